@@ -0,0 +1,112 @@
+/*
+Copyright 2021. Alexis de Talhouët
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// MountPointReference refers to a MountPoint, possibly in another namespace
+type MountPointReference struct {
+	// The namespace of the MountPoint. By default, the namespace of the referring object.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// The name of the MountPoint
+	Name string `json:"name"`
+}
+
+// CallHomeDevicePhase is the approval state of a device calling home
+type CallHomeDevicePhase string
+
+const (
+	PendingCallHomeDevice  CallHomeDevicePhase = "Pending"
+	ApprovedCallHomeDevice CallHomeDevicePhase = "Approved"
+)
+
+// CallHomeDeviceSpec defines the desired state of CallHomeDevice
+type CallHomeDeviceSpec struct {
+	// Whether the device is allowed to call home. Once approved, the next connections of the device
+	// are bound to `mountPointRef`.
+	// +optional
+	Approved bool `json:"approved,omitempty"`
+	// The MountPoint the connections of the device are bound to, once approved. The MountPoint must
+	// have `callHome` set, and use the same transport.
+	// +optional
+	MountPointRef *MountPointReference `json:"mountPointRef,omitempty"`
+}
+
+// CallHomeDeviceStatus defines the observed state of CallHomeDevice
+type CallHomeDeviceStatus struct {
+	// Either `Pending` or `Approved`
+	Phase CallHomeDevicePhase `json:"phase,omitempty"`
+	// The transport the device called home with, either `ssh` or `tls`
+	Transport TransportType `json:"transport,omitempty"`
+	// The SHA256 fingerprint identifying the device: the fingerprint of its SSH host key,
+	// or of its TLS certificate
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// The SSH host key presented by the device, in the authorized_keys format
+	HostKey string `json:"hostKey,omitempty"`
+	// The TLS certificate presented by the device
+	PeerCertificate *PeerCertificate `json:"peerCertificate,omitempty"`
+	// The address the last connection came from
+	RemoteAddress string `json:"remoteAddress,omitempty"`
+	// First time the device called home
+	FirstSeenTime *metav1.Time `json:"firstSeenTime,omitempty"`
+	// Last time the device called home
+	LastSeenTime *metav1.Time `json:"lastSeenTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Transport",type=string,JSONPath=`.status.transport`
+//+kubebuilder:printcolumn:name="Fingerprint",type=string,JSONPath=`.status.fingerprint`
+//+kubebuilder:printcolumn:name="Remote",type=string,JSONPath=`.status.remoteAddress`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// CallHomeDevice is the Schema for the callhomedevices API. It records a device that called home,
+// and that isn't bound to any MountPoint, so an administrator can approve it.
+type CallHomeDevice struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CallHomeDeviceSpec   `json:"spec,omitempty"`
+	Status CallHomeDeviceStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CallHomeDeviceList contains a list of CallHomeDevice
+type CallHomeDeviceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CallHomeDevice `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CallHomeDevice{}, &CallHomeDeviceList{})
+}
+
+// GetMountPointNamespacedName returns the NamespacedName of the MountPoint the device is bound to.
+func (obj *CallHomeDevice) GetMountPointNamespacedName() types.NamespacedName {
+	namespace := obj.Spec.MountPointRef.Namespace
+	if namespace == "" {
+		namespace = obj.Namespace
+	}
+	return types.NamespacedName{Namespace: namespace, Name: obj.Spec.MountPointRef.Name}
+}
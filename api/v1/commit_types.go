@@ -29,20 +29,122 @@ type CommitSpec struct {
 	// defaults to 1 seconds
 	// +kubebuilder:default:=1
 	Timeout int32 `json:"timeout,omitempty"`
-	// If this Commit operation should occur after another operation, specify the other operation here.
-	DependsOn DependsOn `json:"dependsOn,omitempty"`
+	// If this Commit operation should occur after other operations, specify them here. They must all be Ready.
+	// +kubebuilder:validation:Type=""
+	// +kubebuilder:pruning:PreserveUnknownFields
+	DependsOn Dependencies `json:"dependsOn,omitempty"`
+	// Performs a confirmed commit (RFC 6241, section 8.4): the NETCONF server rolls the changes back unless
+	// they are confirmed within the confirm timeout, which only happens once the health check passes.
+	// Requires the :confirmed-commit:1.1 capability.
+	// +optional
+	Confirmed *ConfirmedCommit `json:"confirmed,omitempty"`
+	// Retries the Commit operation once it failed with a transient rpc-error, e.g. a lock denied
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+	// Executes the Commit operation again, or deletes it, once completed
+	ExecutionPolicy `json:",inline"`
+}
+
+// ConfirmedCommit defines how a confirmed commit is performed, and checked before being confirmed
+type ConfirmedCommit struct {
+	// The time, in seconds, the NETCONF server waits for the confirming commit before rolling back.
+	// Default to 600 seconds.
+	// +kubebuilder:default:=600
+	// +kubebuilder:validation:Minimum=1
+	ConfirmTimeout int32 `json:"confirmTimeout,omitempty"`
+	// The persist token, allowing the commit to be confirmed, or cancelled, from another session, e.g. once
+	// the session was re-established following the changes. Default to a token derived from the Commit UID.
+	// +optional
+	Persist string `json:"persist,omitempty"`
+	// The health check to pass before confirming the commit. By default, only the NETCONF session is checked.
+	// +optional
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
+	// What to do when the health check fails: `cancel` sends a cancel-commit, `timeout` lets the NETCONF
+	// server roll back once the confirm timeout expires. Default to `cancel`.
+	// +kubebuilder:validation:Enum=cancel;timeout
+	// +kubebuilder:default:="cancel"
+	OnFailure RollbackPolicy `json:"onFailure,omitempty"`
+}
+
+// HealthCheck is run after a confirmed commit. The NETCONF session must still be reachable, and when a filter
+// is defined, the reply of a get using this filter must hold the expected data.
+type HealthCheck struct {
+	// The delay, in seconds, between the confirmed commit and the first health check. Default to 10 seconds.
+	// +kubebuilder:default:=10
+	InitialDelay int32 `json:"initialDelay,omitempty"`
+	// The interval, in seconds, between two attempts. Default to 10 seconds.
+	// +kubebuilder:default:=10
+	Interval int32 `json:"interval,omitempty"`
+	// The number of attempts before the health check is considered as failed. Default to 3.
+	// +kubebuilder:default:=3
+	// +kubebuilder:validation:Minimum=1
+	Attempts int32 `json:"attempts,omitempty"`
+	// The subtree filter of the get
+	// +optional
+	Filter string `json:"filter,omitempty"`
+	// The data the reply of the get must hold, compared semantically: the order of the elements, and the data
+	// not defined here, are ignored
+	// +optional
+	Expect string `json:"expect,omitempty"`
+}
+
+// RollbackPolicy defines how a confirmed commit failing its health check is rolled back
+type RollbackPolicy string
+
+const (
+	CancelRollbackPolicy  RollbackPolicy = "cancel"
+	TimeoutRollbackPolicy RollbackPolicy = "timeout"
+)
+
+// ConfirmedCommitPhase is the progress of a confirmed commit
+type ConfirmedCommitPhase string
+
+const (
+	// ConfirmingPhase is used once the confirmed commit is sent, while the health check is performed.
+	ConfirmingPhase ConfirmedCommitPhase = "Confirming"
+	// ConfirmedPhase is used once the health check passed, and the confirming commit was sent.
+	ConfirmedPhase ConfirmedCommitPhase = "Confirmed"
+	// CancelledPhase is used once the health check failed, and the cancel-commit was sent.
+	CancelledPhase ConfirmedCommitPhase = "Cancelled"
+	// AwaitingRollbackPhase is used once the health check failed, until the confirm timeout expires.
+	AwaitingRollbackPhase ConfirmedCommitPhase = "AwaitingRollback"
+	// RolledBackPhase is used once the confirm timeout expired without the commit being confirmed.
+	RolledBackPhase ConfirmedCommitPhase = "RolledBack"
+)
+
+// CommitStatus defines the observed state of Commit
+type CommitStatus struct {
+	RPCStatus `json:",inline"`
+	// The progress of the confirmed commit: `Confirming`, `Confirmed`, `Cancelled`, `AwaitingRollback` or
+	// `RolledBack`
+	ConfirmedCommitPhase ConfirmedCommitPhase `json:"confirmedCommitPhase,omitempty"`
+	// The generation the confirmed commit was performed for
+	ConfirmedCommitGeneration int64 `json:"confirmedCommitGeneration,omitempty"`
+	// The persist token of the confirmed commit
+	PersistID string `json:"persistID,omitempty"`
+	// Time the confirmed commit was sent
+	ConfirmedCommitTime *metav1.Time `json:"confirmedCommitTime,omitempty"`
+	// Time the NETCONF server rolls the changes back, unless confirmed
+	ConfirmDeadline *metav1.Time `json:"confirmDeadline,omitempty"`
+	// Number of health check attempts
+	HealthCheckAttempts int32 `json:"healthCheckAttempts,omitempty"`
+	// The outcome of the last health check attempt
+	HealthCheckMessage string `json:"healthCheckMessage,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.confirmedCommitPhase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Commit is the Schema for the commits API
 type Commit struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec      CommitSpec `json:"spec,omitempty"`
-	RPCStatus `json:"status,omitempty"`
+	Spec         CommitSpec `json:"spec,omitempty"`
+	CommitStatus `json:"status,omitempty"`
 }
 
 //+kubebuilder:object:root=true
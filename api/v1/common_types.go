@@ -1,12 +1,14 @@
 package v1
 
 import (
+	"bytes"
+	"encoding/json"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // DependsOn allows to specify a dependency for the operation to execute.
 //If such dependency is not met, and/or if the underlying dependency isn't
-//reporting success, the operation will fail.
+//reporting the Ready condition, the operation is pending, as reported by the DependencyBlocked condition.
 type DependsOn struct {
 	// Any of the Kind supported by netconf.openshift-telco.io/v1 Group
 	Kind string `json:"kind,omitempty"`
@@ -18,6 +20,86 @@ func (d *DependsOn) IsNil() bool {
 	return len(d.Kind) == 0 || len(d.Name) == 0
 }
 
+// Dependencies are the operations an operation depends on. For backward compatibility, a single dependency is also
+// accepted: the CRDs leave the dependsOn field untyped, so that the operations stored with it remain valid.
+type Dependencies []DependsOn
+
+// UnmarshalJSON decodes either a list of dependencies, or a single one. As the API server doesn't check the type of
+// dependsOn, any other value is decoded as a dependency of an unsupported kind, rather than failing to decode the
+// whole operation.
+func (d *Dependencies) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")):
+		*d = nil
+		return nil
+	case trimmed[0] == '{':
+		var dependency DependsOn
+		if err := json.Unmarshal(trimmed, &dependency); err != nil {
+			return err
+		}
+		*d = nil
+		if !dependency.IsNil() {
+			*d = Dependencies{dependency}
+		}
+		return nil
+	case trimmed[0] == '[':
+		return json.Unmarshal(trimmed, (*[]DependsOn)(d))
+	}
+	*d = Dependencies{{Kind: string(trimmed)}}
+	return nil
+}
+
+// RetryPolicy defines how the operation is retried once it failed with a transient rpc-error, e.g. because the
+// datastore is locked by another session. The delay between two attempts doubles upon each retry.
+type RetryPolicy struct {
+	// The maximum number of attempts, the first one included. Default to 3.
+	// +kubebuilder:default:=3
+	// +kubebuilder:validation:Minimum=1
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+	// The delay, in seconds, before the first retry. Default to 5 seconds.
+	// +kubebuilder:default:=5
+	// +kubebuilder:validation:Minimum=1
+	Backoff int32 `json:"backoff,omitempty"`
+	// The maximum delay, in seconds, between two attempts. Default to 300 seconds.
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum=1
+	MaxBackoff int32 `json:"maxBackoff,omitempty"`
+	// The percentage the delay is randomly increased or decreased by, so that the operations competing for the
+	// same datastore don't retry in lockstep. Default to 20.
+	// +kubebuilder:default:=20
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Jitter int32 `json:"jitter,omitempty"`
+	// The error-tags of the rpc-errors the operation is retried upon. Default to `lock-denied`, `in-use` and
+	// `resource-denied`.
+	// +optional
+	RetryOn []string `json:"retryOn,omitempty"`
+}
+
+// RetryStatus is the progress of the attempts of the operation, when a retry policy is set
+type RetryStatus struct {
+	// The generation the attempts were made for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// The number of attempts, the last one included
+	Attempts int32 `json:"attempts,omitempty"`
+	// Time the operation is retried, following a transient failure
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
+}
+
+// ExecutionPolicy defines when a one-shot operation is executed again, and when it is deleted, once completed. The
+// operation is executed once per spec: neither a reconciliation nor a restart of the operator executes it again.
+type ExecutionPolicy struct {
+	// An identifier of the run: changing it executes the operation again, even though the rest of the spec is
+	// unchanged. Setting the `netconf.openshift-telco.io/run-id` annotation has the same effect.
+	// +optional
+	RunID string `json:"runID,omitempty"`
+	// How long, in seconds, the operation is kept once completed, before being deleted. By default, it is kept.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
+
 type KafkaSink struct {
 	Enabled       bool   `json:"enabled"`
 	Topic         string `json:"topic"`
@@ -32,14 +114,75 @@ type RPCStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
-	// Either `success` or `failed`
-	Status string `json:"status,omitempty"`
 	// Provides the received RPC reply
 	RpcReply string `json:"rpcReply,omitempty"`
+	// The category of the last failure: `RPCError` when the NETCONF server replied with an rpc-error,
+	// `Timeout` when no reply was received in time, `Transport` when the RPC couldn't be sent or its reply received
+	ErrorCategory ErrorCategory `json:"errorCategory,omitempty"`
+	// The rpc-errors, warnings included, the NETCONF server replied with
+	RPCErrors []RPCError `json:"rpcErrors,omitempty"`
+	// The data of the received RPC reply, translated into JSON (RFC 7951), when the reply format is `json`
+	RpcReplyJSON string `json:"rpcReplyJSON,omitempty"`
 	// Provide the list of supported capabilities
 	Capabilities []string `json:"capabilities,omitempty"`
 	// In case of a notification, keep track of the subscription-id
 	SubscriptionID string `json:"subscriptionID,omitempty"`
+	// The attempts of the operation, when a retry policy is set
+	Retry *RetryStatus `json:"retry,omitempty"`
+	// The hash of the spec the one-shot operation was last executed for, along with its run-id annotation
+	SpecHash string `json:"specHash,omitempty"`
+	// Time the one-shot operation completed the execution of its spec, either successfully, or failing for good
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+}
+
+// YANGMapping complements the mapping of the YANG modules to their XML namespace, derived from the capabilities
+// advertised by the NETCONF server, used to convert JSON-encoded YANG data (RFC 7951) into XML, and back.
+type YANGMapping struct {
+	// The XML namespaces, by YANG module name, e.g. `ietf-interfaces: urn:ietf:params:xml:ns:yang:ietf-interfaces`
+	// +optional
+	Namespaces map[string]string `json:"namespaces,omitempty"`
+	// The keys of the lists, by qualified list name, e.g. `ietf-interfaces:interface: [name]`. As the API server
+	// sorts the members of the JSON objects, the keys are written first within each list entry, as YANG requires.
+	// When a list isn't defined, the `name` member is written first. The keys also identify the list entries when
+	// comparing configurations, e.g. to detect drift: the entries of a list that isn't defined are identified by
+	// their first leaf, and a list defined without keys, e.g. `example:system: []`, declares a container.
+	// +optional
+	ListKeys map[string][]string `json:"listKeys,omitempty"`
+}
+
+// ReplyFormat defines the format the data of the RPC reply is reported in
+type ReplyFormat string
+
+const (
+	XMLReplyFormat  ReplyFormat = "xml"
+	JSONReplyFormat ReplyFormat = "json"
+)
+
+// ErrorCategory classifies the failure of an RPC
+type ErrorCategory string
+
+const (
+	RPCErrorCategory       ErrorCategory = "RPCError"
+	TimeoutErrorCategory   ErrorCategory = "Timeout"
+	TransportErrorCategory ErrorCategory = "Transport"
+)
+
+// RPCError is an rpc-error the NETCONF server replied with (RFC 6241, section 4.3)
+type RPCError struct {
+	// The conceptual layer the error occurred at: `transport`, `rpc`, `protocol` or `application`
+	Type string `json:"type,omitempty"`
+	// The error-tag identifying the error, e.g. `lock-denied` or `data-missing`
+	Tag string `json:"tag"`
+	// Either `error` or `warning`
+	Severity string `json:"severity,omitempty"`
+	// The data-model-specific or implementation-specific error condition
+	AppTag string `json:"appTag,omitempty"`
+	// The XPath expression of the element associated with the error
+	Path string `json:"path,omitempty"`
+	// The human-readable description of the error
+	Message string `json:"message,omitempty"`
+	// The content of the error-info element, e.g. the session-id holding the lock, in XML
+	Info string `json:"info,omitempty"`
 }
 
 func (obj *RPCStatus) GetConditions() []metav1.Condition {
@@ -49,3 +192,129 @@ func (obj *RPCStatus) GetConditions() []metav1.Condition {
 func (obj *RPCStatus) SetConditions(reconcileStatus []metav1.Condition) {
 	obj.Conditions = reconcileStatus
 }
+
+func (obj *RPCStatus) GetCompletedAt() *metav1.Time {
+	return obj.CompletedAt
+}
+
+// Conditions reported by the netconf.openshift-telco.io/v1 kinds. Each condition carries the generation
+// it was observed for, so `kubectl wait --for=condition=<type>` can be relied upon.
+const (
+	// ReadyCondition is reported by every kind. It is true once the MountPoint session is established,
+	// or once the operation was successfully executed, or the subscription created.
+	ReadyCondition = "Ready"
+	// ConnectedCondition is reported by the MountPoint. It is true while the NETCONF session is established.
+	ConnectedCondition = "Connected"
+	// AppliedCondition is reported by the operations, e.g. EditConfig, Lock or RPC. It is true once the
+	// operation was successfully executed by the NETCONF server.
+	AppliedCondition = "Applied"
+	// CommittedCondition is reported by the Commit, and by the EditConfig requesting a commit. It is true
+	// once the candidate datastore was successfully committed.
+	CommittedCondition = "Committed"
+	// SubscribedCondition is reported by the CreateSubscription and the EstablishSubscription. It is true
+	// while the subscription is registered against the MountPoint session.
+	SubscribedCondition = "Subscribed"
+	// DependencyBlockedCondition is reported by the operations having a dependency. It is true while
+	// the dependency doesn't exist, or isn't Ready.
+	DependencyBlockedCondition = "DependencyBlocked"
+	// DriftedCondition is reported by the EditConfig having a drift policy. It is true while the configuration
+	// of the device differs from the XML payload.
+	DriftedCondition = "Drifted"
+	// BackedUpCondition is reported by the ConfigBackup. It is true once the configuration of every MountPoint
+	// was backed up, upon the last schedule.
+	BackedUpCondition = "BackedUp"
+	// PreviewedCondition is reported by the EditConfig in dry-run mode. It is true once the changes the XML
+	// payload would make were previewed, in the current generation.
+	PreviewedCondition = "Previewed"
+)
+
+// Reasons of the conditions, not related to the NETCONF rpc-error.
+const (
+	// SucceededReason is used when the operation, or the subscription, succeeded.
+	SucceededReason = "Succeeded"
+	// FailedReason is used when the operation failed for a reason other than an rpc-error, a timeout, or a
+	// transport failure.
+	FailedReason = "Failed"
+	// MountPointUnavailableReason is used when the MountPoint doesn't exist, or has no established session.
+	MountPointUnavailableReason = "MountPointUnavailable"
+	// TimeoutReason is used when no rpc-reply was received within the timeout of the operation.
+	TimeoutReason = "Timeout"
+	// TransportErrorReason is used when the RPC couldn't be sent, or its reply received.
+	TransportErrorReason = "TransportError"
+	// ConnectionFailedReason is used when the NETCONF session couldn't be established.
+	ConnectionFailedReason = "ConnectionFailed"
+	// DependencyReadyReason is used when the dependency is Ready.
+	DependencyReadyReason = "DependencyReady"
+	// DependencyNotFoundReason is used when the dependency doesn't exist.
+	DependencyNotFoundReason = "DependencyNotFound"
+	// DependencyNotReadyReason is used when the dependency isn't Ready.
+	DependencyNotReadyReason = "DependencyNotReady"
+	// DependencyFailedReason is used when the dependency failed for good: its retries are exhausted, or the
+	// one-shot operation completed without being Ready.
+	DependencyFailedReason = "DependencyFailed"
+	// InvalidDependencyReason is used when the dependency kind isn't supported.
+	InvalidDependencyReason = "InvalidDependency"
+	// PendingReason is used while a dependency doesn't exist, or isn't Ready. The operation is started once every
+	// dependency is Ready.
+	PendingReason = "Pending"
+	// InSyncReason is used when the configuration of the device matches the XML payload.
+	InSyncReason = "InSync"
+	// ConfigurationDriftedReason is used when the configuration of the device differs from the XML payload.
+	ConfigurationDriftedReason = "ConfigurationDrifted"
+	// DriftCorrectedReason is used when the configuration was re-applied following a drift.
+	DriftCorrectedReason = "DriftCorrected"
+	// ConfirmationPendingReason is used while the health check of a confirmed commit is performed.
+	ConfirmationPendingReason = "ConfirmationPending"
+	// CommitCancelledReason is used when the health check failed, and the confirmed commit was cancelled.
+	CommitCancelledReason = "CommitCancelled"
+	// HealthCheckFailedReason is used when the health check failed, until the confirm timeout expires.
+	HealthCheckFailedReason = "HealthCheckFailed"
+	// ValidationFailedReason is used when the validation of the edited datastore failed, and the changes were
+	// discarded.
+	ValidationFailedReason = "ValidationFailed"
+	// InvalidScheduleReason is used when the schedule, or a maintenance window, isn't in the cron format.
+	InvalidScheduleReason = "InvalidSchedule"
+	// GitCommitFailedReason is used when the Job committing the backups into the Git repository failed.
+	GitCommitFailedReason = "GitCommitFailed"
+	// SourcePendingReason is used while the configuration to restore is being read from the Git repository.
+	SourcePendingReason = "SourcePending"
+	// RolledBackReason is used when the confirm timeout expired without the commit being confirmed.
+	RolledBackReason = "RolledBack"
+	// DryRunReason is used when the EditConfig is in dry-run mode, so nothing was applied.
+	DryRunReason = "DryRun"
+	// RetryingReason is used once the operation failed with a transient rpc-error, until it is retried.
+	RetryingReason = "Retrying"
+	// RetriesExhaustedReason is used when the operation is no longer retried, as the attempts are exhausted.
+	RetriesExhaustedReason = "RetriesExhausted"
+	// RunStartedReason is used when the ScheduledOperation created the operation of a run.
+	RunStartedReason = "RunStarted"
+	// RunSkippedReason is used when a run was skipped, as the operation of a previous run was still active.
+	RunSkippedReason = "RunSkipped"
+	// RunReplacedReason is used when the operation of a previous run was deleted, to start the next run.
+	RunReplacedReason = "RunReplaced"
+)
+
+// Reasons of the conditions, used when the NETCONF server replied with an rpc-error. The reason is derived from the
+// error-tag of the first rpc-error (RFC 6241, Appendix A), e.g. `lock-denied` is reported as `LockDenied`.
+const (
+	InUseReason                 = "InUse"
+	InvalidValueReason          = "InvalidValue"
+	TooBigReason                = "TooBig"
+	MissingAttributeReason      = "MissingAttribute"
+	BadAttributeReason          = "BadAttribute"
+	UnknownAttributeReason      = "UnknownAttribute"
+	MissingElementReason        = "MissingElement"
+	BadElementReason            = "BadElement"
+	UnknownElementReason        = "UnknownElement"
+	UnknownNamespaceReason      = "UnknownNamespace"
+	AccessDeniedReason          = "AccessDenied"
+	LockDeniedReason            = "LockDenied"
+	ResourceDeniedReason        = "ResourceDenied"
+	RollbackFailedReason        = "RollbackFailed"
+	DataExistsReason            = "DataExists"
+	DataMissingReason           = "DataMissing"
+	OperationNotSupportedReason = "OperationNotSupported"
+	OperationFailedReason       = "OperationFailed"
+	PartialOperationReason      = "PartialOperation"
+	MalformedMessageReason      = "MalformedMessage"
+)
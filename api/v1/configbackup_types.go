@@ -0,0 +1,145 @@
+/*
+Copyright 2021. Alexis de Talhouët
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ConfigBackupSpec defines the desired state of ConfigBackup
+type ConfigBackupSpec struct {
+	// The MountPoints whose configuration is backed up
+	// +kubebuilder:validation:MinItems=1
+	MountPoints []string `json:"mountPoints"`
+	// Timeout defines the timeout for the NETCONF transaction
+	// defaults to 30 seconds
+	// +kubebuilder:default:=30
+	Timeout int32 `json:"timeout,omitempty"`
+	// The datastore to back up: `running`, `candidate` or `startup`. Default to `running`.
+	// +kubebuilder:validation:Enum=running;candidate;startup
+	// +kubebuilder:default:="running"
+	Source string `json:"source,omitempty"`
+	// The subtree filter selecting the configuration to back up. By default, the whole datastore is backed up.
+	// +optional
+	Filter string `json:"filter,omitempty"`
+	// The schedule of the backups, in the cron format, e.g. `0 2 * * *`, or `@daily`. Times are in UTC.
+	Schedule string `json:"schedule"`
+	// Whether the scheduled backups are suspended
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// Where the backups are stored
+	Storage BackupStorage `json:"storage"`
+}
+
+// BackupStorage defines where the backups are stored. Exactly one of them must be set.
+type BackupStorage struct {
+	// Stores each version in a ConfigMap
+	// +optional
+	ConfigMap *ConfigMapBackupStorage `json:"configMap,omitempty"`
+	// Commits each version into a Git repository, held by a PersistentVolumeClaim
+	// +optional
+	Git *GitBackupStorage `json:"git,omitempty"`
+}
+
+// ConfigMapBackupStorage stores each version of the configuration in a ConfigMap
+type ConfigMapBackupStorage struct {
+	// The number of versions kept per MountPoint. Default to 10.
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum=1
+	Retention int32 `json:"retention,omitempty"`
+}
+
+// GitBackupStorage commits each version of the configuration into a Git repository. The commit is performed by a
+// Job, mounting the PersistentVolumeClaim.
+type GitBackupStorage struct {
+	// The PersistentVolumeClaim holding the Git repository. The repository is initialized when missing.
+	PersistentVolumeClaimName string `json:"persistentVolumeClaimName"`
+	// The directory of the repository, within the volume. Default to its root.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// The image of the Job, providing `sh` and `git`. Default to `docker.io/alpine/git:latest`.
+	// +kubebuilder:default:="docker.io/alpine/git:latest"
+	Image string `json:"image,omitempty"`
+}
+
+// ConfigBackupStatus defines the observed state of ConfigBackup
+type ConfigBackupStatus struct {
+	RPCStatus `json:",inline"`
+	// Last time the backups were scheduled
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// Next time the backups are scheduled
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+	// The last backup of each MountPoint
+	MountPoints []MountPointBackup `json:"mountPoints,omitempty"`
+	// The last Job committing the backups into the Git repository
+	GitJob string `json:"gitJob,omitempty"`
+}
+
+// MountPointBackup reports the last backup of a MountPoint
+type MountPointBackup struct {
+	MountPoint string `json:"mountPoint"`
+	// Last time the configuration was backed up, whether it changed or not
+	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
+	// Last time a new version of the configuration was stored
+	LastChangeTime *metav1.Time `json:"lastChangeTime,omitempty"`
+	// The size, in bytes, of the configuration
+	Size int64 `json:"size,omitempty"`
+	// The SHA256 hash of the configuration
+	Hash string `json:"hash,omitempty"`
+	// Where the last version is stored: the name of the ConfigMap, or the path within the Git repository
+	Location string `json:"location,omitempty"`
+	// Why the last backup failed, if it did
+	Error string `json:"error,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+//+kubebuilder:printcolumn:name="Last Schedule",type=date,JSONPath=`.status.lastScheduleTime`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ConfigBackup is the Schema for the configbackups API
+type ConfigBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec               ConfigBackupSpec `json:"spec,omitempty"`
+	ConfigBackupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ConfigBackupList contains a list of ConfigBackup
+type ConfigBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ConfigBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ConfigBackup{}, &ConfigBackupList{})
+}
+
+func (obj *ConfigBackup) GetMountPointNamespacedName(mountpoint string) string {
+	return types.NamespacedName{Namespace: obj.Namespace, Name: mountpoint}.String()
+}
+
+func (obj *ConfigBackup) GetNamespacedName() string {
+	return types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}.String()
+}
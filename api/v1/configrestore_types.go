@@ -0,0 +1,148 @@
+/*
+Copyright 2021. Alexis de Talhouët
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ConfigRestoreSpec defines the desired state of ConfigRestore
+type ConfigRestoreSpec struct {
+	// Defines the NETCONF session to use
+	MountPoint string `json:"mountPoint"`
+	// Timeout defines the timeout for the NETCONF transaction
+	// defaults to 30 seconds
+	// +kubebuilder:default:=30
+	Timeout int32 `json:"timeout,omitempty"`
+	// The configuration to restore, e.g. a version stored by a ConfigBackup
+	Source RestoreSource `json:"source"`
+	// How the configuration is pushed: `editConfig` uses an edit-config with the `replace` default operation,
+	// `copyConfig` uses a copy-config, both replacing the whole datastore. `merge` uses an edit-config with the
+	// `merge` default operation, leaving the rest of the datastore untouched: it is required to restore a
+	// ConfigBackup version taken using a filter. Default to `editConfig`.
+	// +kubebuilder:validation:Enum=editConfig;copyConfig;merge
+	// +kubebuilder:default:="editConfig"
+	Method RestoreMethod `json:"method,omitempty"`
+	// Identify the datastore to restore: `running`, `candidate`, or `startup` using `copyConfig`. The datastore is
+	// locked during the restore and, for `candidate`, committed. Default to `candidate`.
+	// +kubebuilder:validation:Enum=running;candidate;startup
+	// +kubebuilder:default:="candidate"
+	Target string `json:"target,omitempty"`
+	// Commits the `candidate` datastore using a confirmed commit, confirmed once the health check passes.
+	// Requires the :confirmed-commit:1.1 capability.
+	// +optional
+	Confirmed *ConfirmedCommit `json:"confirmed,omitempty"`
+	// If this ConfigRestore operation should occur after other operations, specify them here. They must all be Ready.
+	// +kubebuilder:validation:Type=""
+	// +kubebuilder:pruning:PreserveUnknownFields
+	DependsOn Dependencies `json:"dependsOn,omitempty"`
+	// Retries the ConfigRestore operation once it failed with a transient rpc-error, e.g. a lock denied
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+	// Executes the ConfigRestore operation again, or deletes it, once completed
+	ExecutionPolicy `json:",inline"`
+}
+
+// RestoreMethod defines how the configuration is pushed
+type RestoreMethod string
+
+const (
+	EditConfigRestoreMethod RestoreMethod = "editConfig"
+	CopyConfigRestoreMethod RestoreMethod = "copyConfig"
+	MergeRestoreMethod      RestoreMethod = "merge"
+)
+
+// RestoreSource identifies the configuration to restore, as the content of the `config` element. Exactly one of
+// them must be set.
+type RestoreSource struct {
+	// The key of a ConfigMap holding the configuration, e.g. the `config.xml` key of a ConfigBackup version
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// A revision of a file in a Git repository, e.g. committed by a ConfigBackup
+	// +optional
+	Git *GitRevision `json:"git,omitempty"`
+}
+
+// GitRevision identifies a revision of a file in a Git repository, held by a PersistentVolumeClaim. The file is
+// read by a Job, mounting the PersistentVolumeClaim.
+type GitRevision struct {
+	// The PersistentVolumeClaim holding the Git repository
+	PersistentVolumeClaimName string `json:"persistentVolumeClaimName"`
+	// The directory of the repository, within the volume. Default to its root.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// The path of the file, within the repository, e.g. `csr1kv-mountpoint.xml`
+	File string `json:"file"`
+	// The revision of the file, e.g. a commit hash, or `HEAD~1`. Default to `HEAD`.
+	// +kubebuilder:default:="HEAD"
+	Revision string `json:"revision,omitempty"`
+	// The image of the Job, providing `sh` and `git`. Default to `docker.io/alpine/git:latest`.
+	// +kubebuilder:default:="docker.io/alpine/git:latest"
+	Image string `json:"image,omitempty"`
+}
+
+// ConfigRestoreStatus defines the observed state of ConfigRestore
+type ConfigRestoreStatus struct {
+	CommitStatus `json:",inline"`
+	// The Job reading the Git revision
+	SourceJob string `json:"sourceJob,omitempty"`
+	// The Git commit the Git revision resolved to
+	Revision string `json:"revision,omitempty"`
+	// The SHA256 hash of the restored configuration
+	Hash string `json:"hash,omitempty"`
+	// Time the configuration was pushed
+	RestoreTime *metav1.Time `json:"restoreTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.spec.target`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.confirmedCommitPhase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ConfigRestore is the Schema for the configrestores API
+type ConfigRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec                ConfigRestoreSpec `json:"spec,omitempty"`
+	ConfigRestoreStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ConfigRestoreList contains a list of ConfigRestore
+type ConfigRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ConfigRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ConfigRestore{}, &ConfigRestoreList{})
+}
+
+func (obj *ConfigRestore) GetMountPointNamespacedName(mountpoint string) string {
+	return types.NamespacedName{Namespace: obj.Namespace, Name: mountpoint}.String()
+}
+
+func (obj *ConfigRestore) GetNamespacedName() string {
+	return types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}.String()
+}
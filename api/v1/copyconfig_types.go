@@ -0,0 +1,103 @@
+/*
+Copyright 2021. Alexis de Talhouët
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// CopyConfigSpec defines the desired state of CopyConfig
+type CopyConfigSpec struct {
+	// Defines the NETCONF session to use
+	MountPoint string `json:"mountPoint"`
+	// Timeout defines the timeout for the NETCONF transaction
+	// defaults to 1 seconds
+	// +kubebuilder:default:=1
+	Timeout int32 `json:"timeout,omitempty"`
+	// The configuration to replace: either a datastore, or a URL.
+	Target ConfigTarget `json:"target"`
+	// The configuration to copy: either a datastore, a URL, or an inline configuration.
+	Source ConfigSource `json:"source"`
+	// If this CopyConfig operation should occur after other operations, specify them here. They must all be Ready.
+	// +kubebuilder:validation:Type=""
+	// +kubebuilder:pruning:PreserveUnknownFields
+	DependsOn Dependencies `json:"dependsOn,omitempty"`
+	// Retries the CopyConfig operation once it failed with a transient rpc-error, e.g. a lock denied
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+	// Executes the CopyConfig operation again, or deletes it, once completed
+	ExecutionPolicy `json:",inline"`
+}
+
+// ConfigTarget identifies a configuration, either by its datastore, or by its URL. Exactly one of them must be set.
+type ConfigTarget struct {
+	// The datastore: `running`, `candidate` or `startup`. The latter requires the :startup capability.
+	// +kubebuilder:validation:Enum=running;candidate;startup
+	// +optional
+	Datastore string `json:"datastore,omitempty"`
+	// The URL of the configuration, e.g. `ftp://server/config.xml`. Requires the :url capability.
+	// +optional
+	URL string `json:"url,omitempty"`
+}
+
+// ConfigSource identifies the configuration to copy: a datastore, a URL, or an inline configuration, possibly read
+// from a ConfigMap. Exactly one of them must be set.
+type ConfigSource struct {
+	ConfigTarget `json:",inline"`
+	// The configuration, as the content of the `config` element, i.e. the top-level elements of the data models.
+	// +optional
+	Config string `json:"config,omitempty"`
+	// The key of a ConfigMap holding the configuration, as the content of the `config` element, e.g. a golden
+	// configuration.
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// CopyConfig is the Schema for the copyconfigs API
+type CopyConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec      CopyConfigSpec `json:"spec,omitempty"`
+	RPCStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CopyConfigList contains a list of CopyConfig
+type CopyConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CopyConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CopyConfig{}, &CopyConfigList{})
+}
+
+func (obj *CopyConfig) GetMountPointNamespacedName(mountpoint string) string {
+	return types.NamespacedName{Namespace: obj.Namespace, Name: mountpoint}.String()
+}
+
+func (obj *CopyConfig) GetNamespacedName() string {
+	return types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}.String()
+}
@@ -37,6 +37,9 @@ type CreateSubscriptionSpec struct {
 	StopTime string `json:"stopTime,omitempty"`
 	// Used to forward received notification to kafka
 	KafkaSink KafkaSink `json:"kafkaSink,omitempty"`
+	// Retries the CreateSubscription operation once it failed with a transient rpc-error, e.g. a lock denied
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,76 @@
+/*
+Copyright 2021. Alexis de Talhouët
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DeleteConfigSpec defines the desired state of DeleteConfig
+type DeleteConfigSpec struct {
+	// Defines the NETCONF session to use
+	MountPoint string `json:"mountPoint"`
+	// Timeout defines the timeout for the NETCONF transaction
+	// defaults to 1 seconds
+	// +kubebuilder:default:=1
+	Timeout int32 `json:"timeout,omitempty"`
+	// The configuration to delete: either a datastore, or a URL. The `running` datastore can't be deleted.
+	Target ConfigTarget `json:"target"`
+	// If this DeleteConfig operation should occur after other operations, specify them here. They must all be Ready.
+	// +kubebuilder:validation:Type=""
+	// +kubebuilder:pruning:PreserveUnknownFields
+	DependsOn Dependencies `json:"dependsOn,omitempty"`
+	// Retries the DeleteConfig operation once it failed with a transient rpc-error, e.g. a lock denied
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+	// Executes the DeleteConfig operation again, or deletes it, once completed
+	ExecutionPolicy `json:",inline"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// DeleteConfig is the Schema for the deleteconfigs API
+type DeleteConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec      DeleteConfigSpec `json:"spec,omitempty"`
+	RPCStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DeleteConfigList contains a list of DeleteConfig
+type DeleteConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DeleteConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DeleteConfig{}, &DeleteConfigList{})
+}
+
+func (obj *DeleteConfig) GetMountPointNamespacedName(mountpoint string) string {
+	return types.NamespacedName{Namespace: obj.Namespace, Name: mountpoint}.String()
+}
+
+func (obj *DeleteConfig) GetNamespacedName() string {
+	return types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}.String()
+}
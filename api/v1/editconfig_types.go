@@ -17,7 +17,9 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -36,31 +38,160 @@ type EditConfigSpec struct {
 	// Identify the datastore against which the operation should be performed. Default to `candidate`.
 	// +kubebuilder:default:="candidate"
 	Target string `json:"target,omitempty"`
-	// Define the XML payload to sent
-	XML string `json:"xml"`
-	// If this EditConfig operation should occur after another operation, specify the other operation here.
-	DependsOn DependsOn `json:"dependsOn,omitempty"`
+	// Define the XML payload to sent. Exactly one of xml, template and json must be set.
+	// +optional
+	XML string `json:"xml,omitempty"`
+	// Define the XML payload as a Go template (text/template), e.g. `<vlan-id>{{ .Parameters.vlan }}</vlan-id>`.
+	// The template is rendered using the parameters, under `.Parameters`, and the MountPoint, under `.MountPoint`,
+	// e.g. `{{ index .MountPoint.Labels "site" }}`. It is re-rendered whenever one of them changes, and the payload
+	// re-applied when the rendering differs.
+	// +optional
+	Template string `json:"template,omitempty"`
+	// The parameters of the template
+	// +optional
+	Parameters []TemplateParameter `json:"parameters,omitempty"`
+	// Define the payload as JSON-encoded YANG data (RFC 7951), e.g. `ietf-interfaces:interfaces: {...}`, converted
+	// into XML using the YANG modules advertised by the NETCONF server, and the YANG mapping.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	// +optional
+	JSON *runtime.RawExtension `json:"json,omitempty"`
+	// Complements the mapping of the YANG modules to their XML namespace, used to convert the JSON payload
+	// +optional
+	YANGMapping *YANGMapping `json:"yangMapping,omitempty"`
+	// If this EditConfig operation should occur after other operations, specify them here. They must all be Ready.
+	// +kubebuilder:validation:Type=""
+	// +kubebuilder:pruning:PreserveUnknownFields
+	DependsOn Dependencies `json:"dependsOn,omitempty"`
 	// Whether to lock the specified datastore before doing the edit-config
 	// +kubebuilder:default:=false
 	Lock bool `json:"lock,omitempty"`
 	// Whether to commit the changes.
 	// +kubebuilder:default:=false
 	Commit bool `json:"commit,omitempty"`
+	// Whether to validate the target datastore once edited, before committing the changes. When the validation
+	// fails, the changes are discarded, and the commit isn't performed.
+	// +kubebuilder:default:=false
+	ValidateBeforeCommit bool `json:"validateBeforeCommit,omitempty"`
 	// Whether to unlock the specified datastore before doing the edit-config
 	// +kubebuilder:default:=false
 	Unlock bool `json:"unlock,omitempty"`
+	// How the configuration is reconciled once applied. By default, it is applied once per generation.
+	// With `detect`, the configuration of the device is periodically compared with the XML payload, and
+	// differences are reported by the Drifted condition. With `enforce`, the configuration is also re-applied.
+	// +kubebuilder:validation:Enum=detect;enforce
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+	// The interval, in seconds, between two drift checks. Default to 300 seconds.
+	// +kubebuilder:validation:Minimum=10
+	// +optional
+	DriftCheckInterval int32 `json:"driftCheckInterval,omitempty"`
+	// What to do with the configuration when the EditConfig is deleted. By default, it is left on the device.
+	// With `remove`, the elements the XML payload introduced are removed. With `restore`, the elements it
+	// modified are also restored to the values they had before the EditConfig was applied.
+	// +kubebuilder:validation:Enum=remove;restore
+	// +optional
+	PrunePolicy PrunePolicy `json:"prunePolicy,omitempty"`
+	// Whether to only preview the changes the XML payload would make, without applying them. When the NETCONF
+	// server supports the candidate datastore, the payload is pushed to the candidate datastore, within a lock,
+	// its differences with the running datastore are read back, and the changes are discarded. Otherwise, the
+	// payload is compared with the configuration of the running datastore. The changes are reported in the
+	// dryRunDiff status field, and the dependency isn't waited for. Nothing is applied until dryRun is turned off.
+	// +kubebuilder:default:=false
+	DryRun bool `json:"dryRun,omitempty"`
+	// Retries the EditConfig operation once it failed with a transient rpc-error, e.g. a lock denied
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+}
+
+// TemplateParameter defines a parameter of the template, either by its value, or read from another object of
+// the namespace. Exactly one of value and valueFrom must be set.
+type TemplateParameter struct {
+	// The name of the parameter, e.g. `vlan` for `{{ .Parameters.vlan }}`
+	Name string `json:"name"`
+	// +optional
+	Value string `json:"value,omitempty"`
+	// +optional
+	ValueFrom *ParameterSource `json:"valueFrom,omitempty"`
+}
+
+// ParameterSource defines where the value of a parameter is read from. Exactly one field must be set.
+type ParameterSource struct {
+	// A key of a ConfigMap
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// A key of a Secret
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// A field of another object, e.g. a CR. The operator must be granted the permission to get, list and watch
+	// its kind.
+	// +optional
+	ObjectFieldRef *ObjectFieldSelector `json:"objectFieldRef,omitempty"`
+}
+
+// ObjectFieldSelector selects a field of an object of the namespace
+type ObjectFieldSelector struct {
+	// The API version of the object, e.g. `v1` or `netconf.openshift-telco.io/v1`
+	APIVersion string `json:"apiVersion"`
+	// The kind of the object
+	Kind string `json:"kind"`
+	// The name of the object
+	Name string `json:"name"`
+	// The JSONPath of the field, e.g. `{.spec.vlan}` or `.metadata.labels.site`
+	FieldPath string `json:"fieldPath"`
+}
+
+// PrunePolicy defines how the configuration is pruned when the EditConfig is deleted
+type PrunePolicy string
+
+const (
+	RemovePrunePolicy  PrunePolicy = "remove"
+	RestorePrunePolicy PrunePolicy = "restore"
+)
+
+// DriftPolicy defines how a drift of the configuration of the device is handled
+type DriftPolicy string
+
+const (
+	DetectDriftPolicy  DriftPolicy = "detect"
+	EnforceDriftPolicy DriftPolicy = "enforce"
+)
+
+// EditConfigStatus defines the observed state of EditConfig
+type EditConfigStatus struct {
+	RPCStatus `json:",inline"`
+	// Last time the configuration of the device was compared with the XML payload
+	LastDriftCheckTime *metav1.Time `json:"lastDriftCheckTime,omitempty"`
+	// The differences found by the last drift check, one per line
+	Diff string `json:"diff,omitempty"`
+	// Number of times the configuration was re-applied following a drift
+	DriftCorrections int32 `json:"driftCorrections,omitempty"`
+	// Last time the configuration was re-applied following a drift
+	LastDriftCorrectionTime *metav1.Time `json:"lastDriftCorrectionTime,omitempty"`
+	// The ConfigMap holding the snapshot of the configuration taken before the XML payload was applied,
+	// used to prune the configuration when the EditConfig is deleted
+	PruneSnapshot string `json:"pruneSnapshot,omitempty"`
+	// The changes the XML payload would make to the running configuration, previewed in dry-run mode, one per
+	// line: `+` for an added element, `-` for a removed one, and `~` for a leaf holding another value
+	DryRunDiff string `json:"dryRunDiff,omitempty"`
+	// The SHA-256 hash of the payload, as rendered from the template, last applied or previewed
+	PayloadHash string `json:"payloadHash,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.spec.target`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Drifted",type=string,JSONPath=`.status.conditions[?(@.type=="Drifted")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // EditConfig is the Schema for the editconfigs API
 type EditConfig struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec      EditConfigSpec `json:"spec,omitempty"`
-	RPCStatus `json:"status,omitempty"`
+	Spec             EditConfigSpec `json:"spec,omitempty"`
+	EditConfigStatus `json:"status,omitempty"`
 }
 
 //+kubebuilder:object:root=true
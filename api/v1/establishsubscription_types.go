@@ -33,6 +33,9 @@ type EstablishSubscriptionSpec struct {
 	XML string `json:"xml"`
 	// Used to forward received notification to kafka
 	KafkaSink KafkaSink `json:"kafkaSink,omitempty"`
+	// Retries the EstablishSubscription operation once it failed with a transient rpc-error, e.g. a lock denied
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
 }
 
 //+kubebuilder:object:root=true
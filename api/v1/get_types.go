@@ -33,6 +33,11 @@ type GetSpec struct {
 	FilterType string `json:"filterType,omitempty"`
 	// Define the XML payload to sent
 	FilterXML string `json:"filterXML,omitempty"`
+	// Retries the Get operation once it failed with a transient rpc-error, e.g. a lock denied
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+	// Executes the Get operation again, or deletes it, once completed
+	ExecutionPolicy `json:",inline"`
 }
 
 //+kubebuilder:object:root=true
@@ -18,6 +18,7 @@ package v1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -32,6 +33,27 @@ type GetConfigSpec struct {
 	// Identify the datastore against which the operation should be performed. Default to `running`.
 	// +kubebuilder:default:="running"
 	Target string `json:"target,omitempty"`
+	// Define the subtree filter to apply, in XML
+	// +optional
+	Filter string `json:"filter,omitempty"`
+	// Define the subtree filter to apply, as JSON-encoded YANG data (RFC 7951), `null` selecting a leaf
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	// +optional
+	FilterJSON *runtime.RawExtension `json:"filterJSON,omitempty"`
+	// Complements the mapping of the YANG modules to their XML namespace, used to convert the JSON filter, and
+	// the reply
+	// +optional
+	YANGMapping *YANGMapping `json:"yangMapping,omitempty"`
+	// The format the data of the reply is reported in: with `json`, it is also reported in `status.rpcReplyJSON`
+	// +kubebuilder:validation:Enum=xml;json
+	// +optional
+	ReplyFormat ReplyFormat `json:"replyFormat,omitempty"`
+	// Retries the GetConfig operation once it failed with a transient rpc-error, e.g. a lock denied
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+	// Executes the GetConfig operation again, or deletes it, once completed
+	ExecutionPolicy `json:",inline"`
 }
 
 //+kubebuilder:object:root=true
@@ -32,6 +32,11 @@ type LockSpec struct {
 	// Identify the datastore against which the operation should be performed. Default to `candidate`.
 	// +kubebuilder:default:="candidate"
 	Target string `json:"target,omitempty"`
+	// Retries the Lock operation once it failed with a transient rpc-error, e.g. a lock denied
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+	// Executes the Lock operation again, or deletes it, once completed
+	ExecutionPolicy `json:",inline"`
 }
 
 //+kubebuilder:object:root=true
@@ -17,38 +17,287 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
+// AuthMethod is an SSH authentication method
+// +kubebuilder:validation:Enum=publickey;keyboard-interactive;password
+type AuthMethod string
+
+const (
+	PublicKeyAuthMethod           AuthMethod = "publickey"
+	KeyboardInteractiveAuthMethod AuthMethod = "keyboard-interactive"
+	PasswordAuthMethod            AuthMethod = "password"
+)
+
+// HostKeyPolicyMode defines how the host key presented by the NETCONF server is verified
+// +kubebuilder:validation:Enum=strict;pinned;trustOnFirstUse;insecure
+type HostKeyPolicyMode string
+
+const (
+	StrictHostKeyPolicy          HostKeyPolicyMode = "strict"
+	PinnedHostKeyPolicy          HostKeyPolicyMode = "pinned"
+	TrustOnFirstUseHostKeyPolicy HostKeyPolicyMode = "trustOnFirstUse"
+	InsecureHostKeyPolicy        HostKeyPolicyMode = "insecure"
+)
+
+// HostKeyPolicy defines how to verify the host key presented by the NETCONF server
+type HostKeyPolicy struct {
+	// The verification mode:
+	// `strict` requires the host key to be listed in the known_hosts ConfigMap,
+	// `pinned` requires the host key to match one of the provided fingerprints,
+	// `trustOnFirstUse` records the host key in the status on first connection, and requires it to remain the same,
+	// `insecure` accepts any host key.
+	// +kubebuilder:default:=insecure
+	Mode HostKeyPolicyMode `json:"mode,omitempty"`
+	// Reference to a ConfigMap, within the same namespace, holding a `known_hosts` key in the OpenSSH known_hosts format.
+	// Hashed hostnames, wildcards, `@revoked` and `@cert-authority` markers are supported. Required by the `strict` mode.
+	// +optional
+	KnownHostsConfigMapRef *corev1.LocalObjectReference `json:"knownHostsConfigMapRef,omitempty"`
+	// The SHA256 fingerprints of the accepted host keys, as displayed by `ssh-keygen -l`,
+	// e.g. `SHA256:uNiVztksCsDhcc0u9e8BujQXVUpKZIDTMczCvj3tD2s`. Required by the `pinned` mode.
+	// +optional
+	Fingerprints []string `json:"fingerprints,omitempty"`
+}
+
+// TransportType is the transport used to carry the NETCONF session
+// +kubebuilder:validation:Enum=ssh;tls
+type TransportType string
+
+const (
+	SSHTransport TransportType = "ssh"
+	TLSTransport TransportType = "tls"
+)
+
+// CertToNameMapType defines how a NETCONF username is derived from a certificate, as per RFC 7407
+// +kubebuilder:validation:Enum=specified;sanRfc822Name;sanDnsName;sanIpAddress;sanAny;commonName
+type CertToNameMapType string
+
+const (
+	SpecifiedCertToName     CertToNameMapType = "specified"
+	SANRFC822NameCertToName CertToNameMapType = "sanRfc822Name"
+	SANDNSNameCertToName    CertToNameMapType = "sanDnsName"
+	SANIPAddressCertToName  CertToNameMapType = "sanIpAddress"
+	SANAnyCertToName        CertToNameMapType = "sanAny"
+	CommonNameCertToName    CertToNameMapType = "commonName"
+)
+
+// CertToName maps a certificate to a NETCONF username, following the ietf-x509-cert-to-name model (RFC 7407)
+type CertToName struct {
+	// The entries are evaluated by increasing ID, the first matching entry wins.
+	ID uint32 `json:"id"`
+	// The fingerprint of a certificate of the client certificate chain, e.g. the issuing CA,
+	// as a `tls-fingerprint`: the hash algorithm identifier, followed by the hash, in colon separated
+	// hexadecimal, e.g. `04:6a:...` for SHA256.
+	Fingerprint string `json:"fingerprint"`
+	// How the username is derived from the client certificate.
+	MapType CertToNameMapType `json:"mapType"`
+	// The username, required by the `specified` map type.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// TLSConfig defines how to establish NETCONF over TLS (RFC 7589), using mutual X.509 authentication
+type TLSConfig struct {
+	// Reference to a Secret, within the same namespace, holding the client certificate and its private key,
+	// e.g. a Secret of type `kubernetes.io/tls`, within the `tls.crt` and `tls.key` keys.
+	// The `tls.crt` key can hold the intermediate certificates following the client certificate.
+	ClientCertificateSecretRef corev1.LocalObjectReference `json:"clientCertificateSecretRef"`
+	// Reference to a Secret, within the same namespace, holding the CA bundle used to verify the server
+	// certificate, within the `ca.crt` key. By default, the system CA bundle is used.
+	// +optional
+	CABundleSecretRef *corev1.LocalObjectReference `json:"caBundleSecretRef,omitempty"`
+	// The name expected in the server certificate. By default, the host of the target is used.
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+	// Whether to skip the verification of the server certificate. Not recommended.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+	// The cert-to-name mapping configured on the NETCONF server. When provided, the NETCONF username
+	// the server derives from the client certificate is reported in the status.
+	// +optional
+	CertToName []CertToName `json:"certToName,omitempty"`
+}
+
+// CallHome defines how to identify the NETCONF server calling home (RFC 8071)
+type CallHome struct {
+	// The SHA256 fingerprints identifying the device: the fingerprints of its SSH host key, as displayed by
+	// `ssh-keygen -l`, or of its TLS certificate, in colon separated hexadecimal. A device calling home can also
+	// be bound to the MountPoint by approving its CallHomeDevice.
+	// +optional
+	Fingerprints []string `json:"fingerprints,omitempty"`
+}
+
 // MountPointSpec defines the desired state of MountPoint
 type MountPointSpec struct {
 	// Represents the Netconf server to establish a session with.
-	// By default, port `830` is used. If you need to use another port,
-	// provide the target in the following format: `<host>:<port>`.
-	Target string `json:"target"`
-	// The username to use to authenticate
-	Username string `json:"username"`
-	// The password to use to authenticate
-	Password string `json:"password"`
+	// By default, port `830` is used, or port `6513` when using the `tls` transport.
+	// If you need to use another port, provide the target in the following format: `<host>:<port>`.
+	// Not used when `callHome` is set.
+	// +optional
+	Target string `json:"target,omitempty"`
+	// The transport used to carry the NETCONF session, either `ssh` or `tls`.
+	// +kubebuilder:default:=ssh
+	Transport TransportType `json:"transport,omitempty"`
+	// How to establish the TLS connection. Required by the `tls` transport.
+	// +optional
+	TLS *TLSConfig `json:"tls,omitempty"`
+	// When set, the session isn't established by dialing the target; instead, the operator waits for the
+	// NETCONF server to call home, on port `4334` for `ssh`, or `4335` for `tls`.
+	// +optional
+	CallHome *CallHome `json:"callHome,omitempty"`
+	// Reference to a Secret, within the same namespace, holding the credentials to use to authenticate.
+	// The Secret must provide the `username` key, along with the `password` and/or the `privateKey` key.
+	// The `privateKey` can be protected by the passphrase provided in the `passphrase` key, and an OpenSSH
+	// user certificate can be provided in the `certificate` key.
+	// When set, it takes precedence over the `username` and `password` fields.
+	// +optional
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+	// Reference to a Secret, within the same namespace, holding the SSH private key to use to authenticate,
+	// e.g. a Secret of type `kubernetes.io/ssh-auth`. The key is read from the `ssh-privatekey` or `privateKey` key,
+	// and can be protected by the passphrase provided in the `passphrase` key. An OpenSSH user certificate
+	// can be provided in the `certificate` key.
+	// When set, it takes precedence over the private key provided in the `credentialsSecretRef` Secret.
+	// +optional
+	PrivateKeySecretRef *corev1.LocalObjectReference `json:"privateKeySecretRef,omitempty"`
+	// The SSH authentication methods to attempt, in order. By default, every method for which credentials
+	// are available is attempted, in the following order: `publickey`, `keyboard-interactive`, `password`.
+	// +optional
+	AuthMethods []AuthMethod `json:"authMethods,omitempty"`
+	// The username to use to authenticate.
+	// Deprecated: use `credentialsSecretRef` instead.
+	// +optional
+	Username string `json:"username,omitempty"`
+	// The password to use to authenticate.
+	// Deprecated: use `credentialsSecretRef` instead.
+	// +optional
+	Password string `json:"password,omitempty"`
+	// How to verify the host key presented by the NETCONF server. By default, any host key is accepted.
+	// +optional
+	HostKeyPolicy *HostKeyPolicy `json:"hostKeyPolicy,omitempty"`
 	// Whether to apply timeout while attempting the connection, expressed in second.
 	// By default, set to `15` seconds. Put `0` for no timeout.
 	// +kubebuilder:default:=15
 	Timeout int32 `json:"timeout,omitempty"`
+	// Delay, expressed in second, before attempting to reconnect once the session is lost.
+	// The delay doubles after each failed attempt, up to `reconnectMaxDelay`.
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	ReconnectInitialDelay int32 `json:"reconnectInitialDelay,omitempty"`
+	// Maximum delay, expressed in second, between two reconnection attempts.
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum=1
+	ReconnectMaxDelay int32 `json:"reconnectMaxDelay,omitempty"`
 	// This is to instruct the NETCONF client to advertise additional capabilities
 	AdditionalCapabilities []string `json:"additionalCapabilities,omitempty"`
 }
 
+// Condition reported on a MountPoint about the credentials used to authenticate.
+const (
+	// CredentialsAvailableCondition is true when the credentials referenced by the MountPoint could be loaded.
+	CredentialsAvailableCondition = "CredentialsAvailable"
+
+	// CredentialsLoadedReason is used when the credentials were successfully loaded.
+	CredentialsLoadedReason = "CredentialsLoaded"
+	// SecretNotFoundReason is used when the referenced Secret doesn't exist.
+	SecretNotFoundReason = "SecretNotFound"
+	// SecretMalformedReason is used when the referenced Secret is missing keys, or holds invalid data.
+	SecretMalformedReason = "SecretMalformed"
+)
+
+// Condition reported on a MountPoint about the host key presented by the NETCONF server.
+const (
+	// HostKeyMismatchCondition is true when the host key presented by the NETCONF server doesn't match the
+	// host key policy. In such case, the connection is refused.
+	HostKeyMismatchCondition = "HostKeyMismatch"
+
+	// HostKeyVerifiedReason is used when the host key matches the host key policy.
+	HostKeyVerifiedReason = "HostKeyVerified"
+	// HostKeyChangedReason is used when the host key differs from the expected one.
+	HostKeyChangedReason = "HostKeyChanged"
+	// HostKeyUnknownReason is used when the host isn't listed in known_hosts.
+	HostKeyUnknownReason = "HostKeyUnknown"
+	// HostKeyRevokedReason is used when the host key is marked as revoked in known_hosts.
+	HostKeyRevokedReason = "HostKeyRevoked"
+)
+
+// PeerCertificate describes the certificate presented by the NETCONF server
+type PeerCertificate struct {
+	Subject      string      `json:"subject,omitempty"`
+	Issuer       string      `json:"issuer,omitempty"`
+	SerialNumber string      `json:"serialNumber,omitempty"`
+	NotBefore    metav1.Time `json:"notBefore,omitempty"`
+	NotAfter     metav1.Time `json:"notAfter,omitempty"`
+	DNSNames     []string    `json:"dnsNames,omitempty"`
+	IPAddresses  []string    `json:"ipAddresses,omitempty"`
+	// The SHA256 fingerprint of the certificate, in colon separated hexadecimal
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// TLSStatus describes the TLS connection carrying the NETCONF session
+type TLSStatus struct {
+	// The negotiated TLS version, e.g. `TLS 1.3`
+	Version string `json:"version,omitempty"`
+	// The negotiated cipher suite
+	CipherSuite string `json:"cipherSuite,omitempty"`
+	// The certificate presented by the NETCONF server
+	PeerCertificate *PeerCertificate `json:"peerCertificate,omitempty"`
+	// The NETCONF username derived from the client certificate, using the cert-to-name mapping
+	Username string `json:"username,omitempty"`
+}
+
+// ConnectionState is the state of the NETCONF session established for a MountPoint
+type ConnectionState string
+
+const (
+	Connected        ConnectionState = "Connected"
+	Disconnected     ConnectionState = "Disconnected"
+	Reconnecting     ConnectionState = "Reconnecting"
+	AwaitingCallHome ConnectionState = "AwaitingCallHome"
+)
+
+// MountPointStatus defines the observed state of MountPoint
+type MountPointStatus struct {
+	RPCStatus `json:",inline"`
+	// The host key presented by the NETCONF server, in the authorized_keys format.
+	// With the `trustOnFirstUse` host key policy, this is the trusted key; clear it to trust a new key.
+	HostKey string `json:"hostKey,omitempty"`
+	// The SHA256 fingerprint of the host key presented by the NETCONF server
+	HostKeyFingerprint string `json:"hostKeyFingerprint,omitempty"`
+	// The TLS connection carrying the NETCONF session, when using the `tls` transport
+	TLS *TLSStatus `json:"tls,omitempty"`
+	// The state of the NETCONF session, either `Connected`, `Disconnected`, `Reconnecting` or `AwaitingCallHome`
+	ConnectionState ConnectionState `json:"connectionState,omitempty"`
+	// The address the NETCONF server called home from
+	CallHomeAddress string `json:"callHomeAddress,omitempty"`
+	// Last time the NETCONF session was established
+	LastConnectedTime *metav1.Time `json:"lastConnectedTime,omitempty"`
+	// Last time the NETCONF session was lost
+	LastDisconnectedTime *metav1.Time `json:"lastDisconnectedTime,omitempty"`
+	// Why the NETCONF session was lost, or why the last reconnection attempt failed
+	DisconnectReason string `json:"disconnectReason,omitempty"`
+	// Number of reconnection attempts since the NETCONF session was lost
+	ReconnectAttempts int32 `json:"reconnectAttempts,omitempty"`
+	// Time of the next reconnection attempt
+	NextReconnectTime *metav1.Time `json:"nextReconnectTime,omitempty"`
+}
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.spec.target`
+//+kubebuilder:printcolumn:name="Transport",type=string,JSONPath=`.spec.transport`
+//+kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.connectionState`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // MountPoint is the Schema for the mountPoints API
 type MountPoint struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec      MountPointSpec `json:"spec,omitempty"`
-	RPCStatus `json:"status,omitempty"`
+	Spec             MountPointSpec `json:"spec,omitempty"`
+	MountPointStatus `json:"status,omitempty"`
 }
 
 //+kubebuilder:object:root=true
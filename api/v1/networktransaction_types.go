@@ -0,0 +1,169 @@
+/*
+Copyright 2021. Alexis de Talhouët
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// NetworkTransactionSpec defines the desired state of NetworkTransaction
+type NetworkTransactionSpec struct {
+	// Timeout defines the timeout of each NETCONF operation
+	// defaults to 30 seconds
+	// +kubebuilder:default:=30
+	Timeout int32 `json:"timeout,omitempty"`
+	// The changes to apply, one per MountPoint. They are applied to every device, or to none of them.
+	// +kubebuilder:validation:MinItems=1
+	Devices []TransactionDevice `json:"devices"`
+	// The time, in seconds, the NETCONF servers wait for the confirming commit before rolling back, should the
+	// transaction be interrupted once committed. Default to 120 seconds.
+	// +kubebuilder:default:=120
+	// +kubebuilder:validation:Minimum=1
+	ConfirmTimeout int32 `json:"confirmTimeout,omitempty"`
+	// If this NetworkTransaction should occur after other operations, specify them here. They must all be Ready.
+	// +kubebuilder:validation:Type=""
+	// +kubebuilder:pruning:PreserveUnknownFields
+	DependsOn Dependencies `json:"dependsOn,omitempty"`
+	// Retries the transaction once it was aborted upon a transient rpc-error, e.g. a lock denied on one of
+	// the devices
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+	// Executes the transaction again, or deletes it, once completed
+	ExecutionPolicy `json:",inline"`
+}
+
+// TransactionDevice defines the changes to apply to the device of a MountPoint
+type TransactionDevice struct {
+	// Defines the NETCONF session to use
+	MountPoint string `json:"mountPoint"`
+	// The default operation of the edit-config: `merge`, `replace` or `none`. Default to `merge`.
+	// +kubebuilder:validation:Enum=merge;replace;none
+	// +kubebuilder:default:="merge"
+	Operation string `json:"operation,omitempty"`
+	// Define the XML payload to push to the candidate datastore
+	XML string `json:"xml"`
+}
+
+// NetworkTransactionPhase is the progress of a NetworkTransaction
+type NetworkTransactionPhase string
+
+const (
+	// PendingTransactionPhase is used until the transaction is started, e.g. while the dependency isn't Ready.
+	PendingTransactionPhase NetworkTransactionPhase = "Pending"
+	// ConfirmingTransactionPhase is used once the confirmed commit was sent to every device, until every commit is
+	// confirmed.
+	ConfirmingTransactionPhase NetworkTransactionPhase = "Confirming"
+	// CommittedTransactionPhase is used once the commit was confirmed on every device.
+	CommittedTransactionPhase NetworkTransactionPhase = "Committed"
+	// AbortedTransactionPhase is used once a device failed, and the changes were discarded, or cancelled, on every
+	// device.
+	AbortedTransactionPhase NetworkTransactionPhase = "Aborted"
+	// InconsistentTransactionPhase is used when the confirm timeout expired once the commit was confirmed on some
+	// of the devices only.
+	InconsistentTransactionPhase NetworkTransactionPhase = "Inconsistent"
+)
+
+// TransactionDevicePhase is the progress of the transaction on a device
+type TransactionDevicePhase string
+
+const (
+	PendingDevicePhase TransactionDevicePhase = "Pending"
+	// PreparedDevicePhase is used once the candidate datastore was locked, edited and validated.
+	PreparedDevicePhase TransactionDevicePhase = "Prepared"
+	// ConfirmingDevicePhase is used once the confirmed commit was sent, until confirmed.
+	ConfirmingDevicePhase TransactionDevicePhase = "Confirming"
+	// CommittedDevicePhase is used once the commit was confirmed.
+	CommittedDevicePhase TransactionDevicePhase = "Committed"
+	// DiscardedDevicePhase is used once the changes of the candidate datastore were discarded.
+	DiscardedDevicePhase TransactionDevicePhase = "Discarded"
+	// CancelledDevicePhase is used once the confirmed commit was cancelled.
+	CancelledDevicePhase TransactionDevicePhase = "Cancelled"
+	// AwaitingRollbackDevicePhase is used when the confirmed commit couldn't be cancelled, until the confirm
+	// timeout expires.
+	AwaitingRollbackDevicePhase TransactionDevicePhase = "AwaitingRollback"
+	// RolledBackDevicePhase is used once the confirm timeout expired without the commit being confirmed.
+	RolledBackDevicePhase TransactionDevicePhase = "RolledBack"
+	// FailedDevicePhase is used for the device the transaction failed on. Its changes are discarded.
+	FailedDevicePhase TransactionDevicePhase = "Failed"
+)
+
+// TransactionDeviceStatus is the outcome of the transaction on a device
+type TransactionDeviceStatus struct {
+	// The MountPoint of the device
+	MountPoint string `json:"mountPoint"`
+	// The progress of the transaction on the device: `Pending`, `Prepared`, `Confirming`, `Committed`, `Discarded`,
+	// `Cancelled`, `AwaitingRollback`, `RolledBack` or `Failed`
+	Phase TransactionDevicePhase `json:"phase"`
+	// The reason the transaction failed on the device
+	Message string `json:"message,omitempty"`
+	// The rpc-errors, warnings included, the NETCONF server replied with to the last failed operation
+	RPCErrors []RPCError `json:"rpcErrors,omitempty"`
+}
+
+// NetworkTransactionStatus defines the observed state of NetworkTransaction
+type NetworkTransactionStatus struct {
+	RPCStatus `json:",inline"`
+	// The progress of the transaction: `Pending`, `Confirming`, `Committed`, `Aborted` or `Inconsistent`
+	Phase NetworkTransactionPhase `json:"phase,omitempty"`
+	// The generation the transaction was executed for. A transaction is executed once per generation.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// The persist token of the confirmed commits
+	PersistID string `json:"persistID,omitempty"`
+	// Time the NETCONF servers roll the changes back, unless confirmed
+	ConfirmDeadline *metav1.Time `json:"confirmDeadline,omitempty"`
+	// Time the transaction completed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// The outcome of the transaction, per device
+	Devices []TransactionDeviceStatus `json:"devices,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// NetworkTransaction is the Schema for the networktransactions API
+type NetworkTransaction struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec                     NetworkTransactionSpec `json:"spec,omitempty"`
+	NetworkTransactionStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NetworkTransactionList contains a list of NetworkTransaction
+type NetworkTransactionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NetworkTransaction `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NetworkTransaction{}, &NetworkTransactionList{})
+}
+
+func (obj *NetworkTransaction) GetMountPointNamespacedName(mountpoint string) string {
+	return types.NamespacedName{Namespace: obj.Namespace, Name: mountpoint}.String()
+}
+
+func (obj *NetworkTransaction) GetNamespacedName() string {
+	return types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}.String()
+}
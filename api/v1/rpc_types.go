@@ -18,6 +18,7 @@ package v1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -29,8 +30,28 @@ type RPCSpec struct {
 	// defaults to 1 seconds
 	// +kubebuilder:default:=1
 	Timeout int32 `json:"timeout,omitempty"`
-	// Define the XML payload to sent
-	XML string `json:"xml"`
+	// Define the XML payload to sent. Exactly one of xml and json must be set.
+	// +optional
+	XML string `json:"xml,omitempty"`
+	// Define the payload as JSON-encoded YANG data (RFC 7951), e.g. `module:rpc: {...}`, converted into XML using
+	// the YANG modules advertised by the NETCONF server, and the YANG mapping.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	// +optional
+	JSON *runtime.RawExtension `json:"json,omitempty"`
+	// Complements the mapping of the YANG modules to their XML namespace, used to convert the JSON payload, and
+	// the reply
+	// +optional
+	YANGMapping *YANGMapping `json:"yangMapping,omitempty"`
+	// The format the data of the reply is reported in: with `json`, it is also reported in `status.rpcReplyJSON`
+	// +kubebuilder:validation:Enum=xml;json
+	// +optional
+	ReplyFormat ReplyFormat `json:"replyFormat,omitempty"`
+	// Retries the RPC operation once it failed with a transient rpc-error, e.g. a lock denied
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+	// Executes the RPC operation again, or deletes it, once completed
+	ExecutionPolicy `json:",inline"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,171 @@
+/*
+Copyright 2021. Alexis de Talhouët
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ScheduledOperationSpec defines the desired state of ScheduledOperation
+type ScheduledOperationSpec struct {
+	// The schedule of the runs, in the cron format, e.g. `0 2 * * *`, or `@daily`, or as an interval, e.g.
+	// `@every 30s`
+	Schedule string `json:"schedule"`
+	// The time zone of the schedule, and of the maintenance windows, e.g. `Europe/Paris`. Default to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+	// The windows the runs are restricted to: a run due outside of them is deferred until the next window opens.
+	// By default, the runs aren't restricted.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+	// How to handle a run due while the operation of a previous run is still active: `Allow` starts it anyway,
+	// `Forbid` skips it, and `Replace` deletes the active operations before starting it. Default to `Allow`.
+	// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+	// +kubebuilder:default:="Allow"
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	// Whether the runs are suspended
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// The number of completed runs kept in the history, along with their operation. Default to 10.
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum=1
+	HistoryLimit int32 `json:"historyLimit,omitempty"`
+	// The operation created upon each run
+	Operation OperationTemplate `json:"operation"`
+}
+
+// MaintenanceWindow is a recurring period of time the runs are allowed in
+type MaintenanceWindow struct {
+	// When the window opens, in the cron format, e.g. `0 1 * * sat`. An interval, e.g. `@every 1h`, isn't
+	// supported.
+	Start string `json:"start"`
+	// How long the window stays open, in seconds
+	// +kubebuilder:validation:Minimum=60
+	Duration int32 `json:"duration"`
+}
+
+// ConcurrencyPolicy defines how concurrent runs are handled
+type ConcurrencyPolicy string
+
+const (
+	AllowConcurrent   ConcurrencyPolicy = "Allow"
+	ForbidConcurrent  ConcurrencyPolicy = "Forbid"
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+// OperationTemplate describes the operation created upon each run, named after the ScheduledOperation and the
+// time the run was due
+type OperationTemplate struct {
+	// The kind of the operation
+	// +kubebuilder:validation:Enum=Commit;ConfigRestore;CopyConfig;DeleteConfig;DiscardChanges;EditConfig;Get;GetConfig;Lock;NetworkTransaction;RPC;Unlock;Validate;Workflow
+	Kind string `json:"kind"`
+	// The labels of the operation
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// The annotations of the operation
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// The spec of the operation, as defined by its kind
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Spec runtime.RawExtension `json:"spec"`
+}
+
+// ScheduledRunResult is the outcome of a run
+type ScheduledRunResult string
+
+const (
+	// RunningResult is used until the operation is Ready, or failed.
+	RunningResult ScheduledRunResult = "Running"
+	// SucceededResult is used once the operation is Ready.
+	SucceededResult ScheduledRunResult = "Succeeded"
+	// FailedResult is used once the operation failed, or was deleted before completing.
+	FailedResult ScheduledRunResult = "Failed"
+	// SkippedResult is used when the run was skipped, as the operation of a previous run was still active.
+	SkippedResult ScheduledRunResult = "Skipped"
+	// ReplacedResult is used when the operation was deleted by the next run, before completing.
+	ReplacedResult ScheduledRunResult = "Replaced"
+)
+
+// ScheduledRun is a run of the ScheduledOperation
+type ScheduledRun struct {
+	// The kind of the operation
+	Kind string `json:"kind"`
+	// The operation created by the run
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Time the run was due
+	ScheduleTime metav1.Time `json:"scheduleTime"`
+	// Time the operation was created
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Time the operation completed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// How long the operation took to complete, e.g. `1m30s`
+	Duration string `json:"duration,omitempty"`
+	// `Running`, `Succeeded`, `Failed`, `Skipped` or `Replaced`
+	Result ScheduledRunResult `json:"result"`
+	// The message of the Ready condition of the operation, once completed, or why the run was skipped
+	Message string `json:"message,omitempty"`
+}
+
+// ScheduledOperationStatus defines the observed state of ScheduledOperation
+type ScheduledOperationStatus struct {
+	RPCStatus `json:",inline"`
+	// Last time a run was started, or skipped
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// Next time a run is due, or the next maintenance window opens
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+	// The runs, the most recent first
+	History []ScheduledRun `json:"history,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+//+kubebuilder:printcolumn:name="Kind",type=string,JSONPath=`.spec.operation.kind`
+//+kubebuilder:printcolumn:name="Last Schedule",type=date,JSONPath=`.status.lastScheduleTime`
+//+kubebuilder:printcolumn:name="Last Result",type=string,JSONPath=`.status.history[0].result`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ScheduledOperation is the Schema for the scheduledoperations API
+type ScheduledOperation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec                     ScheduledOperationSpec `json:"spec,omitempty"`
+	ScheduledOperationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ScheduledOperationList contains a list of ScheduledOperation
+type ScheduledOperationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ScheduledOperation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ScheduledOperation{}, &ScheduledOperationList{})
+}
+
+func (obj *ScheduledOperation) GetNamespacedName() string {
+	return types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}.String()
+}
@@ -32,8 +32,15 @@ type UnlockSpec struct {
 	// Identify the datastore against which the operation should be performed. Default to `candidate`.
 	// +kubebuilder:default:="candidate"
 	Target string `json:"target,omitempty"`
-	// If this Unlock operation should occur after another operation, specify the other operation here.
-	DependsOn DependsOn `json:"dependsOn,omitempty"`
+	// If this Unlock operation should occur after other operations, specify them here. They must all be Ready.
+	// +kubebuilder:validation:Type=""
+	// +kubebuilder:pruning:PreserveUnknownFields
+	DependsOn Dependencies `json:"dependsOn,omitempty"`
+	// Retries the Unlock operation once it failed with a transient rpc-error, e.g. a lock denied
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+	// Executes the Unlock operation again, or deletes it, once completed
+	ExecutionPolicy `json:",inline"`
 }
 
 //+kubebuilder:object:root=true
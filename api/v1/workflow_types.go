@@ -0,0 +1,186 @@
+/*
+Copyright 2021. Alexis de Talhouët
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// WorkflowSpec defines the desired state of Workflow
+type WorkflowSpec struct {
+	// Defines the NETCONF session to use
+	MountPoint string `json:"mountPoint"`
+	// Timeout defines the timeout of each step
+	// defaults to 1 seconds
+	// +kubebuilder:default:=1
+	Timeout int32 `json:"timeout,omitempty"`
+	// The steps to execute, in order, on the session of the MountPoint. A step can declare the steps it runs after,
+	// in which case the steps are executed in a topological order, otherwise in the order they are listed.
+	// +kubebuilder:validation:MinItems=1
+	Steps []WorkflowStep `json:"steps"`
+	// The compensating steps to execute once a step failed. By default, the changes of the candidate datastore are
+	// discarded, if edited since its last commit, and the datastores locked by the workflow are unlocked.
+	// +optional
+	OnFailure []WorkflowStep `json:"onFailure,omitempty"`
+	// If this Workflow should occur after other operations, specify them here. They must all be Ready.
+	// +kubebuilder:validation:Type=""
+	// +kubebuilder:pruning:PreserveUnknownFields
+	DependsOn Dependencies `json:"dependsOn,omitempty"`
+	// Retries the workflow once it failed with a transient rpc-error, e.g. a lock denied. The failed
+	// workflow is compensated before being retried from its first step
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+	// Executes the workflow again, or deletes it, once completed
+	ExecutionPolicy `json:",inline"`
+}
+
+// WorkflowStepType defines the NETCONF operation of a step
+type WorkflowStepType string
+
+const (
+	LockWorkflowStep           WorkflowStepType = "lock"
+	UnlockWorkflowStep         WorkflowStepType = "unlock"
+	EditConfigWorkflowStep     WorkflowStepType = "editConfig"
+	ValidateWorkflowStep       WorkflowStepType = "validate"
+	CommitWorkflowStep         WorkflowStepType = "commit"
+	DiscardChangesWorkflowStep WorkflowStepType = "discardChanges"
+	GetWorkflowStep            WorkflowStepType = "get"
+	GetConfigWorkflowStep      WorkflowStepType = "getConfig"
+	RPCWorkflowStep            WorkflowStepType = "rpc"
+)
+
+// WorkflowStep is a NETCONF operation of a Workflow
+type WorkflowStep struct {
+	// The name of the step, unique within the workflow
+	Name string `json:"name"`
+	// The NETCONF operation: `lock`, `unlock`, `editConfig`, `validate`, `commit`, `discardChanges`, `get`,
+	// `getConfig` or `rpc`
+	// +kubebuilder:validation:Enum=lock;unlock;editConfig;validate;commit;discardChanges;get;getConfig;rpc
+	Type WorkflowStepType `json:"type"`
+	// The names of the steps this step is executed after
+	// +optional
+	RunAfter []string `json:"runAfter,omitempty"`
+	// Identify the datastore to lock, unlock, edit, validate, or read using `getConfig`. Default to `running` for
+	// `getConfig`, `candidate` otherwise.
+	// +kubebuilder:validation:Enum=running;candidate;startup
+	// +optional
+	Datastore string `json:"datastore,omitempty"`
+	// The default operation of `editConfig`: `merge`, `replace` or `none`. Default to `merge`.
+	// +kubebuilder:validation:Enum=merge;replace;none
+	// +optional
+	Operation string `json:"operation,omitempty"`
+	// The XML payload: the configuration of `editConfig`, the RPC of `rpc`, or the subtree filter of `get` and
+	// `getConfig`
+	// +optional
+	XML string `json:"xml,omitempty"`
+}
+
+// WorkflowPhase is the progress of a Workflow
+type WorkflowPhase string
+
+const (
+	// PendingWorkflowPhase is used until the steps are started, e.g. while the dependency isn't Ready
+	PendingWorkflowPhase   WorkflowPhase = "Pending"
+	SucceededWorkflowPhase WorkflowPhase = "Succeeded"
+	FailedWorkflowPhase    WorkflowPhase = "Failed"
+)
+
+// WorkflowStepPhase is the outcome of a step
+type WorkflowStepPhase string
+
+const (
+	PendingWorkflowStepPhase   WorkflowStepPhase = "Pending"
+	SucceededWorkflowStepPhase WorkflowStepPhase = "Succeeded"
+	FailedWorkflowStepPhase    WorkflowStepPhase = "Failed"
+	// SkippedWorkflowStepPhase is used for the steps following a failed step
+	SkippedWorkflowStepPhase WorkflowStepPhase = "Skipped"
+)
+
+// WorkflowStepStatus is the outcome of a step
+type WorkflowStepStatus struct {
+	// The name of the step
+	Name string `json:"name"`
+	// The NETCONF operation of the step
+	Type WorkflowStepType `json:"type"`
+	// The outcome of the step: `Pending`, `Succeeded`, `Failed` or `Skipped`
+	Phase WorkflowStepPhase `json:"phase"`
+	// Time the step was started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Time the step completed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// The reason the step failed, or was skipped
+	Message string `json:"message,omitempty"`
+	// The data of the reply of `get`, `getConfig` and `rpc`
+	Reply string `json:"reply,omitempty"`
+	// The rpc-errors, warnings included, the NETCONF server replied with
+	RPCErrors []RPCError `json:"rpcErrors,omitempty"`
+}
+
+// WorkflowStatus defines the observed state of Workflow
+type WorkflowStatus struct {
+	RPCStatus `json:",inline"`
+	// The progress of the workflow: `Pending`, `Succeeded` or `Failed`
+	Phase WorkflowPhase `json:"phase,omitempty"`
+	// The generation the workflow was executed for. A workflow is executed once per generation.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Time the workflow was started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Time the workflow completed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// The outcome of each step, in execution order
+	Steps []WorkflowStepStatus `json:"steps,omitempty"`
+	// The outcome of each compensating step, executed once a step failed
+	Compensations []WorkflowStepStatus `json:"compensations,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="MountPoint",type=string,JSONPath=`.spec.mountPoint`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Workflow is the Schema for the workflows API
+type Workflow struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec           WorkflowSpec `json:"spec,omitempty"`
+	WorkflowStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// WorkflowList contains a list of Workflow
+type WorkflowList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Workflow `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Workflow{}, &WorkflowList{})
+}
+
+func (obj *Workflow) GetMountPointNamespacedName(mountpoint string) string {
+	return types.NamespacedName{Namespace: obj.Namespace, Name: mountpoint}.String()
+}
+
+func (obj *Workflow) GetNamespacedName() string {
+	return types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}.String()
+}
@@ -21,106 +21,77 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Commit) DeepCopyInto(out *Commit) {
+func (in *BackupStorage) DeepCopyInto(out *BackupStorage) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Commit.
-func (in *Commit) DeepCopy() *Commit {
-	if in == nil {
-		return nil
-	}
-	out := new(Commit)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Commit) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(ConfigMapBackupStorage)
+		**out = **in
 	}
-	return nil
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CommitList) DeepCopyInto(out *CommitList) {
-	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Commit, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitBackupStorage)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommitList.
-func (in *CommitList) DeepCopy() *CommitList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStorage.
+func (in *BackupStorage) DeepCopy() *BackupStorage {
 	if in == nil {
 		return nil
 	}
-	out := new(CommitList)
+	out := new(BackupStorage)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CommitList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CommitSpec) DeepCopyInto(out *CommitSpec) {
+func (in *CallHome) DeepCopyInto(out *CallHome) {
 	*out = *in
-	out.DependsOn = in.DependsOn
+	if in.Fingerprints != nil {
+		in, out := &in.Fingerprints, &out.Fingerprints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommitSpec.
-func (in *CommitSpec) DeepCopy() *CommitSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CallHome.
+func (in *CallHome) DeepCopy() *CallHome {
 	if in == nil {
 		return nil
 	}
-	out := new(CommitSpec)
+	out := new(CallHome)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CreateSubscription) DeepCopyInto(out *CreateSubscription) {
+func (in *CallHomeDevice) DeepCopyInto(out *CallHomeDevice) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CreateSubscription.
-func (in *CreateSubscription) DeepCopy() *CreateSubscription {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CallHomeDevice.
+func (in *CallHomeDevice) DeepCopy() *CallHomeDevice {
 	if in == nil {
 		return nil
 	}
-	out := new(CreateSubscription)
+	out := new(CallHomeDevice)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CreateSubscription) DeepCopyObject() runtime.Object {
+func (in *CallHomeDevice) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -128,31 +99,31 @@ func (in *CreateSubscription) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CreateSubscriptionList) DeepCopyInto(out *CreateSubscriptionList) {
+func (in *CallHomeDeviceList) DeepCopyInto(out *CallHomeDeviceList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]CreateSubscription, len(*in))
+		*out = make([]CallHomeDevice, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CreateSubscriptionList.
-func (in *CreateSubscriptionList) DeepCopy() *CreateSubscriptionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CallHomeDeviceList.
+func (in *CallHomeDeviceList) DeepCopy() *CallHomeDeviceList {
 	if in == nil {
 		return nil
 	}
-	out := new(CreateSubscriptionList)
+	out := new(CallHomeDeviceList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CreateSubscriptionList) DeepCopyObject() runtime.Object {
+func (in *CallHomeDeviceList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -160,57 +131,89 @@ func (in *CreateSubscriptionList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CreateSubscriptionSpec) DeepCopyInto(out *CreateSubscriptionSpec) {
+func (in *CallHomeDeviceSpec) DeepCopyInto(out *CallHomeDeviceSpec) {
 	*out = *in
-	out.KafkaSink = in.KafkaSink
+	if in.MountPointRef != nil {
+		in, out := &in.MountPointRef, &out.MountPointRef
+		*out = new(MountPointReference)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CreateSubscriptionSpec.
-func (in *CreateSubscriptionSpec) DeepCopy() *CreateSubscriptionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CallHomeDeviceSpec.
+func (in *CallHomeDeviceSpec) DeepCopy() *CallHomeDeviceSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(CreateSubscriptionSpec)
+	out := new(CallHomeDeviceSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DependsOn) DeepCopyInto(out *DependsOn) {
+func (in *CallHomeDeviceStatus) DeepCopyInto(out *CallHomeDeviceStatus) {
 	*out = *in
+	if in.PeerCertificate != nil {
+		in, out := &in.PeerCertificate, &out.PeerCertificate
+		*out = new(PeerCertificate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FirstSeenTime != nil {
+		in, out := &in.FirstSeenTime, &out.FirstSeenTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSeenTime != nil {
+		in, out := &in.LastSeenTime, &out.LastSeenTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependsOn.
-func (in *DependsOn) DeepCopy() *DependsOn {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CallHomeDeviceStatus.
+func (in *CallHomeDeviceStatus) DeepCopy() *CallHomeDeviceStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DependsOn)
+	out := new(CallHomeDeviceStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EditConfig) DeepCopyInto(out *EditConfig) {
+func (in *CertToName) DeepCopyInto(out *CertToName) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertToName.
+func (in *CertToName) DeepCopy() *CertToName {
+	if in == nil {
+		return nil
+	}
+	out := new(CertToName)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Commit) DeepCopyInto(out *Commit) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.CommitStatus.DeepCopyInto(&out.CommitStatus)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EditConfig.
-func (in *EditConfig) DeepCopy() *EditConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Commit.
+func (in *Commit) DeepCopy() *Commit {
 	if in == nil {
 		return nil
 	}
-	out := new(EditConfig)
+	out := new(Commit)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *EditConfig) DeepCopyObject() runtime.Object {
+func (in *Commit) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -218,31 +221,31 @@ func (in *EditConfig) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EditConfigList) DeepCopyInto(out *EditConfigList) {
+func (in *CommitList) DeepCopyInto(out *CommitList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]EditConfig, len(*in))
+		*out = make([]Commit, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EditConfigList.
-func (in *EditConfigList) DeepCopy() *EditConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommitList.
+func (in *CommitList) DeepCopy() *CommitList {
 	if in == nil {
 		return nil
 	}
-	out := new(EditConfigList)
+	out := new(CommitList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *EditConfigList) DeepCopyObject() runtime.Object {
+func (in *CommitList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -250,42 +253,81 @@ func (in *EditConfigList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EditConfigSpec) DeepCopyInto(out *EditConfigSpec) {
+func (in *CommitSpec) DeepCopyInto(out *CommitSpec) {
 	*out = *in
-	out.DependsOn = in.DependsOn
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make(Dependencies, len(*in))
+		copy(*out, *in)
+	}
+	if in.Confirmed != nil {
+		in, out := &in.Confirmed, &out.Confirmed
+		*out = new(ConfirmedCommit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ExecutionPolicy.DeepCopyInto(&out.ExecutionPolicy)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EditConfigSpec.
-func (in *EditConfigSpec) DeepCopy() *EditConfigSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommitSpec.
+func (in *CommitSpec) DeepCopy() *CommitSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(EditConfigSpec)
+	out := new(CommitSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EstablishSubscription) DeepCopyInto(out *EstablishSubscription) {
+func (in *CommitStatus) DeepCopyInto(out *CommitStatus) {
+	*out = *in
+	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+	if in.ConfirmedCommitTime != nil {
+		in, out := &in.ConfirmedCommitTime, &out.ConfirmedCommitTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ConfirmDeadline != nil {
+		in, out := &in.ConfirmDeadline, &out.ConfirmDeadline
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommitStatus.
+func (in *CommitStatus) DeepCopy() *CommitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CommitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigBackup) DeepCopyInto(out *ConfigBackup) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.ConfigBackupStatus.DeepCopyInto(&out.ConfigBackupStatus)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EstablishSubscription.
-func (in *EstablishSubscription) DeepCopy() *EstablishSubscription {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigBackup.
+func (in *ConfigBackup) DeepCopy() *ConfigBackup {
 	if in == nil {
 		return nil
 	}
-	out := new(EstablishSubscription)
+	out := new(ConfigBackup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *EstablishSubscription) DeepCopyObject() runtime.Object {
+func (in *ConfigBackup) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -293,31 +335,31 @@ func (in *EstablishSubscription) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EstablishSubscriptionList) DeepCopyInto(out *EstablishSubscriptionList) {
+func (in *ConfigBackupList) DeepCopyInto(out *ConfigBackupList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]EstablishSubscription, len(*in))
+		*out = make([]ConfigBackup, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EstablishSubscriptionList.
-func (in *EstablishSubscriptionList) DeepCopy() *EstablishSubscriptionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigBackupList.
+func (in *ConfigBackupList) DeepCopy() *ConfigBackupList {
 	if in == nil {
 		return nil
 	}
-	out := new(EstablishSubscriptionList)
+	out := new(ConfigBackupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *EstablishSubscriptionList) DeepCopyObject() runtime.Object {
+func (in *ConfigBackupList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -325,69 +367,93 @@ func (in *EstablishSubscriptionList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EstablishSubscriptionSpec) DeepCopyInto(out *EstablishSubscriptionSpec) {
+func (in *ConfigBackupSpec) DeepCopyInto(out *ConfigBackupSpec) {
 	*out = *in
-	out.KafkaSink = in.KafkaSink
+	if in.MountPoints != nil {
+		in, out := &in.MountPoints, &out.MountPoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Storage.DeepCopyInto(&out.Storage)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EstablishSubscriptionSpec.
-func (in *EstablishSubscriptionSpec) DeepCopy() *EstablishSubscriptionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigBackupSpec.
+func (in *ConfigBackupSpec) DeepCopy() *ConfigBackupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(EstablishSubscriptionSpec)
+	out := new(ConfigBackupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Get) DeepCopyInto(out *Get) {
+func (in *ConfigBackupStatus) DeepCopyInto(out *ConfigBackupStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
 	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.MountPoints != nil {
+		in, out := &in.MountPoints, &out.MountPoints
+		*out = make([]MountPointBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Get.
-func (in *Get) DeepCopy() *Get {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigBackupStatus.
+func (in *ConfigBackupStatus) DeepCopy() *ConfigBackupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Get)
+	out := new(ConfigBackupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Get) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapBackupStorage) DeepCopyInto(out *ConfigMapBackupStorage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapBackupStorage.
+func (in *ConfigMapBackupStorage) DeepCopy() *ConfigMapBackupStorage {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(ConfigMapBackupStorage)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GetConfig) DeepCopyInto(out *GetConfig) {
+func (in *ConfigRestore) DeepCopyInto(out *ConfigRestore) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.ConfigRestoreStatus.DeepCopyInto(&out.ConfigRestoreStatus)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GetConfig.
-func (in *GetConfig) DeepCopy() *GetConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigRestore.
+func (in *ConfigRestore) DeepCopy() *ConfigRestore {
 	if in == nil {
 		return nil
 	}
-	out := new(GetConfig)
+	out := new(ConfigRestore)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GetConfig) DeepCopyObject() runtime.Object {
+func (in *ConfigRestore) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -395,31 +461,31 @@ func (in *GetConfig) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GetConfigList) DeepCopyInto(out *GetConfigList) {
+func (in *ConfigRestoreList) DeepCopyInto(out *ConfigRestoreList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]GetConfig, len(*in))
+		*out = make([]ConfigRestore, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GetConfigList.
-func (in *GetConfigList) DeepCopy() *GetConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigRestoreList.
+func (in *ConfigRestoreList) DeepCopy() *ConfigRestoreList {
 	if in == nil {
 		return nil
 	}
-	out := new(GetConfigList)
+	out := new(ConfigRestoreList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GetConfigList) DeepCopyObject() runtime.Object {
+func (in *ConfigRestoreList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -427,103 +493,133 @@ func (in *GetConfigList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GetConfigSpec) DeepCopyInto(out *GetConfigSpec) {
+func (in *ConfigRestoreSpec) DeepCopyInto(out *ConfigRestoreSpec) {
 	*out = *in
+	in.Source.DeepCopyInto(&out.Source)
+	if in.Confirmed != nil {
+		in, out := &in.Confirmed, &out.Confirmed
+		*out = new(ConfirmedCommit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make(Dependencies, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ExecutionPolicy.DeepCopyInto(&out.ExecutionPolicy)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GetConfigSpec.
-func (in *GetConfigSpec) DeepCopy() *GetConfigSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigRestoreSpec.
+func (in *ConfigRestoreSpec) DeepCopy() *ConfigRestoreSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GetConfigSpec)
+	out := new(ConfigRestoreSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GetList) DeepCopyInto(out *GetList) {
+func (in *ConfigRestoreStatus) DeepCopyInto(out *ConfigRestoreStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Get, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	in.CommitStatus.DeepCopyInto(&out.CommitStatus)
+	if in.RestoreTime != nil {
+		in, out := &in.RestoreTime, &out.RestoreTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GetList.
-func (in *GetList) DeepCopy() *GetList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigRestoreStatus.
+func (in *ConfigRestoreStatus) DeepCopy() *ConfigRestoreStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GetList)
+	out := new(ConfigRestoreStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GetList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigSource) DeepCopyInto(out *ConfigSource) {
+	*out = *in
+	out.ConfigTarget = in.ConfigTarget
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = (*in).DeepCopy()
 	}
-	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSource.
+func (in *ConfigSource) DeepCopy() *ConfigSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigSource)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GetSpec) DeepCopyInto(out *GetSpec) {
+func (in *ConfigTarget) DeepCopyInto(out *ConfigTarget) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GetSpec.
-func (in *GetSpec) DeepCopy() *GetSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigTarget.
+func (in *ConfigTarget) DeepCopy() *ConfigTarget {
 	if in == nil {
 		return nil
 	}
-	out := new(GetSpec)
+	out := new(ConfigTarget)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KafkaSink) DeepCopyInto(out *KafkaSink) {
+func (in *ConfirmedCommit) DeepCopyInto(out *ConfirmedCommit) {
 	*out = *in
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheck)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaSink.
-func (in *KafkaSink) DeepCopy() *KafkaSink {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfirmedCommit.
+func (in *ConfirmedCommit) DeepCopy() *ConfirmedCommit {
 	if in == nil {
 		return nil
 	}
-	out := new(KafkaSink)
+	out := new(ConfirmedCommit)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Lock) DeepCopyInto(out *Lock) {
+func (in *CopyConfig) DeepCopyInto(out *CopyConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Lock.
-func (in *Lock) DeepCopy() *Lock {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CopyConfig.
+func (in *CopyConfig) DeepCopy() *CopyConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(Lock)
+	out := new(CopyConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Lock) DeepCopyObject() runtime.Object {
+func (in *CopyConfig) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -531,31 +627,31 @@ func (in *Lock) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LockList) DeepCopyInto(out *LockList) {
+func (in *CopyConfigList) DeepCopyInto(out *CopyConfigList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Lock, len(*in))
+		*out = make([]CopyConfig, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockList.
-func (in *LockList) DeepCopy() *LockList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CopyConfigList.
+func (in *CopyConfigList) DeepCopy() *CopyConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(LockList)
+	out := new(CopyConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LockList) DeepCopyObject() runtime.Object {
+func (in *CopyConfigList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -563,22 +659,35 @@ func (in *LockList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LockSpec) DeepCopyInto(out *LockSpec) {
+func (in *CopyConfigSpec) DeepCopyInto(out *CopyConfigSpec) {
 	*out = *in
+	out.Target = in.Target
+	in.Source.DeepCopyInto(&out.Source)
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make(Dependencies, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ExecutionPolicy.DeepCopyInto(&out.ExecutionPolicy)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockSpec.
-func (in *LockSpec) DeepCopy() *LockSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CopyConfigSpec.
+func (in *CopyConfigSpec) DeepCopy() *CopyConfigSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(LockSpec)
+	out := new(CopyConfigSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MountPoint) DeepCopyInto(out *MountPoint) {
+func (in *CreateSubscription) DeepCopyInto(out *CreateSubscription) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -586,18 +695,18 @@ func (in *MountPoint) DeepCopyInto(out *MountPoint) {
 	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MountPoint.
-func (in *MountPoint) DeepCopy() *MountPoint {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CreateSubscription.
+func (in *CreateSubscription) DeepCopy() *CreateSubscription {
 	if in == nil {
 		return nil
 	}
-	out := new(MountPoint)
+	out := new(CreateSubscription)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MountPoint) DeepCopyObject() runtime.Object {
+func (in *CreateSubscription) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -605,31 +714,31 @@ func (in *MountPoint) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MountPointList) DeepCopyInto(out *MountPointList) {
+func (in *CreateSubscriptionList) DeepCopyInto(out *CreateSubscriptionList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]MountPoint, len(*in))
+		*out = make([]CreateSubscription, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MountPointList.
-func (in *MountPointList) DeepCopy() *MountPointList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CreateSubscriptionList.
+func (in *CreateSubscriptionList) DeepCopy() *CreateSubscriptionList {
 	if in == nil {
 		return nil
 	}
-	out := new(MountPointList)
+	out := new(CreateSubscriptionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MountPointList) DeepCopyObject() runtime.Object {
+func (in *CreateSubscriptionList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -637,46 +746,47 @@ func (in *MountPointList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MountPointSpec) DeepCopyInto(out *MountPointSpec) {
+func (in *CreateSubscriptionSpec) DeepCopyInto(out *CreateSubscriptionSpec) {
 	*out = *in
-	if in.AdditionalCapabilities != nil {
-		in, out := &in.AdditionalCapabilities, &out.AdditionalCapabilities
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out.KafkaSink = in.KafkaSink
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MountPointSpec.
-func (in *MountPointSpec) DeepCopy() *MountPointSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CreateSubscriptionSpec.
+func (in *CreateSubscriptionSpec) DeepCopy() *CreateSubscriptionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MountPointSpec)
+	out := new(CreateSubscriptionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RPC) DeepCopyInto(out *RPC) {
+func (in *DeleteConfig) DeepCopyInto(out *DeleteConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RPC.
-func (in *RPC) DeepCopy() *RPC {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeleteConfig.
+func (in *DeleteConfig) DeepCopy() *DeleteConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(RPC)
+	out := new(DeleteConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RPC) DeepCopyObject() runtime.Object {
+func (in *DeleteConfig) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -684,31 +794,31 @@ func (in *RPC) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RPCList) DeepCopyInto(out *RPCList) {
+func (in *DeleteConfigList) DeepCopyInto(out *DeleteConfigList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]RPC, len(*in))
+		*out = make([]DeleteConfig, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RPCList.
-func (in *RPCList) DeepCopy() *RPCList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeleteConfigList.
+func (in *DeleteConfigList) DeepCopy() *DeleteConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(RPCList)
+	out := new(DeleteConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RPCList) DeepCopyObject() runtime.Object {
+func (in *DeleteConfigList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -716,68 +826,172 @@ func (in *RPCList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RPCSpec) DeepCopyInto(out *RPCSpec) {
+func (in *DeleteConfigSpec) DeepCopyInto(out *DeleteConfigSpec) {
 	*out = *in
+	out.Target = in.Target
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make(Dependencies, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ExecutionPolicy.DeepCopyInto(&out.ExecutionPolicy)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RPCSpec.
-func (in *RPCSpec) DeepCopy() *RPCSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeleteConfigSpec.
+func (in *DeleteConfigSpec) DeepCopy() *DeleteConfigSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RPCSpec)
+	out := new(DeleteConfigSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RPCStatus) DeepCopyInto(out *RPCStatus) {
+func (in Dependencies) DeepCopyInto(out *Dependencies) {
+	{
+		in := &in
+		*out = make(Dependencies, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Dependencies.
+func (in Dependencies) DeepCopy() Dependencies {
+	if in == nil {
+		return nil
+	}
+	out := new(Dependencies)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DependsOn) DeepCopyInto(out *DependsOn) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependsOn.
+func (in *DependsOn) DeepCopy() *DependsOn {
+	if in == nil {
+		return nil
+	}
+	out := new(DependsOn)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiscardChanges) DeepCopyInto(out *DiscardChanges) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiscardChanges.
+func (in *DiscardChanges) DeepCopy() *DiscardChanges {
+	if in == nil {
+		return nil
+	}
+	out := new(DiscardChanges)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DiscardChanges) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiscardChangesList) DeepCopyInto(out *DiscardChangesList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DiscardChanges, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Capabilities != nil {
-		in, out := &in.Capabilities, &out.Capabilities
-		*out = make([]string, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiscardChangesList.
+func (in *DiscardChangesList) DeepCopy() *DiscardChangesList {
+	if in == nil {
+		return nil
+	}
+	out := new(DiscardChangesList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DiscardChangesList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiscardChangesSpec) DeepCopyInto(out *DiscardChangesSpec) {
+	*out = *in
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make(Dependencies, len(*in))
 		copy(*out, *in)
 	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ExecutionPolicy.DeepCopyInto(&out.ExecutionPolicy)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RPCStatus.
-func (in *RPCStatus) DeepCopy() *RPCStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiscardChangesSpec.
+func (in *DiscardChangesSpec) DeepCopy() *DiscardChangesSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RPCStatus)
+	out := new(DiscardChangesSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Unlock) DeepCopyInto(out *Unlock) {
+func (in *EditConfig) DeepCopyInto(out *EditConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.EditConfigStatus.DeepCopyInto(&out.EditConfigStatus)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Unlock.
-func (in *Unlock) DeepCopy() *Unlock {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EditConfig.
+func (in *EditConfig) DeepCopy() *EditConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(Unlock)
+	out := new(EditConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Unlock) DeepCopyObject() runtime.Object {
+func (in *EditConfig) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -785,31 +999,31 @@ func (in *Unlock) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *UnlockList) DeepCopyInto(out *UnlockList) {
+func (in *EditConfigList) DeepCopyInto(out *EditConfigList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Unlock, len(*in))
+		*out = make([]EditConfig, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnlockList.
-func (in *UnlockList) DeepCopy() *UnlockList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EditConfigList.
+func (in *EditConfigList) DeepCopy() *EditConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(UnlockList)
+	out := new(EditConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *UnlockList) DeepCopyObject() runtime.Object {
+func (in *EditConfigList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -817,17 +1031,1752 @@ func (in *UnlockList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *UnlockSpec) DeepCopyInto(out *UnlockSpec) {
+func (in *EditConfigSpec) DeepCopyInto(out *EditConfigSpec) {
 	*out = *in
-	out.DependsOn = in.DependsOn
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]TemplateParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.JSON != nil {
+		in, out := &in.JSON, &out.JSON
+		*out = (*in).DeepCopy()
+	}
+	if in.YANGMapping != nil {
+		in, out := &in.YANGMapping, &out.YANGMapping
+		*out = new(YANGMapping)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make(Dependencies, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnlockSpec.
-func (in *UnlockSpec) DeepCopy() *UnlockSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EditConfigSpec.
+func (in *EditConfigSpec) DeepCopy() *EditConfigSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(UnlockSpec)
+	out := new(EditConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EditConfigStatus) DeepCopyInto(out *EditConfigStatus) {
+	*out = *in
+	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+	if in.LastDriftCheckTime != nil {
+		in, out := &in.LastDriftCheckTime, &out.LastDriftCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastDriftCorrectionTime != nil {
+		in, out := &in.LastDriftCorrectionTime, &out.LastDriftCorrectionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EditConfigStatus.
+func (in *EditConfigStatus) DeepCopy() *EditConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EditConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EstablishSubscription) DeepCopyInto(out *EstablishSubscription) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EstablishSubscription.
+func (in *EstablishSubscription) DeepCopy() *EstablishSubscription {
+	if in == nil {
+		return nil
+	}
+	out := new(EstablishSubscription)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EstablishSubscription) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EstablishSubscriptionList) DeepCopyInto(out *EstablishSubscriptionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EstablishSubscription, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EstablishSubscriptionList.
+func (in *EstablishSubscriptionList) DeepCopy() *EstablishSubscriptionList {
+	if in == nil {
+		return nil
+	}
+	out := new(EstablishSubscriptionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EstablishSubscriptionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EstablishSubscriptionSpec) DeepCopyInto(out *EstablishSubscriptionSpec) {
+	*out = *in
+	out.KafkaSink = in.KafkaSink
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EstablishSubscriptionSpec.
+func (in *EstablishSubscriptionSpec) DeepCopy() *EstablishSubscriptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EstablishSubscriptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutionPolicy) DeepCopyInto(out *ExecutionPolicy) {
+	*out = *in
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionPolicy.
+func (in *ExecutionPolicy) DeepCopy() *ExecutionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Get) DeepCopyInto(out *Get) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Get.
+func (in *Get) DeepCopy() *Get {
+	if in == nil {
+		return nil
+	}
+	out := new(Get)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Get) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GetConfig) DeepCopyInto(out *GetConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GetConfig.
+func (in *GetConfig) DeepCopy() *GetConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GetConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GetConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GetConfigList) DeepCopyInto(out *GetConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GetConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GetConfigList.
+func (in *GetConfigList) DeepCopy() *GetConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(GetConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GetConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GetConfigSpec) DeepCopyInto(out *GetConfigSpec) {
+	*out = *in
+	if in.FilterJSON != nil {
+		in, out := &in.FilterJSON, &out.FilterJSON
+		*out = (*in).DeepCopy()
+	}
+	if in.YANGMapping != nil {
+		in, out := &in.YANGMapping, &out.YANGMapping
+		*out = new(YANGMapping)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ExecutionPolicy.DeepCopyInto(&out.ExecutionPolicy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GetConfigSpec.
+func (in *GetConfigSpec) DeepCopy() *GetConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GetConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GetList) DeepCopyInto(out *GetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Get, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GetList.
+func (in *GetList) DeepCopy() *GetList {
+	if in == nil {
+		return nil
+	}
+	out := new(GetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GetSpec) DeepCopyInto(out *GetSpec) {
+	*out = *in
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ExecutionPolicy.DeepCopyInto(&out.ExecutionPolicy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GetSpec.
+func (in *GetSpec) DeepCopy() *GetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitBackupStorage) DeepCopyInto(out *GitBackupStorage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitBackupStorage.
+func (in *GitBackupStorage) DeepCopy() *GitBackupStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(GitBackupStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitRevision) DeepCopyInto(out *GitRevision) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitRevision.
+func (in *GitRevision) DeepCopy() *GitRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(GitRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheck) DeepCopyInto(out *HealthCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheck.
+func (in *HealthCheck) DeepCopy() *HealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostKeyPolicy) DeepCopyInto(out *HostKeyPolicy) {
+	*out = *in
+	if in.KnownHostsConfigMapRef != nil {
+		in, out := &in.KnownHostsConfigMapRef, &out.KnownHostsConfigMapRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Fingerprints != nil {
+		in, out := &in.Fingerprints, &out.Fingerprints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostKeyPolicy.
+func (in *HostKeyPolicy) DeepCopy() *HostKeyPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(HostKeyPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaSink) DeepCopyInto(out *KafkaSink) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaSink.
+func (in *KafkaSink) DeepCopy() *KafkaSink {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Lock) DeepCopyInto(out *Lock) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Lock.
+func (in *Lock) DeepCopy() *Lock {
+	if in == nil {
+		return nil
+	}
+	out := new(Lock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Lock) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LockList) DeepCopyInto(out *LockList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Lock, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockList.
+func (in *LockList) DeepCopy() *LockList {
+	if in == nil {
+		return nil
+	}
+	out := new(LockList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LockList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LockSpec) DeepCopyInto(out *LockSpec) {
+	*out = *in
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ExecutionPolicy.DeepCopyInto(&out.ExecutionPolicy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockSpec.
+func (in *LockSpec) DeepCopy() *LockSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LockSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MountPoint) DeepCopyInto(out *MountPoint) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.MountPointStatus.DeepCopyInto(&out.MountPointStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MountPoint.
+func (in *MountPoint) DeepCopy() *MountPoint {
+	if in == nil {
+		return nil
+	}
+	out := new(MountPoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MountPoint) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MountPointBackup) DeepCopyInto(out *MountPointBackup) {
+	*out = *in
+	if in.LastBackupTime != nil {
+		in, out := &in.LastBackupTime, &out.LastBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastChangeTime != nil {
+		in, out := &in.LastChangeTime, &out.LastChangeTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MountPointBackup.
+func (in *MountPointBackup) DeepCopy() *MountPointBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(MountPointBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MountPointList) DeepCopyInto(out *MountPointList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MountPoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MountPointList.
+func (in *MountPointList) DeepCopy() *MountPointList {
+	if in == nil {
+		return nil
+	}
+	out := new(MountPointList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MountPointList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MountPointReference) DeepCopyInto(out *MountPointReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MountPointReference.
+func (in *MountPointReference) DeepCopy() *MountPointReference {
+	if in == nil {
+		return nil
+	}
+	out := new(MountPointReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MountPointSpec) DeepCopyInto(out *MountPointSpec) {
+	*out = *in
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CallHome != nil {
+		in, out := &in.CallHome, &out.CallHome
+		*out = new(CallHome)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.PrivateKeySecretRef != nil {
+		in, out := &in.PrivateKeySecretRef, &out.PrivateKeySecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.AuthMethods != nil {
+		in, out := &in.AuthMethods, &out.AuthMethods
+		*out = make([]AuthMethod, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostKeyPolicy != nil {
+		in, out := &in.HostKeyPolicy, &out.HostKeyPolicy
+		*out = new(HostKeyPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalCapabilities != nil {
+		in, out := &in.AdditionalCapabilities, &out.AdditionalCapabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MountPointSpec.
+func (in *MountPointSpec) DeepCopy() *MountPointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MountPointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MountPointStatus) DeepCopyInto(out *MountPointStatus) {
+	*out = *in
+	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastConnectedTime != nil {
+		in, out := &in.LastConnectedTime, &out.LastConnectedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastDisconnectedTime != nil {
+		in, out := &in.LastDisconnectedTime, &out.LastDisconnectedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextReconnectTime != nil {
+		in, out := &in.NextReconnectTime, &out.NextReconnectTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MountPointStatus.
+func (in *MountPointStatus) DeepCopy() *MountPointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MountPointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkTransaction) DeepCopyInto(out *NetworkTransaction) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.NetworkTransactionStatus.DeepCopyInto(&out.NetworkTransactionStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkTransaction.
+func (in *NetworkTransaction) DeepCopy() *NetworkTransaction {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkTransaction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetworkTransaction) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkTransactionList) DeepCopyInto(out *NetworkTransactionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NetworkTransaction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkTransactionList.
+func (in *NetworkTransactionList) DeepCopy() *NetworkTransactionList {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkTransactionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetworkTransactionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkTransactionSpec) DeepCopyInto(out *NetworkTransactionSpec) {
+	*out = *in
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]TransactionDevice, len(*in))
+		copy(*out, *in)
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make(Dependencies, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ExecutionPolicy.DeepCopyInto(&out.ExecutionPolicy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkTransactionSpec.
+func (in *NetworkTransactionSpec) DeepCopy() *NetworkTransactionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkTransactionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkTransactionStatus) DeepCopyInto(out *NetworkTransactionStatus) {
+	*out = *in
+	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+	if in.ConfirmDeadline != nil {
+		in, out := &in.ConfirmDeadline, &out.ConfirmDeadline
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]TransactionDeviceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkTransactionStatus.
+func (in *NetworkTransactionStatus) DeepCopy() *NetworkTransactionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkTransactionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectFieldSelector) DeepCopyInto(out *ObjectFieldSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectFieldSelector.
+func (in *ObjectFieldSelector) DeepCopy() *ObjectFieldSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectFieldSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationTemplate) DeepCopyInto(out *OperationTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationTemplate.
+func (in *OperationTemplate) DeepCopy() *OperationTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParameterSource) DeepCopyInto(out *ParameterSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = (*in).DeepCopy()
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = (*in).DeepCopy()
+	}
+	if in.ObjectFieldRef != nil {
+		in, out := &in.ObjectFieldRef, &out.ObjectFieldRef
+		*out = new(ObjectFieldSelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParameterSource.
+func (in *ParameterSource) DeepCopy() *ParameterSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ParameterSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeerCertificate) DeepCopyInto(out *PeerCertificate) {
+	*out = *in
+	in.NotBefore.DeepCopyInto(&out.NotBefore)
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPAddresses != nil {
+		in, out := &in.IPAddresses, &out.IPAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeerCertificate.
+func (in *PeerCertificate) DeepCopy() *PeerCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(PeerCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RPC) DeepCopyInto(out *RPC) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RPC.
+func (in *RPC) DeepCopy() *RPC {
+	if in == nil {
+		return nil
+	}
+	out := new(RPC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RPC) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RPCError) DeepCopyInto(out *RPCError) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RPCError.
+func (in *RPCError) DeepCopy() *RPCError {
+	if in == nil {
+		return nil
+	}
+	out := new(RPCError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RPCList) DeepCopyInto(out *RPCList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RPC, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RPCList.
+func (in *RPCList) DeepCopy() *RPCList {
+	if in == nil {
+		return nil
+	}
+	out := new(RPCList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RPCList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RPCSpec) DeepCopyInto(out *RPCSpec) {
+	*out = *in
+	if in.JSON != nil {
+		in, out := &in.JSON, &out.JSON
+		*out = (*in).DeepCopy()
+	}
+	if in.YANGMapping != nil {
+		in, out := &in.YANGMapping, &out.YANGMapping
+		*out = new(YANGMapping)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ExecutionPolicy.DeepCopyInto(&out.ExecutionPolicy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RPCSpec.
+func (in *RPCSpec) DeepCopy() *RPCSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RPCSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RPCStatus) DeepCopyInto(out *RPCStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RPCErrors != nil {
+		in, out := &in.RPCErrors, &out.RPCErrors
+		*out = make([]RPCError, len(*in))
+		copy(*out, *in)
+	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RPCStatus.
+func (in *RPCStatus) DeepCopy() *RPCStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RPCStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreSource) DeepCopyInto(out *RestoreSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = (*in).DeepCopy()
+	}
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitRevision)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreSource.
+func (in *RestoreSource) DeepCopy() *RestoreSource {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	if in.RetryOn != nil {
+		in, out := &in.RetryOn, &out.RetryOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryStatus) DeepCopyInto(out *RetryStatus) {
+	*out = *in
+	if in.NextRetryTime != nil {
+		in, out := &in.NextRetryTime, &out.NextRetryTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryStatus.
+func (in *RetryStatus) DeepCopy() *RetryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledOperation) DeepCopyInto(out *ScheduledOperation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.ScheduledOperationStatus.DeepCopyInto(&out.ScheduledOperationStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledOperation.
+func (in *ScheduledOperation) DeepCopy() *ScheduledOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduledOperation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledOperationList) DeepCopyInto(out *ScheduledOperationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ScheduledOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledOperationList.
+func (in *ScheduledOperationList) DeepCopy() *ScheduledOperationList {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledOperationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduledOperationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledOperationSpec) DeepCopyInto(out *ScheduledOperationSpec) {
+	*out = *in
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	in.Operation.DeepCopyInto(&out.Operation)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledOperationSpec.
+func (in *ScheduledOperationSpec) DeepCopy() *ScheduledOperationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledOperationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledOperationStatus) DeepCopyInto(out *ScheduledOperationStatus) {
+	*out = *in
+	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]ScheduledRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledOperationStatus.
+func (in *ScheduledOperationStatus) DeepCopy() *ScheduledOperationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledOperationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledRun) DeepCopyInto(out *ScheduledRun) {
+	*out = *in
+	in.ScheduleTime.DeepCopyInto(&out.ScheduleTime)
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledRun.
+func (in *ScheduledRun) DeepCopy() *ScheduledRun {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSConfig) DeepCopyInto(out *TLSConfig) {
+	*out = *in
+	out.ClientCertificateSecretRef = in.ClientCertificateSecretRef
+	if in.CABundleSecretRef != nil {
+		in, out := &in.CABundleSecretRef, &out.CABundleSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.CertToName != nil {
+		in, out := &in.CertToName, &out.CertToName
+		*out = make([]CertToName, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSConfig.
+func (in *TLSConfig) DeepCopy() *TLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSStatus) DeepCopyInto(out *TLSStatus) {
+	*out = *in
+	if in.PeerCertificate != nil {
+		in, out := &in.PeerCertificate, &out.PeerCertificate
+		*out = new(PeerCertificate)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSStatus.
+func (in *TLSStatus) DeepCopy() *TLSStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateParameter) DeepCopyInto(out *TemplateParameter) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(ParameterSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateParameter.
+func (in *TemplateParameter) DeepCopy() *TemplateParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransactionDevice) DeepCopyInto(out *TransactionDevice) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransactionDevice.
+func (in *TransactionDevice) DeepCopy() *TransactionDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(TransactionDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransactionDeviceStatus) DeepCopyInto(out *TransactionDeviceStatus) {
+	*out = *in
+	if in.RPCErrors != nil {
+		in, out := &in.RPCErrors, &out.RPCErrors
+		*out = make([]RPCError, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransactionDeviceStatus.
+func (in *TransactionDeviceStatus) DeepCopy() *TransactionDeviceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TransactionDeviceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Unlock) DeepCopyInto(out *Unlock) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Unlock.
+func (in *Unlock) DeepCopy() *Unlock {
+	if in == nil {
+		return nil
+	}
+	out := new(Unlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Unlock) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnlockList) DeepCopyInto(out *UnlockList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Unlock, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnlockList.
+func (in *UnlockList) DeepCopy() *UnlockList {
+	if in == nil {
+		return nil
+	}
+	out := new(UnlockList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UnlockList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnlockSpec) DeepCopyInto(out *UnlockSpec) {
+	*out = *in
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make(Dependencies, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ExecutionPolicy.DeepCopyInto(&out.ExecutionPolicy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnlockSpec.
+func (in *UnlockSpec) DeepCopy() *UnlockSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UnlockSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Validate) DeepCopyInto(out *Validate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Validate.
+func (in *Validate) DeepCopy() *Validate {
+	if in == nil {
+		return nil
+	}
+	out := new(Validate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Validate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidateList) DeepCopyInto(out *ValidateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Validate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidateList.
+func (in *ValidateList) DeepCopy() *ValidateList {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ValidateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidateSpec) DeepCopyInto(out *ValidateSpec) {
+	*out = *in
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make(Dependencies, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ExecutionPolicy.DeepCopyInto(&out.ExecutionPolicy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidateSpec.
+func (in *ValidateSpec) DeepCopy() *ValidateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Workflow) DeepCopyInto(out *Workflow) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.WorkflowStatus.DeepCopyInto(&out.WorkflowStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Workflow.
+func (in *Workflow) DeepCopy() *Workflow {
+	if in == nil {
+		return nil
+	}
+	out := new(Workflow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Workflow) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowList) DeepCopyInto(out *WorkflowList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Workflow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowList.
+func (in *WorkflowList) DeepCopy() *WorkflowList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkflowList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowSpec) DeepCopyInto(out *WorkflowSpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]WorkflowStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OnFailure != nil {
+		in, out := &in.OnFailure, &out.OnFailure
+		*out = make([]WorkflowStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make(Dependencies, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ExecutionPolicy.DeepCopyInto(&out.ExecutionPolicy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowSpec.
+func (in *WorkflowSpec) DeepCopy() *WorkflowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowStatus) DeepCopyInto(out *WorkflowStatus) {
+	*out = *in
+	in.RPCStatus.DeepCopyInto(&out.RPCStatus)
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]WorkflowStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Compensations != nil {
+		in, out := &in.Compensations, &out.Compensations
+		*out = make([]WorkflowStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowStatus.
+func (in *WorkflowStatus) DeepCopy() *WorkflowStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowStep) DeepCopyInto(out *WorkflowStep) {
+	*out = *in
+	if in.RunAfter != nil {
+		in, out := &in.RunAfter, &out.RunAfter
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowStep.
+func (in *WorkflowStep) DeepCopy() *WorkflowStep {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowStepStatus) DeepCopyInto(out *WorkflowStepStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RPCErrors != nil {
+		in, out := &in.RPCErrors, &out.RPCErrors
+		*out = make([]RPCError, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowStepStatus.
+func (in *WorkflowStepStatus) DeepCopy() *WorkflowStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *YANGMapping) DeepCopyInto(out *YANGMapping) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ListKeys != nil {
+		in, out := &in.ListKeys, &out.ListKeys
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new YANGMapping.
+func (in *YANGMapping) DeepCopy() *YANGMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(YANGMapping)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -0,0 +1,295 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"github.com/openshift-telco/go-netconf-client/netconf/message"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"path"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sort"
+	"strings"
+	"time"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// Labels and annotations of the ConfigMaps holding the backups
+const (
+	configBackupLabel = "netconf.openshift-telco.io/config-backup"
+	mountPointLabel   = "netconf.openshift-telco.io/mount-point"
+	hashAnnotation    = "netconf.openshift-telco.io/hash"
+	sourceAnnotation  = "netconf.openshift-telco.io/source"
+	// filterAnnotation holds the filter the backup was taken with: the backup only holds a part of the datastore
+	filterAnnotation = "netconf.openshift-telco.io/filter"
+	// defaultGitImage provides `sh` and `git`, when the Git storage doesn't define its image
+	defaultGitImage = "docker.io/alpine/git:latest"
+	// backupKey holds the configuration, as the content of the data element of the get-config reply
+	backupKey = "config.xml"
+)
+
+// gitCommitScript commits the backups, mounted from a ConfigMap, into the Git repository, initialized when missing.
+const gitCommitScript = `set -e
+mkdir -p "$REPOSITORY"
+cd "$REPOSITORY"
+git config --global --add safe.directory "$REPOSITORY" || true
+[ -d .git ] || git init -q
+for backup in /backups/*.xml; do
+  cp "$backup" .
+  git add "$(basename "$backup")"
+done
+git -c user.name=netconf-operator -c user.email=netconf-operator@openshift-telco.io commit -q -m "$MESSAGE" ||
+  echo "Nothing to commit"
+`
+
+// backupMountPoint backs up the configuration of the MountPoint, and stores it unless it didn't change since the
+// last backup. With the Git storage, the configuration is only recorded in changed, to be committed afterwards.
+func (r *ConfigBackupReconciler) backupMountPoint(
+	obj *netconfv1.ConfigBackup, status *netconfv1.MountPointBackup, now metav1.Time, changed map[string]string,
+) error {
+	config, err := r.fetchConfig(obj, status.MountPoint)
+	if err != nil {
+		return err
+	}
+
+	hash := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(config)))
+	status.LastBackupTime = &now
+	status.Size = int64(len(config))
+	status.Error = ""
+	if hash == status.Hash {
+		// Nothing changed since the last version
+		return nil
+	}
+
+	if obj.Spec.Storage.Git != nil {
+		changed[status.MountPoint] = config
+		status.Location = path.Join(obj.Spec.Storage.Git.Path, status.MountPoint+".xml")
+	} else {
+		status.Location, err = r.storeConfigMap(obj, status.MountPoint, config, hash, now.Time)
+		if err != nil {
+			return err
+		}
+	}
+	status.Hash = hash
+	status.LastChangeTime = &now
+	return nil
+}
+
+// fetchConfig retrieves the configuration of the MountPoint, as the content of the data element of the reply.
+func (r *ConfigBackupReconciler) fetchConfig(obj *netconfv1.ConfigBackup, mountPoint string) (string, error) {
+	exists := CheckMountPointExists(
+		r.ReconcilerBase, r.sessions, types.NamespacedName{Namespace: obj.Namespace, Name: mountPoint},
+	)
+	if !exists {
+		return "", mountPointUnavailable(mountPoint)
+	}
+
+	datastore := obj.Spec.Source
+	if datastore == "" {
+		datastore = message.DatastoreRunning
+	}
+	source, err := configTarget(
+		netconfv1.ConfigTarget{Datastore: datastore},
+		r.sessions.Capabilities(obj.GetMountPointNamespacedName(mountPoint)),
+	)
+	if err != nil {
+		return "", err
+	}
+	filter := ""
+	if obj.Spec.Filter != "" {
+		if _, err := parseXML(obj.Spec.Filter); err != nil {
+			return "", fmt.Errorf("invalid filter: %w", err)
+		}
+		filter = `<filter type="subtree">` + obj.Spec.Filter + `</filter>`
+	}
+
+	s, err := r.sessions.Acquire(obj.GetMountPointNamespacedName(mountPoint))
+	if err != nil {
+		return "", mountPointUnavailable(mountPoint)
+	}
+	defer r.sessions.Release(s)
+
+	reply, err := s.SyncRPC(
+		message.NewRPC(fmt.Sprintf("<get-config><source>%s</source>%s</get-config>", source, filter)),
+		obj.Spec.Timeout,
+	)
+	err = checkReply(reply, err)
+	if err != nil {
+		setReplyStatus(&obj.RPCStatus, reply, err)
+		return "", err
+	}
+	config, err := dataXML(reply.RawReply)
+	if err != nil {
+		return "", fmt.Errorf("invalid get-config reply: %w", err)
+	}
+	return config, nil
+}
+
+// storeConfigMap stores the version of the configuration in a new ConfigMap, and deletes the oldest versions
+// beyond the retention count.
+func (r *ConfigBackupReconciler) storeConfigMap(
+	obj *netconfv1.ConfigBackup, mountPoint string, config string, hash string, now time.Time,
+) (string, error) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: obj.Namespace,
+			Name:      fmt.Sprintf("configbackup-%s-%s-%s", obj.Name, mountPoint, now.UTC().Format("20060102150405")),
+			Labels:    map[string]string{configBackupLabel: obj.Name, mountPointLabel: mountPoint},
+			Annotations: map[string]string{
+				hashAnnotation:   hash,
+				sourceAnnotation: obj.Spec.Source,
+			},
+		},
+		Data: map[string]string{backupKey: config},
+	}
+	if obj.Spec.Filter != "" {
+		configMap.Annotations[filterAnnotation] = obj.Spec.Filter
+	}
+	// Garbage collected along with the ConfigBackup
+	err := controllerutil.SetControllerReference(obj, configMap, r.GetScheme())
+	if err != nil {
+		return "", err
+	}
+	err = r.GetClient().Create(context.Background(), configMap)
+	if err != nil {
+		return "", fmt.Errorf("failed to create ConfigMap %s: %w", configMap.Name, err)
+	}
+
+	configMaps := &corev1.ConfigMapList{}
+	err = r.GetClient().List(
+		context.Background(), configMaps, client.InNamespace(obj.Namespace),
+		client.MatchingLabels{configBackupLabel: obj.Name, mountPointLabel: mountPoint},
+	)
+	if err != nil {
+		return configMap.Name, fmt.Errorf("failed to list the backups: %w", err)
+	}
+	versions := configMaps.Items
+	// The name ends with the time of the backup
+	sort.Slice(
+		versions, func(i, j int) bool {
+			return versions[i].Name > versions[j].Name
+		},
+	)
+	retention := int(obj.Spec.Storage.ConfigMap.Retention)
+	if retention <= 0 {
+		retention = 10
+	}
+	for i := retention; i < len(versions); i++ {
+		err = r.GetClient().Delete(context.Background(), &versions[i])
+		if client.IgnoreNotFound(err) != nil {
+			return configMap.Name, fmt.Errorf("failed to delete the backup %s: %w", versions[i].Name, err)
+		}
+	}
+	return configMap.Name, nil
+}
+
+// commitGit creates a Job committing the configurations into the Git repository. The configurations are mounted
+// from a ConfigMap, garbage collected along with the Job.
+func (r *ConfigBackupReconciler) commitGit(
+	obj *netconfv1.ConfigBackup, configs map[string]string, now time.Time,
+) (string, error) {
+	git := obj.Spec.Storage.Git
+	name := fmt.Sprintf("configbackup-%s-%d", obj.Name, now.Unix())
+	files := make(map[string]string, len(configs))
+	var mountPoints []string
+	for mountPoint, config := range configs {
+		files[mountPoint+".xml"] = config
+		mountPoints = append(mountPoints, mountPoint)
+	}
+	sort.Strings(mountPoints)
+	image := git.Image
+	if image == "" {
+		image = defaultGitImage
+	}
+
+	backoffLimit := int32(2)
+	ttl := int32(3600)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: obj.Namespace,
+			Name:      name,
+			Labels:    map[string]string{configBackupLabel: obj.Name},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    "git",
+							Image:   image,
+							Command: []string{"sh", "-c", gitCommitScript},
+							Env: []corev1.EnvVar{
+								{Name: "REPOSITORY", Value: path.Join("/repository", git.Path)},
+								{
+									Name: "MESSAGE",
+									Value: fmt.Sprintf(
+										"Backup of %s at %s", strings.Join(mountPoints, ", "),
+										now.UTC().Format(time.RFC3339),
+									),
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "repository", MountPath: "/repository"},
+								{Name: "backups", MountPath: "/backups", ReadOnly: true},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "repository",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: git.PersistentVolumeClaimName,
+								},
+							},
+						},
+						{
+							Name: "backups",
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{Name: name},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	err := controllerutil.SetControllerReference(obj, job, r.GetScheme())
+	if err != nil {
+		return "", err
+	}
+	err = r.GetClient().Create(context.Background(), job)
+	if err != nil {
+		return "", fmt.Errorf("failed to create Job %s: %w", name, err)
+	}
+
+	// The pod waits for the ConfigMap to be mounted
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: obj.Namespace,
+			Name:      name,
+			Labels:    map[string]string{configBackupLabel: obj.Name},
+		},
+		Data: files,
+	}
+	err = controllerutil.SetControllerReference(job, configMap, r.GetScheme())
+	if err == nil {
+		err = r.GetClient().Create(context.Background(), configMap)
+	}
+	if err != nil {
+		// Otherwise, the pod would wait forever
+		_ = r.GetClient().Delete(context.Background(), job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		return "", fmt.Errorf("failed to create ConfigMap %s: %w", name, err)
+	}
+	return name, nil
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+func TestBackupMountPoint(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+	}{
+		{name: "whole datastore"},
+		{name: "filtered", filter: interfaces()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSession(nil)
+			s.data = make(map[string]string)
+			mountPoint := &netconfv1.MountPoint{ObjectMeta: metav1.ObjectMeta{Name: "device"}}
+			c := &testClient{objects: map[string]client.Object{"device": mountPoint}}
+			r := &ConfigBackupReconciler{ReconcilerBase: newTestReconcilerBase(c), sessions: s}
+			obj := &netconfv1.ConfigBackup{
+				ObjectMeta: metav1.ObjectMeta{Name: "backup"},
+				Spec: netconfv1.ConfigBackupSpec{
+					Filter:  tt.filter,
+					Storage: netconfv1.BackupStorage{ConfigMap: &netconfv1.ConfigMapBackupStorage{Retention: 2}},
+				},
+			}
+			status := &netconfv1.MountPointBackup{MountPoint: "device"}
+
+			// Three versions, only the last two being kept
+			for i, mtu := range []string{"1500", "9000", "1400"} {
+				s.data["get-config"] = interfaces("<name>eth0</name><mtu>" + mtu + "</mtu>")
+				now := metav1.NewTime(time.Date(2026, 10, 17, 2, 0, i, 0, time.UTC))
+				if err := r.backupMountPoint(obj, status, now, nil); err != nil {
+					t.Fatalf("backupMountPoint() error = %v", err)
+				}
+			}
+
+			if got := s.rpcs[0]; strings.Contains(got, "<filter") != (tt.filter != "") {
+				t.Errorf("backupMountPoint() sent %s, want filter %q", got, tt.filter)
+			}
+			var versions []string
+			for name, obj := range c.objects {
+				configMap, ok := obj.(*corev1.ConfigMap)
+				if !ok {
+					continue
+				}
+				versions = append(versions, name)
+				if got := configMap.Annotations[filterAnnotation]; got != tt.filter {
+					t.Errorf("ConfigMap %s has the filter annotation %q, want %q", name, got, tt.filter)
+				}
+			}
+			if len(versions) != 2 || c.objects[status.Location] == nil {
+				t.Errorf("backupMountPoint() kept %v, want the last two versions, %s included", versions, status.Location)
+			}
+		})
+	}
+}
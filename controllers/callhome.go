@@ -0,0 +1,552 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/go-logr/logr"
+	"github.com/openshift-telco/go-netconf-client/netconf"
+	"golang.org/x/crypto/ssh"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"net"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"strings"
+	"sync"
+	"time"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=callhomedevices,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=callhomedevices/status,verbs=get;update;patch
+
+// callHomeHandshakeTimeout bounds the SSH or TLS handshake with a device calling home
+const callHomeHandshakeTimeout = 30 * time.Second
+
+// errNotBound is returned when the device calling home isn't bound to any MountPoint
+var errNotBound = errors.New("device isn't bound to any MountPoint")
+
+// errTooManyPendingDevices is returned when an unknown device calls home while the CallHomeDevice pending approval
+// already reach their maximum
+var errTooManyPendingDevices = errors.New("too many devices pending approval")
+
+// CallHomeOptions configures the NETCONF Call Home listeners
+type CallHomeOptions struct {
+	// The address to listen on for NETCONF over SSH Call Home, e.g. `:4334`. Empty to disable, by default.
+	SSHAddress string
+	// The address to listen on for NETCONF over TLS Call Home, e.g. `:4335`. Empty to disable, by default.
+	TLSAddress string
+	// The namespace in which the CallHomeDevice of the unknown devices are created
+	Namespace string
+	// The maximum number of CallHomeDevice pending approval. Beyond, the unknown devices calling home are refused
+	// without being recorded.
+	MaxPendingDevices int
+	// The username initially used to authenticate against a device calling home over SSH. As SSH requires the
+	// username before the device is identified by its host key, a device whose MountPoint credentials hold another
+	// username is disconnected, and authenticated using the right username on its next call home.
+	SSHUsername string
+}
+
+// callHomeListener accepts the connections of the NETCONF servers calling home (RFC 8071), identifies them
+// by their SSH host key or TLS certificate, and binds the established sessions to their MountPoint.
+type callHomeListener struct {
+	r       *MountPointReconciler
+	options CallHomeOptions
+	log     logr.Logger
+
+	// Username to use, per remote host, learnt from the previous identification of the device
+	sshUsernamesLock sync.Mutex
+	sshUsernames     map[string]string
+}
+
+// AddCallHome adds the NETCONF Call Home listeners to the Manager.
+func AddCallHome(mgr manager.Manager, options CallHomeOptions) error {
+	if options.SSHAddress == "" && options.TLSAddress == "" {
+		return nil
+	}
+	return mgr.Add(
+		&callHomeListener{
+			r:            newMountPointReconciler(mgr).(*MountPointReconciler),
+			options:      options,
+			log:          logf.Log.WithName(callHomeControllerName),
+			sshUsernames: make(map[string]string),
+		},
+	)
+}
+
+// Start implements manager.Runnable. Being subject to leader election, only the leader accepts devices.
+func (l *callHomeListener) Start(ctx context.Context) error {
+	var listeners []net.Listener
+	defer func() {
+		for _, listener := range listeners {
+			_ = listener.Close()
+		}
+	}()
+
+	if l.options.SSHAddress != "" {
+		listener, err := net.Listen("tcp", l.options.SSHAddress)
+		if err != nil {
+			return err
+		}
+		listeners = append(listeners, listener)
+		l.log.Info(fmt.Sprintf("Listening for NETCONF over SSH Call Home on %s", l.options.SSHAddress))
+		go l.accept(listener, l.serveSSH)
+	}
+	if l.options.TLSAddress != "" {
+		listener, err := net.Listen("tcp", l.options.TLSAddress)
+		if err != nil {
+			return err
+		}
+		listeners = append(listeners, listener)
+		l.log.Info(fmt.Sprintf("Listening for NETCONF over TLS Call Home on %s", l.options.TLSAddress))
+		go l.accept(listener, l.serveTLS)
+	}
+
+	<-ctx.Done()
+	return nil
+}
+
+func (l *callHomeListener) accept(listener net.Listener, serve func(conn net.Conn)) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Temporary() {
+				continue
+			}
+			return
+		}
+		if tcpConn, ok := conn.(*net.TCPConn); ok {
+			_ = tcpConn.SetKeepAlive(true)
+			_ = tcpConn.SetKeepAlivePeriod(15 * time.Second)
+		}
+		go serve(conn)
+	}
+}
+
+// serveSSH authenticates against the device calling home over SSH, using the credentials of its MountPoint.
+func (l *callHomeListener) serveSSH(conn net.Conn) {
+	remote := conn.RemoteAddr().String()
+	log := l.log.WithValues("Remote", remote, "Transport", netconfv1.SSHTransport)
+
+	var obj *netconfv1.MountPoint
+	var creds *credentials
+	var hostKey ssh.PublicKey
+	username := l.sshUsername(remote)
+	config := &ssh.ClientConfig{
+		User: username,
+		HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			hostKey = key
+			mountPoint, err := l.identify(
+				netconfv1.SSHTransport, ssh.FingerprintSHA256(key), remote,
+				func(status *netconfv1.CallHomeDeviceStatus) {
+					status.HostKey = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key)))
+				},
+			)
+			if err != nil {
+				return err
+			}
+			c, err := getCredentials(l.r.ReconcilerBase, mountPoint)
+			if err != nil {
+				return err
+			}
+			if c.username != username {
+				// The username can't be changed past the key exchange, it is used on the next call home.
+				l.setSSHUsername(remote, c.username)
+				return fmt.Errorf(
+					"MountPoint %s expects username %s, instead of %s", mountPoint.GetNamespacedName(), c.username,
+					username,
+				)
+			}
+			obj, creds = mountPoint, c
+			return nil
+		},
+		Auth: []ssh.AuthMethod{
+			ssh.PublicKeysCallback(
+				func() ([]ssh.Signer, error) {
+					if creds == nil || len(creds.privateKey) == 0 {
+						return nil, nil
+					}
+					return sshSigners(creds)
+				},
+			),
+			ssh.KeyboardInteractive(
+				func(user, instruction string, questions []string, echos []bool) ([]string, error) {
+					return passwordChallenge(creds.password)(user, instruction, questions, echos)
+				},
+			),
+			ssh.PasswordCallback(func() (string, error) { return creds.password, nil }),
+		},
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(callHomeHandshakeTimeout))
+	c, channels, requests, err := ssh.NewClientConn(conn, remote, config)
+	if err != nil {
+		log.Info(fmt.Sprintf("Call Home refused: %s", err))
+		return
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	client := ssh.NewClient(c, channels, requests)
+	transport, err := sshTransport(client)
+	if err != nil {
+		log.Error(err, fmt.Sprintf("%s: Failed to open the netconf subsystem", obj.Name))
+		_ = client.Close()
+		return
+	}
+	if obj.Spec.Timeout > 0 {
+		go sshKeepAlive(client, time.Duration(obj.Spec.Timeout)*time.Second)
+	}
+
+	obj.HostKey = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(hostKey)))
+	obj.HostKeyFingerprint = ssh.FingerprintSHA256(hostKey)
+	obj.TLS = nil
+	setHostKeyCondition(obj, nil)
+
+	l.bind(obj, netconf.NewSession(transport), remote, log)
+}
+
+// serveTLS authenticates against the device calling home over TLS, using the client certificate of its MountPoint.
+// The device is identified by its certificate, before its chain is verified using the CA bundle of its MountPoint.
+func (l *callHomeListener) serveTLS(conn net.Conn) {
+	remote := conn.RemoteAddr().String()
+	log := l.log.WithValues("Remote", remote, "Transport", netconfv1.TLSTransport)
+
+	var obj *netconfv1.MountPoint
+	var cert *tls.Certificate
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// The chain is verified by VerifyPeerCertificate, once the device is identified.
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return errors.New("no certificate presented")
+			}
+			certs := make([]*x509.Certificate, len(rawCerts))
+			for i, raw := range rawCerts {
+				parsed, err := x509.ParseCertificate(raw)
+				if err != nil {
+					return err
+				}
+				certs[i] = parsed
+			}
+
+			mountPoint, err := l.identify(
+				netconfv1.TLSTransport, certificateFingerprint(rawCerts[0]), remote,
+				func(status *netconfv1.CallHomeDeviceStatus) {
+					status.PeerCertificate = peerCertificate(certs[0])
+				},
+			)
+			if err != nil {
+				return err
+			}
+			if mountPoint.Spec.TLS == nil {
+				return fmt.Errorf("MountPoint %s has no tls configuration", mountPoint.GetNamespacedName())
+			}
+			tlsConfig, c, err := newTLSConfig(l.r.ReconcilerBase, mountPoint)
+			if err != nil {
+				return err
+			}
+			if !tlsConfig.InsecureSkipVerify {
+				intermediates := x509.NewCertPool()
+				for _, intermediate := range certs[1:] {
+					intermediates.AddCert(intermediate)
+				}
+				_, err = certs[0].Verify(
+					x509.VerifyOptions{
+						Roots:         tlsConfig.RootCAs,
+						Intermediates: intermediates,
+						DNSName:       mountPoint.Spec.TLS.ServerName,
+					},
+				)
+				if err != nil {
+					return err
+				}
+			}
+			obj, cert = mountPoint, c
+			return nil
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			if cert == nil {
+				return &tls.Certificate{}, nil
+			}
+			return cert, nil
+		},
+	}
+
+	tlsConn := tls.Client(conn, config)
+	_ = tlsConn.SetDeadline(time.Now().Add(callHomeHandshakeTimeout))
+	err := tlsConn.Handshake()
+	if err != nil {
+		log.Info(fmt.Sprintf("Call Home refused: %s", err))
+		_ = tlsConn.Close()
+		return
+	}
+	_ = tlsConn.SetDeadline(time.Time{})
+
+	setTLSStatus(obj, tlsConn, cert)
+
+	transport := newConnTransport(tlsConn, time.Duration(obj.Spec.Timeout)*time.Second)
+	l.bind(obj, netconf.NewSession(transport), remote, log)
+}
+
+// bind exchanges the hello-messages over the session established with the device calling home,
+// and binds it to the MountPoint.
+func (l *callHomeListener) bind(obj *netconfv1.MountPoint, session *netconf.Session, remote string, log logr.Logger) {
+	transport, err := l.r.hello(obj, session, log)
+	if err != nil {
+		return
+	}
+	l.r.bind(obj, session, transport)
+
+	log.Info(fmt.Sprintf("%s: Successfully connected, following Call Home.", obj.Name))
+	obj.CallHomeAddress = remote
+	connected := obj.MountPointStatus.DeepCopy()
+	latest, err := updateMountPointStatus(
+		l.r.GetClient(), types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name},
+		func(latest *netconfv1.MountPoint) {
+			latest.MountPointStatus = *connected
+			setConnected(latest)
+		},
+	)
+	if err != nil {
+		log.Error(err, "unable to update status")
+	} else {
+		l.r.GetRecorder().Event(
+			latest, "Normal", string(netconfv1.Connected), fmt.Sprintf("NETCONF server called home from %s", remote),
+		)
+	}
+
+	// Subscriptions don't survive their session.
+	resyncSubscriptions(l.r.GetClient(), obj)
+}
+
+// identify returns the MountPoint the device is bound to, either through the fingerprints of its callHome
+// configuration, or through an approved CallHomeDevice. Otherwise, the device is recorded as a pending
+// CallHomeDevice, and errNotBound is returned.
+func (l *callHomeListener) identify(
+	transport netconfv1.TransportType, fingerprint string, remote string,
+	describe func(status *netconfv1.CallHomeDeviceStatus),
+) (*netconfv1.MountPoint, error) {
+	mountPoints := &netconfv1.MountPointList{}
+	err := l.r.GetClient().List(context.Background(), mountPoints)
+	if err != nil {
+		return nil, err
+	}
+	for i := range mountPoints.Items {
+		mountPoint := &mountPoints.Items[i]
+		if mountPoint.Spec.CallHome == nil || transportOf(mountPoint) != transport {
+			continue
+		}
+		for _, expected := range mountPoint.Spec.CallHome.Fingerprints {
+			if fingerprintEqual(transport, expected, fingerprint) {
+				return mountPoint, nil
+			}
+		}
+	}
+
+	device, err := l.recordDevice(transport, fingerprint, remote, describe)
+	if err != nil {
+		return nil, err
+	}
+	if !device.Spec.Approved || device.Spec.MountPointRef == nil {
+		l.log.Info(fmt.Sprintf("Call Home from unknown device %s, pending approval of %s", fingerprint, device.Name))
+		return nil, errNotBound
+	}
+
+	mountPoint := &netconfv1.MountPoint{}
+	err = l.r.GetClient().Get(context.Background(), device.GetMountPointNamespacedName(), mountPoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MountPoint %s of %s: %w", device.GetMountPointNamespacedName(), device.Name, err)
+	}
+	if mountPoint.Spec.CallHome == nil || transportOf(mountPoint) != transport {
+		return nil, fmt.Errorf(
+			"MountPoint %s of %s isn't in Call Home mode over %s", mountPoint.GetNamespacedName(), device.Name, transport,
+		)
+	}
+	return mountPoint, nil
+}
+
+// recordDevice creates or updates the CallHomeDevice of the device calling home.
+func (l *callHomeListener) recordDevice(
+	transport netconfv1.TransportType, fingerprint string, remote string,
+	describe func(status *netconfv1.CallHomeDeviceStatus),
+) (*netconfv1.CallHomeDevice, error) {
+	key := types.NamespacedName{Namespace: l.options.Namespace, Name: callHomeDeviceName(transport, fingerprint)}
+	device := &netconfv1.CallHomeDevice{}
+	err := l.r.GetClient().Get(context.Background(), key, device)
+	if apierrors.IsNotFound(err) {
+		err = l.checkPendingDevices()
+		if err != nil {
+			return nil, err
+		}
+		device = &netconfv1.CallHomeDevice{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+		err = l.r.GetClient().Create(context.Background(), device)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := metav1.Now()
+	if device.Status.FirstSeenTime == nil {
+		device.Status.FirstSeenTime = &now
+	}
+	device.Status.LastSeenTime = &now
+	device.Status.Transport = transport
+	device.Status.Fingerprint = fingerprint
+	device.Status.RemoteAddress = remote
+	device.Status.Phase = netconfv1.PendingCallHomeDevice
+	if device.Spec.Approved && device.Spec.MountPointRef != nil {
+		device.Status.Phase = netconfv1.ApprovedCallHomeDevice
+	}
+	describe(&device.Status)
+	err = l.r.GetClient().Status().Update(context.Background(), device)
+	if err != nil {
+		l.log.Error(err, "unable to update status", "CallHomeDevice", key.String())
+	}
+	return device, nil
+}
+
+// checkPendingDevices returns errTooManyPendingDevices when the CallHomeDevice pending approval reach their maximum,
+// so that a flood of unknown devices can't fill the namespace.
+func (l *callHomeListener) checkPendingDevices() error {
+	if l.options.MaxPendingDevices <= 0 {
+		return nil
+	}
+	devices := &netconfv1.CallHomeDeviceList{}
+	err := l.r.GetClient().List(context.Background(), devices, client.InNamespace(l.options.Namespace))
+	if err != nil {
+		return err
+	}
+	pending := 0
+	for i := range devices.Items {
+		if !devices.Items[i].Spec.Approved {
+			pending++
+		}
+	}
+	if pending >= l.options.MaxPendingDevices {
+		return fmt.Errorf("%w: %d CallHomeDevice to approve, or delete", errTooManyPendingDevices, pending)
+	}
+	return nil
+}
+
+// sshUsername returns the username to use for the device calling home from the remote address.
+func (l *callHomeListener) sshUsername(remote string) string {
+	l.sshUsernamesLock.Lock()
+	defer l.sshUsernamesLock.Unlock()
+	if username, ok := l.sshUsernames[remoteHost(remote)]; ok {
+		return username
+	}
+	return l.options.SSHUsername
+}
+
+func (l *callHomeListener) setSSHUsername(remote string, username string) {
+	l.sshUsernamesLock.Lock()
+	defer l.sshUsernamesLock.Unlock()
+	l.sshUsernames[remoteHost(remote)] = username
+}
+
+// sshTransport opens the netconf subsystem over the SSH connection.
+func sshTransport(client *ssh.Client) (*framedTransport, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	writer, err := session.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	reader, err := session.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	err = session.RequestSubsystem("netconf")
+	if err != nil {
+		return nil, err
+	}
+	return newFramedTransport(
+		reader, writer, func() error {
+			_ = session.Close()
+			return client.Close()
+		},
+	), nil
+}
+
+// sshKeepAlive sends keepalives until the connection is closed. A missing answer closes the connection,
+// which surfaces as a receive error on the session.
+func sshKeepAlive(client *ssh.Client, timeout time.Duration) {
+	ticker := time.NewTicker(timeout / 2)
+	defer ticker.Stop()
+	for range ticker.C {
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		if err != nil {
+			_ = client.Close()
+			return
+		}
+	}
+}
+
+func transportOf(obj *netconfv1.MountPoint) netconfv1.TransportType {
+	if obj.Spec.Transport == "" {
+		return netconfv1.SSHTransport
+	}
+	return obj.Spec.Transport
+}
+
+// fingerprintEqual compares fingerprints, as displayed by `ssh-keygen -l` for SSH host keys,
+// or in hexadecimal, colon separated or not, for TLS certificates.
+func fingerprintEqual(transport netconfv1.TransportType, a string, b string) bool {
+	if transport == netconfv1.TLSTransport {
+		normalize := func(fingerprint string) string {
+			return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(fingerprint), ":", ""))
+		}
+		return normalize(a) == normalize(b)
+	}
+	return normalizeFingerprint(a) == normalizeFingerprint(b)
+}
+
+// callHomeDeviceName derives a stable object name from the fingerprint of the device.
+func callHomeDeviceName(transport netconfv1.TransportType, fingerprint string) string {
+	hash := sha256.Sum256([]byte(fingerprint))
+	return fmt.Sprintf("%s-%x", transport, hash[:8])
+}
+
+func remoteHost(remote string) string {
+	host, _, err := net.SplitHostPort(remote)
+	if err != nil {
+		return remote
+	}
+	return host
+}
+
+// awaitCallHome checks the credentials of a MountPoint in Call Home mode. The session is established
+// once the NETCONF server calls home.
+func (r *MountPointReconciler) awaitCallHome(obj *netconfv1.MountPoint, log logr.Logger) error {
+	var err error
+	if transportOf(obj) == netconfv1.TLSTransport {
+		_, _, err = newTLSConfig(r.ReconcilerBase, obj)
+	} else {
+		_, err = getCredentials(r.ReconcilerBase, obj)
+	}
+	setCredentialsCondition(obj, err)
+	if err != nil {
+		log.Error(err, fmt.Sprintf("%s: Failed to load credentials.", obj.Name))
+		if !r.sessions.Healthy(obj.GetNamespacedName()) {
+			setNotConnected(obj, netconfv1.AwaitingCallHome, err.Error())
+		}
+		return err
+	}
+
+	if !r.sessions.Healthy(obj.GetNamespacedName()) {
+		log.Info(fmt.Sprintf("%s: Waiting for the NETCONF server to call home.", obj.Name))
+		setNotConnected(obj, netconfv1.AwaitingCallHome, "Waiting for the NETCONF server to call home")
+	}
+	return nil
+}
@@ -25,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -45,6 +46,7 @@ import (
 // CommitReconciler reconciles a Commit object
 type CommitReconciler struct {
 	util.ReconcilerBase
+	sessions SessionProvider
 }
 
 // AddCommit Add creates a new MountPoint Controller and adds it to the Manager.
@@ -73,8 +75,18 @@ func (r *CommitReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return r.ManageError(ctx, instance, err)
 	}
 
+	// The operation is executed once per spec, unless executed again through its run-id
+	execute, result, err := manageExecution(
+		ctx, r.ReconcilerBase, instance, instance.Spec, instance.Spec.ExecutionPolicy, &instance.RPCStatus,
+		func() { instance.ConfirmedCommitGeneration = 0 }, log,
+	)
+	if !execute {
+		return result, err
+	}
+
 	// Managing CR validation
 	if ok, err := r.isValid(instance); !ok {
+		setFailed(instance, err, netconfv1.CommittedCondition)
 		return r.ManageErrorWithRequeue(ctx, instance, err, 2*time.Second)
 	}
 
@@ -93,12 +105,24 @@ func (r *CommitReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return reconcile.Result{}, nil
 	}
 
+	// Wait for the retry scheduled following a transient failure, if any
+	if wait := startAttempt(instance.Spec.RetryPolicy, &instance.RPCStatus, instance.Generation); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
 	err = r.manageOperatorLogic(instance, log)
 	if err != nil {
-		return r.ManageError(ctx, instance, err)
+		return manageRetry(ctx, r.ReconcilerBase, instance, instance.Spec.RetryPolicy, &instance.RPCStatus, err)
 	}
 
-	return r.ManageSuccess(ctx, instance)
+	result, err = manageCompletion(
+		ctx, r.ReconcilerBase, instance, instance.Spec.ExecutionPolicy, &instance.RPCStatus,
+	)
+	// Next step of the confirmed commit, if any
+	if requeue := confirmedCommitRequeue(commitConfirmation(instance)); err == nil && requeue > 0 {
+		result.RequeueAfter = requeue
+	}
+	return result, err
 }
 
 func (r *CommitReconciler) isInitialized(obj metav1.Object) bool {
@@ -116,39 +140,48 @@ func (r *CommitReconciler) isValid(obj metav1.Object) (bool, error) {
 	}
 
 	exists := CheckMountPointExists(
-		r.ReconcilerBase,
+		r.ReconcilerBase, r.sessions,
 		types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.MountPoint},
 	)
 	if !exists {
-		return false, fmt.Errorf("MountPoint %s doesn't exists", instance.Spec.MountPoint)
-	}
-
-	if !instance.Spec.DependsOn.IsNil() {
-		err := validateDependency(r.ReconcilerBase, instance.Namespace, instance.Spec.DependsOn)
-		if err != nil {
-			return false, err
-		}
+		return false, mountPointUnavailable(instance.Spec.MountPoint)
 	}
 
 	return true, nil
 }
 
 func (r *CommitReconciler) manageOperatorLogic(obj *netconfv1.Commit, log logr.Logger) error {
+	ok, err := checkDependencies(r.ReconcilerBase, obj, obj.Spec.DependsOn, log, netconfv1.CommittedCondition)
+	if !ok {
+		return err
+	}
+
+	if obj.Spec.Confirmed != nil {
+		return r.manageConfirmedCommit(obj, log)
+	}
+
 	log.Info(fmt.Sprintf("%s: Execute Commit operation %s.", obj.Spec.MountPoint, obj.Name))
 
-	s := Sessions[obj.GetMountPointNamespacedName(obj.Spec.MountPoint)]
+	s, err := r.sessions.Acquire(obj.GetMountPointNamespacedName(obj.Spec.MountPoint))
+	if err != nil {
+		err = mountPointUnavailable(obj.Spec.MountPoint)
+		setFailed(obj, err, netconfv1.CommittedCondition)
+		return err
+	}
+	defer r.sessions.Release(s)
 	reply, err := s.SyncRPC(message.NewCommit(), obj.Spec.Timeout)
 
-	if err != nil || reply.Errors != nil {
+	err = checkReply(reply, err)
+	if err != nil {
 		log.Info(fmt.Sprintf("%s: Failed to Commit %s", obj.Spec.MountPoint, obj.Name))
-		obj.Status = "failed"
-		obj.RpcReply = reply.RawReply
+		setReplyStatus(&obj.RPCStatus, reply, err)
+		setFailed(obj, err, netconfv1.CommittedCondition)
 		return err
 	}
 
 	log.Info(fmt.Sprintf("%s: Successfully executed Commit operation %s.", obj.Spec.MountPoint, obj.Name))
-	obj.Status = "success"
-	obj.RpcReply = reply.Data
+	setReplyStatus(&obj.RPCStatus, reply, nil)
+	setSucceeded(obj, netconfv1.CommittedCondition)
 	return nil
 
 }
@@ -159,6 +192,7 @@ func newCommitReconciler(mgr manager.Manager) reconcile.Reconciler {
 			mgr.GetClient(), mgr.GetScheme(), mgr.GetConfig(), mgr.GetEventRecorderFor(commitControllerName),
 			mgr.GetAPIReader(),
 		),
+		sessions: sessions,
 	}
 	return c
 }
@@ -171,11 +205,19 @@ func addCommit(mgr manager.Manager, r reconcile.Reconciler) error {
 
 	err = c.Watch(
 		&source.Kind{Type: &netconfv1.Commit{}}, &handler.EnqueueRequestForObject{},
-		util.ResourceGenerationOrFinalizerChangedPredicate{},
+		executionPredicate,
 	)
 	if err != nil {
 		return err
 	}
 
+	// Watch over the dependencies, to start the Commit once they are Ready
+	err = watchDependencies(
+		mgr, c, &netconfv1.Commit{}, &netconfv1.CommitList{},
+		func(obj client.Object) netconfv1.Dependencies { return obj.(*netconfv1.Commit).Spec.DependsOn },
+	)
+	if err != nil {
+		return err
+	}
 	return nil
 }
@@ -4,14 +4,16 @@ import (
 	"context"
 	"encoding/xml"
 	"fmt"
-	"github.com/openshift-telco/go-netconf-client/netconf"
 	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
 	"github.com/redhat-cop/operator-utils/pkg/util"
 	"github.com/segmentio/kafka-go"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"log"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
 	"time"
 )
 
@@ -22,19 +24,26 @@ const getConfigControllerName = "get-config"
 const editConfigControllerName = "edit-config"
 const lockControllerName = "lock"
 const unlockControllerName = "unlock"
+const validateControllerName = "validate"
+const discardChangesControllerName = "discard-changes"
+const copyConfigControllerName = "copy-config"
+const deleteConfigControllerName = "delete-config"
+const configBackupControllerName = "config-backup"
+const configRestoreControllerName = "config-restore"
+const workflowControllerName = "workflow"
+const networkTransactionControllerName = "network-transaction"
+const scheduledOperationControllerName = "scheduled-operation"
 const rpcControllerName = "RPC"
 const createSubscriptionControllerName = "create-subscription"
 const establishSubscriptionControllerName = "establish-subscription"
+const callHomeControllerName = "call-home"
 
 const mountpointFinalizer = "io.openshift-telco.netconf.mountpoint.finalizer"
 const establishSubscriptionFinalizer = "io.openshift-telco.netconf.establishsubscription.finalizer"
+const editConfigFinalizer = "io.openshift-telco.netconf.editconfig.finalizer"
 
-// Sessions hold the active SSH session to NETCONF servers.
-// The key is the NamespacedName of the MountPoint object referred in the CR
-var Sessions = make(map[string]*netconf.Session)
-
-// CheckMountPointExists validates a MountPoint, defined by its namespacedName, exists
-func CheckMountPointExists(r util.ReconcilerBase, namespacedName types.NamespacedName) bool {
+// CheckMountPointExists validates a MountPoint, defined by its namespacedName, exists and has a healthy session
+func CheckMountPointExists(r util.ReconcilerBase, sessions SessionProvider, namespacedName types.NamespacedName) bool {
 	// Check MountPoint CRD exists
 	instance := &netconfv1.MountPoint{}
 	err := r.GetClient().Get(context.Background(), namespacedName, instance)
@@ -42,13 +51,9 @@ func CheckMountPointExists(r util.ReconcilerBase, namespacedName types.Namespace
 		return false
 	}
 
-	// Check MountPoint session exists
-	if _, ok := Sessions[instance.GetNamespacedName()]; ok {
-		//TODO check session is still connected, or reconnect session
-	} else {
-		return false
-	}
-	return true
+	// Check MountPoint session exists, and is connected. When the session is lost, the MountPoint
+	// supervisor takes care of reconnecting it.
+	return sessions.Healthy(instance.GetNamespacedName())
 }
 
 // ValidateXML checks a provided string can be properly unmarshall in the specified struct
@@ -86,56 +91,59 @@ func SendToKafka(message string, kafkaSync netconfv1.KafkaSink) error {
 	return nil
 }
 
+// validateDependency checks the dependency exists, and is Ready. The returned error carries the reason
+// to report on the DependencyBlocked condition.
 func validateDependency(r util.ReconcilerBase, namespace string, dep netconfv1.DependsOn) error {
-
-	var instance client.Object
-
-	switch dep.Kind {
-	case "Commit":
-		instance = &netconfv1.Commit{}
-	case "EditConfig":
-		instance = &netconfv1.EditConfig{}
-	case "Lock":
-		instance = &netconfv1.Lock{}
-	default:
-		return fmt.Errorf(
-			"invalid dependendy. Only Commit, EditConfig and Lock are supported. %s was provided", dep.Kind,
-		)
+	newInstance, ok := dependencyKinds[dep.Kind]
+	if !ok {
+		return &conditionError{
+			reason: netconfv1.InvalidDependencyReason,
+			message: fmt.Sprintf(
+				"invalid dependendy. Only %s are supported. %s was provided",
+				strings.Join(dependencyKindNames(), ", "), dep.Kind,
+			),
+		}
 	}
+	instance := newInstance()
 	err := validateExist(r, namespace, dep, instance)
 	if err != nil {
 		return err
 	}
 
-	switch dep.Kind {
-	case "Commit":
-		i, _ := instance.(*netconfv1.Commit)
-		return validateStatus(i.Status, dep.Name, namespace)
-	case "EditConfig":
-		i, _ := instance.(*netconfv1.EditConfig)
-		return validateStatus(i.Status, dep.Name, namespace)
-	case "Lock":
-		i, _ := instance.(*netconfv1.Lock)
-		return validateStatus(i.Status, dep.Name, namespace)
-	}
-
-	return nil
+	return validateStatus(instance, dep.Name, namespace)
 }
 
 func validateExist(r util.ReconcilerBase, namespace string, dep netconfv1.DependsOn, instance client.Object) error {
 	err := r.GetClient().Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: dep.Name}, instance)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
-			return fmt.Errorf("provided resource %s not found in namespace %s", dep.Name, namespace)
+			return &conditionError{
+				reason:  netconfv1.DependencyNotFoundReason,
+				message: fmt.Sprintf("provided resource %s not found in namespace %s", dep.Name, namespace),
+			}
 		}
-		return fmt.Errorf("failed to read resource %s from namespace %s", dep.Name, namespace)
+		return fmt.Errorf("failed to read resource %s from namespace %s: %w", dep.Name, namespace, err)
 	}
 	return nil
 }
 
-func validateStatus(status string, name string, namespace string) error {
-	if status == "success" {
+func validateStatus(instance conditionsAware, name string, namespace string) error {
+	if isConditionTrue(instance, netconfv1.ReadyCondition) {
 		return nil
 	}
-	return fmt.Errorf("Dependent resource %s from namespace %s is in %s state", name, namespace, status)
+	ready := meta.FindStatusCondition(instance.GetConditions(), netconfv1.ReadyCondition)
+	if dependencyFailed(instance) {
+		return &conditionError{
+			reason:  netconfv1.DependencyFailedReason,
+			message: fmt.Sprintf("Dependent resource %s from namespace %s failed (%s)", name, namespace, ready.Reason),
+		}
+	}
+	reason := "not Ready"
+	if ready != nil && ready.Status == metav1.ConditionFalse {
+		reason = fmt.Sprintf("not Ready (%s)", ready.Reason)
+	}
+	return &conditionError{
+		reason:  netconfv1.DependencyNotReadyReason,
+		message: fmt.Sprintf("Dependent resource %s from namespace %s is %s", name, namespace, reason),
+	}
 }
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// conditionsAware is implemented by every kind, through their RPCStatus
+type conditionsAware interface {
+	GetGeneration() int64
+	GetConditions() []metav1.Condition
+	SetConditions(conditions []metav1.Condition)
+}
+
+// conditionError carries the reason to report on the conditions.
+type conditionError struct {
+	reason  string
+	message string
+}
+
+func (e *conditionError) Error() string {
+	return e.message
+}
+
+func mountPointUnavailable(mountPoint string) error {
+	return &conditionError{
+		reason:  netconfv1.MountPointUnavailableReason,
+		message: fmt.Sprintf("MountPoint %s doesn't exists, or has no established session", mountPoint),
+	}
+}
+
+// conditionReason returns the reason to report for the error: for an rpc-error, its error-tag, e.g. `LockDenied`.
+func conditionReason(err error) string {
+	var condErr *conditionError
+	if errors.As(err, &condErr) {
+		return condErr.reason
+	}
+	var replyErr *rpcReplyError
+	if errors.As(err, &replyErr) {
+		return errorTagReason(replyErr.errors[0].Tag)
+	}
+	var transportErr *rpcTransportError
+	if errors.As(err, &transportErr) {
+		if transportErr.category == netconfv1.TimeoutErrorCategory {
+			return netconfv1.TimeoutReason
+		}
+		return netconfv1.TransportErrorReason
+	}
+	return netconfv1.FailedReason
+}
+
+// errorTagReason converts an error-tag, e.g. `lock-denied`, into a condition reason, e.g. `LockDenied`.
+func errorTagReason(tag string) string {
+	var reason strings.Builder
+	for _, word := range strings.FieldsFunc(
+		tag, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+		},
+	) {
+		reason.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	if reason.Len() == 0 || !(reason.String()[0] >= 'A' && reason.String()[0] <= 'Z') {
+		return netconfv1.OperationFailedReason
+	}
+	return reason.String()
+}
+
+// setConditions sets the conditions, for the current generation of the object.
+func setConditions(
+	obj conditionsAware, status metav1.ConditionStatus, reason string, message string, conditionTypes ...string,
+) {
+	conditions := obj.GetConditions()
+	for _, conditionType := range conditionTypes {
+		meta.SetStatusCondition(
+			&conditions, metav1.Condition{
+				Type:               conditionType,
+				Status:             status,
+				ObservedGeneration: obj.GetGeneration(),
+				Reason:             reason,
+				Message:            message,
+			},
+		)
+	}
+	obj.SetConditions(conditions)
+}
+
+// isConditionTrue reports whether the condition is true, for the current generation of the object.
+func isConditionTrue(obj conditionsAware, conditionType string) bool {
+	condition := meta.FindStatusCondition(obj.GetConditions(), conditionType)
+	return condition != nil && condition.Status == metav1.ConditionTrue &&
+		condition.ObservedGeneration == obj.GetGeneration()
+}
+
+// setSucceeded reports the conditions, along with Ready, as true.
+func setSucceeded(obj conditionsAware, conditionTypes ...string) {
+	setConditions(
+		obj, metav1.ConditionTrue, netconfv1.SucceededReason, "", append(conditionTypes, netconfv1.ReadyCondition)...,
+	)
+}
+
+// setFailed reports the conditions, along with Ready, as false, using the reason derived from the error.
+func setFailed(obj conditionsAware, err error, conditionTypes ...string) {
+	setConditions(
+		obj, metav1.ConditionFalse, conditionReason(err), err.Error(),
+		append(conditionTypes, netconfv1.ReadyCondition)...,
+	)
+}
+
+// setDependencyBlocked reports whether the dependency is blocking the operation.
+func setDependencyBlocked(obj conditionsAware, err error) {
+	if err == nil {
+		setConditions(
+			obj, metav1.ConditionFalse, netconfv1.DependencyReadyReason, "", netconfv1.DependencyBlockedCondition,
+		)
+		return
+	}
+	setConditions(obj, metav1.ConditionTrue, conditionReason(err), err.Error(), netconfv1.DependencyBlockedCondition)
+}
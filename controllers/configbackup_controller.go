@@ -0,0 +1,289 @@
+/*
+Copyright 2021. Alexis de Talhouët
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"strings"
+	"time"
+
+	"github.com/redhat-cop/operator-utils/pkg/util"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=configbackups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=configbackups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=configbackups/finalizers,verbs=update
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+
+// ConfigBackupReconciler reconciles a ConfigBackup object
+type ConfigBackupReconciler struct {
+	util.ReconcilerBase
+	sessions SessionProvider
+}
+
+// AddConfigBackup creates a new ConfigBackup Controller and adds it to the Manager.
+func AddConfigBackup(mgr manager.Manager) error {
+	return addConfigBackup(mgr, newConfigBackupReconciler(mgr))
+}
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *ConfigBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var log = logf.Log.WithName(configBackupControllerName)
+
+	reqLogger := log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+	reqLogger.Info("Reconciling ConfigBackup")
+
+	// Fetch the CRD instance
+	instance := &netconfv1.ConfigBackup{}
+	err := r.GetClient().Get(context.Background(), req.NamespacedName, instance)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("ConfigBackup resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		log.Error(err, "Failed to get ConfigBackup")
+		return r.ManageError(ctx, instance, err)
+	}
+
+	// Managing CR Finalization
+	if util.IsBeingDeleted(instance) {
+		return reconcile.Result{}, nil
+	}
+
+	err = r.manageOperatorLogic(instance, log)
+	if err != nil {
+		return r.ManageError(ctx, instance, err)
+	}
+
+	result, err := r.ManageSuccess(ctx, instance)
+	if err == nil && instance.NextScheduleTime != nil {
+		// Wait for the next schedule
+		result.RequeueAfter = time.Until(instance.NextScheduleTime.Time) + time.Second
+	}
+	return result, err
+}
+
+// manageOperatorLogic backs up the configuration of the MountPoints once the schedule is due. A schedule missed,
+// e.g. while the operator wasn't running, is caught up once.
+func (r *ConfigBackupReconciler) manageOperatorLogic(obj *netconfv1.ConfigBackup, log logr.Logger) error {
+	schedule, err := parseCron(obj.Spec.Schedule)
+	if err != nil {
+		err = &conditionError{reason: netconfv1.InvalidScheduleReason, message: err.Error()}
+		setFailed(obj, err, netconfv1.BackedUpCondition)
+		return err
+	}
+	if (obj.Spec.Storage.ConfigMap == nil) == (obj.Spec.Storage.Git == nil) {
+		err = fmt.Errorf("exactly one of configMap and git storage must be set")
+		setFailed(obj, err, netconfv1.BackedUpCondition)
+		return err
+	}
+
+	err = r.checkGitJob(obj)
+	if err != nil {
+		return err
+	}
+
+	if obj.Spec.Suspend {
+		obj.NextScheduleTime = nil
+		return nil
+	}
+	last := obj.CreationTimestamp.Time
+	if obj.LastScheduleTime != nil {
+		last = obj.LastScheduleTime.Time
+	}
+	due := schedule.next(last.UTC())
+	if due.IsZero() {
+		err = &conditionError{
+			reason:  netconfv1.InvalidScheduleReason,
+			message: fmt.Sprintf("schedule %q never matches", obj.Spec.Schedule),
+		}
+		setFailed(obj, err, netconfv1.BackedUpCondition)
+		return err
+	}
+	if time.Now().Before(due) {
+		next := metav1.NewTime(due)
+		obj.NextScheduleTime = &next
+		return nil
+	}
+
+	now := metav1.Now()
+	r.backup(obj, now, log)
+	next := metav1.NewTime(schedule.next(now.UTC()))
+	obj.LastScheduleTime = &now
+	obj.NextScheduleTime = &next
+	return nil
+}
+
+// backup backs up the configuration of every MountPoint, and stores the versions that changed since their
+// last backup.
+func (r *ConfigBackupReconciler) backup(obj *netconfv1.ConfigBackup, now metav1.Time, log logr.Logger) {
+	setReplyStatus(&obj.RPCStatus, nil, nil)
+	obj.RpcReply = ""
+
+	var statuses []netconfv1.MountPointBackup
+	var failures []string
+	var failure error
+	changed := make(map[string]string)
+	for _, mountPoint := range obj.Spec.MountPoints {
+		status := netconfv1.MountPointBackup{MountPoint: mountPoint}
+		for _, previous := range obj.MountPoints {
+			if previous.MountPoint == mountPoint {
+				status = previous
+			}
+		}
+
+		err := r.backupMountPoint(obj, &status, now, changed)
+		if err != nil {
+			log.Info(fmt.Sprintf("%s: Failed to back up ConfigBackup %s: %s", mountPoint, obj.Name, err))
+			status.Error = err.Error()
+			failures = append(failures, fmt.Sprintf("%s: %s", mountPoint, err))
+			if failure == nil {
+				failure = err
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	if len(changed) != 0 {
+		job, err := r.commitGit(obj, changed, now.Time)
+		if err != nil {
+			log.Info(fmt.Sprintf("Failed to commit ConfigBackup %s into Git: %s", obj.Name, err))
+			for i := range statuses {
+				if _, ok := changed[statuses[i].MountPoint]; ok {
+					// Committed upon the next schedule
+					statuses[i].Hash = ""
+					statuses[i].Error = err.Error()
+				}
+			}
+			failures = append(failures, err.Error())
+			if failure == nil {
+				failure = err
+			}
+		}
+		obj.GitJob = job
+	}
+	obj.MountPoints = statuses
+
+	if failure != nil {
+		setFailed(
+			obj, &conditionError{
+				reason: conditionReason(failure),
+				message: fmt.Sprintf(
+					"%d of %d backup(s) failed: %s", len(failures), len(obj.Spec.MountPoints),
+					strings.Join(failures, "; "),
+				),
+			}, netconfv1.BackedUpCondition,
+		)
+		return
+	}
+	setSucceeded(obj, netconfv1.BackedUpCondition)
+}
+
+// checkGitJob reports the failure of the last Job committing the backups into the Git repository. The backups
+// it was committing are committed upon the next schedule.
+func (r *ConfigBackupReconciler) checkGitJob(obj *netconfv1.ConfigBackup) error {
+	if obj.GitJob == "" {
+		return nil
+	}
+
+	job := &batchv1.Job{}
+	err := r.GetClient().Get(
+		context.Background(), types.NamespacedName{Namespace: obj.Namespace, Name: obj.GitJob}, job,
+	)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// Cleaned up once finished
+			obj.GitJob = ""
+			return nil
+		}
+		return err
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Type != batchv1.JobFailed || condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		for i := range obj.MountPoints {
+			obj.MountPoints[i].Hash = ""
+		}
+		obj.GitJob = ""
+		setConditions(
+			obj, metav1.ConditionFalse, netconfv1.GitCommitFailedReason,
+			fmt.Sprintf("Job %s failed to commit the backups: %s", job.Name, condition.Message),
+			netconfv1.BackedUpCondition, netconfv1.ReadyCondition,
+		)
+		r.GetRecorder().Event(
+			obj, "Warning", netconfv1.GitCommitFailedReason, fmt.Sprintf("Job %s failed", job.Name),
+		)
+	}
+	return nil
+}
+
+func newConfigBackupReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ConfigBackupReconciler{
+		ReconcilerBase: util.NewReconcilerBase(
+			mgr.GetClient(), mgr.GetScheme(), mgr.GetConfig(), mgr.GetEventRecorderFor(configBackupControllerName),
+			mgr.GetAPIReader(),
+		),
+		sessions: sessions,
+	}
+}
+
+func addConfigBackup(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(configBackupControllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(
+		&source.Kind{Type: &netconfv1.ConfigBackup{}}, &handler.EnqueueRequestForObject{},
+		util.ResourceGenerationOrFinalizerChangedPredicate{},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch over the Jobs committing the backups into the Git repository, to report their failure
+	err = c.Watch(
+		&source.Kind{Type: &batchv1.Job{}},
+		&handler.EnqueueRequestForOwner{OwnerType: &netconfv1.ConfigBackup{}, IsController: true},
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
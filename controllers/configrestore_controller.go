@@ -0,0 +1,231 @@
+/*
+Copyright 2021. Alexis de Talhouët
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"time"
+
+	"github.com/redhat-cop/operator-utils/pkg/util"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=configrestores,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=configrestores/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=configrestores/finalizers,verbs=update
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list
+//+kubebuilder:rbac:groups="",resources=pods/log,verbs=get
+
+// ConfigRestoreReconciler reconciles a ConfigRestore object
+type ConfigRestoreReconciler struct {
+	util.ReconcilerBase
+	sessions SessionProvider
+}
+
+// AddConfigRestore creates a new ConfigRestore Controller and adds it to the Manager.
+func AddConfigRestore(mgr manager.Manager) error {
+	return addConfigRestore(mgr, newConfigRestoreReconciler(mgr))
+}
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *ConfigRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var log = logf.Log.WithName(configRestoreControllerName)
+
+	reqLogger := log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+	reqLogger.Info("Reconciling ConfigRestore")
+
+	// Fetch the CRD instance
+	instance := &netconfv1.ConfigRestore{}
+	err := r.GetClient().Get(context.Background(), req.NamespacedName, instance)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("ConfigRestore resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		log.Error(err, "Failed to get ConfigRestore")
+		return r.ManageError(ctx, instance, err)
+	}
+
+	// The operation is executed once per spec, unless executed again through its run-id
+	execute, result, err := manageExecution(
+		ctx, r.ReconcilerBase, instance, instance.Spec, instance.Spec.ExecutionPolicy, &instance.RPCStatus,
+		func() {
+			instance.ConfirmedCommitGeneration = 0
+			meta.RemoveStatusCondition(&instance.Conditions, netconfv1.AppliedCondition)
+		}, log,
+	)
+	if !execute {
+		return result, err
+	}
+
+	// Managing CR validation
+	if ok, err := r.isValid(instance); !ok {
+		setFailed(instance, err, netconfv1.AppliedCondition)
+		return r.ManageErrorWithRequeue(ctx, instance, err, 2*time.Second)
+	}
+
+	// Managing CR Finalization
+	if util.IsBeingDeleted(instance) {
+		return reconcile.Result{}, nil
+	}
+
+	// Wait for the retry scheduled following a transient failure, if any
+	if wait := startAttempt(instance.Spec.RetryPolicy, &instance.RPCStatus, instance.Generation); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
+	err = r.manageOperatorLogic(instance, log)
+	if err != nil {
+		return manageRetry(ctx, r.ReconcilerBase, instance, instance.Spec.RetryPolicy, &instance.RPCStatus, err)
+	}
+
+	result, err = manageCompletion(
+		ctx, r.ReconcilerBase, instance, instance.Spec.ExecutionPolicy, &instance.RPCStatus,
+	)
+	// Next step of the confirmed commit, if any
+	if requeue := confirmedCommitRequeue(restoreConfirmation(instance)); err == nil && requeue > 0 {
+		result.RequeueAfter = requeue
+	}
+	return result, err
+}
+
+func (r *ConfigRestoreReconciler) isValid(obj metav1.Object) (bool, error) {
+	instance, ok := obj.(*netconfv1.ConfigRestore)
+	if !ok {
+		return false, fmt.Errorf("%s is not a ConfigRestore object", obj.GetName())
+	}
+
+	exists := CheckMountPointExists(
+		r.ReconcilerBase, r.sessions,
+		types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.MountPoint},
+	)
+	if !exists {
+		return false, mountPointUnavailable(instance.Spec.MountPoint)
+	}
+
+	return true, nil
+}
+
+// manageOperatorLogic restores the configuration once per generation, then drives its confirmed commit, if any.
+func (r *ConfigRestoreReconciler) manageOperatorLogic(obj *netconfv1.ConfigRestore, log logr.Logger) error {
+	if isConditionTrue(obj, netconfv1.AppliedCondition) {
+		if obj.Spec.Confirmed != nil && obj.ConfirmedCommitGeneration == obj.Generation {
+			return checkConfirmation(r.ReconcilerBase, r.sessions, restoreConfirmation(obj), log)
+		}
+		return nil
+	}
+
+	ok, err := checkDependencies(r.ReconcilerBase, obj, obj.Spec.DependsOn, log, netconfv1.AppliedCondition)
+	if !ok {
+		return err
+	}
+
+	config, pending, err := r.restoreSource(obj)
+	if err != nil {
+		err = fmt.Errorf("invalid source: %w", err)
+		setFailed(obj, err, netconfv1.AppliedCondition)
+		return err
+	}
+	if pending {
+		log.Info(fmt.Sprintf("%s: Waiting for Job %s to read the Git revision.", obj.Spec.MountPoint, obj.SourceJob))
+		setConditions(
+			obj, metav1.ConditionFalse, netconfv1.SourcePendingReason,
+			fmt.Sprintf("Waiting for Job %s to read the Git revision", obj.SourceJob),
+			netconfv1.AppliedCondition, netconfv1.ReadyCondition,
+		)
+		return nil
+	}
+	if _, err := parseXML(config); err != nil {
+		err = fmt.Errorf("invalid configuration: %w", err)
+		setFailed(obj, err, netconfv1.AppliedCondition)
+		return err
+	}
+
+	log.Info(fmt.Sprintf("%s: Restore ConfigRestore %s.", obj.Spec.MountPoint, obj.Name))
+	s, err := r.sessions.Acquire(obj.GetMountPointNamespacedName(obj.Spec.MountPoint))
+	if err != nil {
+		err = mountPointUnavailable(obj.Spec.MountPoint)
+		setFailed(obj, err, netconfv1.AppliedCondition)
+		return err
+	}
+	defer r.sessions.Release(s)
+	return r.restore(obj, s, config, log)
+}
+
+func newConfigRestoreReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ConfigRestoreReconciler{
+		ReconcilerBase: util.NewReconcilerBase(
+			mgr.GetClient(), mgr.GetScheme(), mgr.GetConfig(), mgr.GetEventRecorderFor(configRestoreControllerName),
+			mgr.GetAPIReader(),
+		),
+		sessions: sessions,
+	}
+}
+
+func addConfigRestore(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(configRestoreControllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(
+		&source.Kind{Type: &netconfv1.ConfigRestore{}}, &handler.EnqueueRequestForObject{},
+		executionPredicate,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch over the Jobs reading the Git revisions, to restore them once read
+	err = c.Watch(
+		&source.Kind{Type: &batchv1.Job{}},
+		&handler.EnqueueRequestForOwner{OwnerType: &netconfv1.ConfigRestore{}, IsController: true},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch over the dependencies, to start the ConfigRestore once they are Ready
+	err = watchDependencies(
+		mgr, c, &netconfv1.ConfigRestore{}, &netconfv1.ConfigRestoreList{},
+		func(obj client.Object) netconfv1.Dependencies { return obj.(*netconfv1.ConfigRestore).Spec.DependsOn },
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
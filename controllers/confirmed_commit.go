@@ -0,0 +1,311 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"github.com/go-logr/logr"
+	"github.com/openshift-telco/go-netconf-client/netconf/message"
+	"github.com/redhat-cop/operator-utils/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+	"time"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// confirmedCommitCapability is required to perform a confirmed commit with a persist token.
+const confirmedCommitCapability = "urn:ietf:params:netconf:capability:confirmed-commit:1.1"
+
+// confirmation is the confirmed commit performed on behalf of a Commit, or of a ConfigRestore.
+type confirmation struct {
+	obj        confirmedObject
+	mountPoint string
+	timeout    int32
+	spec       *netconfv1.ConfirmedCommit
+	status     *netconfv1.CommitStatus
+}
+
+// confirmedObject is implemented by the kinds performing a confirmed commit.
+type confirmedObject interface {
+	client.Object
+	conditionsAware
+}
+
+func commitConfirmation(obj *netconfv1.Commit) *confirmation {
+	return &confirmation{
+		obj:        obj,
+		mountPoint: obj.Spec.MountPoint,
+		timeout:    obj.Spec.Timeout,
+		spec:       obj.Spec.Confirmed,
+		status:     &obj.CommitStatus,
+	}
+}
+
+// manageConfirmedCommit drives the confirmed commit of the current generation: it sends the confirmed commit,
+// runs the health check until it passes, or the attempts are exhausted, then either sends the confirming commit,
+// or rolls back according to the rollback policy. Each step is performed upon its own reconciliation.
+func (r *CommitReconciler) manageConfirmedCommit(obj *netconfv1.Commit, log logr.Logger) error {
+	c := commitConfirmation(obj)
+	if obj.ConfirmedCommitGeneration == obj.Generation {
+		return checkConfirmation(r.ReconcilerBase, r.sessions, c, log)
+	}
+
+	s, err := r.sessions.Acquire(obj.GetMountPointNamespacedName(obj.Spec.MountPoint))
+	if err != nil {
+		err = mountPointUnavailable(obj.Spec.MountPoint)
+		setFailed(obj, err, netconfv1.CommittedCondition)
+		return err
+	}
+	defer r.sessions.Release(s)
+	return confirmedCommit(r.sessions, s, c, log)
+}
+
+// checkConfirmation performs the next step of the confirmed commit, once sent: the health check, and the
+// confirming commit, or the rollback.
+func checkConfirmation(r util.ReconcilerBase, sessions SessionProvider, c *confirmation, log logr.Logger) error {
+	switch c.status.ConfirmedCommitPhase {
+	case netconfv1.ConfirmingPhase:
+		if time.Now().After(c.status.ConfirmDeadline.Time) {
+			rolledBack(c, "The confirm timeout expired before the health check passed")
+			return nil
+		}
+		return checkConfirmedCommit(r, sessions, c, log)
+	case netconfv1.AwaitingRollbackPhase:
+		if time.Now().After(c.status.ConfirmDeadline.Time) {
+			rolledBack(c, "The confirm timeout expired following the health check failure")
+		}
+	}
+	return nil
+}
+
+// confirmedCommit sends the confirmed commit, along with its confirm timeout and persist token.
+func confirmedCommit(sessions SessionProvider, s Session, c *confirmation, log logr.Logger) error {
+	obj := c.obj
+	namespacedName := types.NamespacedName{Namespace: obj.GetNamespace(), Name: c.mountPoint}.String()
+	if !hasCapability(sessions.Capabilities(namespacedName), confirmedCommitCapability) {
+		err := fmt.Errorf("MountPoint %s doesn't support the %s capability", c.mountPoint, confirmedCommitCapability)
+		setFailed(obj, err, netconfv1.CommittedCondition)
+		return err
+	}
+
+	persist := c.spec.Persist
+	if persist == "" {
+		persist = fmt.Sprintf("%s-%d", obj.GetUID(), obj.GetGeneration())
+	}
+	reply, err := s.SyncRPC(
+		message.NewRPC(
+			fmt.Sprintf(
+				"<commit><confirmed/><confirm-timeout>%d</confirm-timeout><persist>%s</persist></commit>",
+				c.spec.ConfirmTimeout, escapeXML(persist),
+			),
+		), c.timeout,
+	)
+	err = checkReply(reply, err)
+	setReplyStatus(&c.status.RPCStatus, reply, err)
+	if err != nil {
+		log.Info(fmt.Sprintf("%s: Failed to send confirmed commit for %s", c.mountPoint, obj.GetName()))
+		setFailed(obj, err, netconfv1.CommittedCondition)
+		return err
+	}
+
+	log.Info(fmt.Sprintf("%s: Confirmed commit for %s sent, awaiting health check.", c.mountPoint, obj.GetName()))
+	now := metav1.Now()
+	deadline := metav1.NewTime(now.Add(time.Duration(c.spec.ConfirmTimeout) * time.Second))
+	c.status.ConfirmedCommitPhase = netconfv1.ConfirmingPhase
+	c.status.ConfirmedCommitGeneration = obj.GetGeneration()
+	c.status.PersistID = persist
+	c.status.ConfirmedCommitTime = &now
+	c.status.ConfirmDeadline = &deadline
+	c.status.HealthCheckAttempts = 0
+	c.status.HealthCheckMessage = ""
+	setConditions(
+		obj, metav1.ConditionFalse, netconfv1.ConfirmationPendingReason,
+		fmt.Sprintf("Rolled back at %s, unless confirmed", deadline.UTC().Format(time.RFC3339)),
+		netconfv1.CommittedCondition, netconfv1.ReadyCondition,
+	)
+	return nil
+}
+
+// checkConfirmedCommit runs the health check, and sends the confirming commit once it passes. Once the attempts
+// are exhausted, the commit is either cancelled, or left to the NETCONF server to roll back.
+func checkConfirmedCommit(r util.ReconcilerBase, sessions SessionProvider, c *confirmation, log logr.Logger) error {
+	obj := c.obj
+	s, err := sessions.Acquire(types.NamespacedName{Namespace: obj.GetNamespace(), Name: c.mountPoint}.String())
+	if err != nil {
+		err = mountPointUnavailable(c.mountPoint)
+	} else {
+		defer sessions.Release(s)
+		err = healthCheck(c, s)
+	}
+
+	c.status.HealthCheckAttempts++
+	if err != nil {
+		log.Info(
+			fmt.Sprintf(
+				"%s: Health check %d of %s failed: %s", c.mountPoint, c.status.HealthCheckAttempts, obj.GetName(),
+				err,
+			),
+		)
+		c.status.HealthCheckMessage = err.Error()
+		if c.status.HealthCheckAttempts < healthCheckAttempts(c.spec) {
+			return nil
+		}
+		r.GetRecorder().Event(obj, "Warning", netconfv1.HealthCheckFailedReason, err.Error())
+		return rollback(c, s, log)
+	}
+
+	c.status.HealthCheckMessage = "Health check passed"
+	reply, err := s.SyncRPC(
+		message.NewRPC(fmt.Sprintf("<commit><persist-id>%s</persist-id></commit>", escapeXML(c.status.PersistID))),
+		c.timeout,
+	)
+	err = checkReply(reply, err)
+	setReplyStatus(&c.status.RPCStatus, reply, err)
+	if err != nil {
+		log.Info(fmt.Sprintf("%s: Failed to confirm the commit for %s", c.mountPoint, obj.GetName()))
+		// Retried upon the next reconciliation, until the confirm timeout expires
+		return err
+	}
+
+	log.Info(fmt.Sprintf("%s: Successfully confirmed the commit for %s.", c.mountPoint, obj.GetName()))
+	c.status.ConfirmedCommitPhase = netconfv1.ConfirmedPhase
+	completeExecution(&c.status.RPCStatus)
+	setSucceeded(obj, netconfv1.CommittedCondition)
+	return nil
+}
+
+// rollback applies the rollback policy once the health check failed: either a cancel-commit is sent, or the
+// NETCONF server is left to roll back once the confirm timeout expires. The latter is also the fallback when
+// the cancel-commit can't be sent.
+func rollback(c *confirmation, s Session, log logr.Logger) error {
+	obj := c.obj
+	if c.spec.OnFailure != netconfv1.TimeoutRollbackPolicy && s != nil {
+		reply, err := s.SyncRPC(
+			message.NewRPC(
+				fmt.Sprintf(
+					"<cancel-commit><persist-id>%s</persist-id></cancel-commit>", escapeXML(c.status.PersistID),
+				),
+			), c.timeout,
+		)
+		err = checkReply(reply, err)
+		setReplyStatus(&c.status.RPCStatus, reply, err)
+		if err == nil {
+			log.Info(fmt.Sprintf("%s: Cancelled the commit for %s.", c.mountPoint, obj.GetName()))
+			c.status.ConfirmedCommitPhase = netconfv1.CancelledPhase
+			completeExecution(&c.status.RPCStatus)
+			setConditions(
+				obj, metav1.ConditionFalse, netconfv1.CommitCancelledReason,
+				"Health check failed, the commit was cancelled: "+c.status.HealthCheckMessage,
+				netconfv1.CommittedCondition, netconfv1.ReadyCondition,
+			)
+			return nil
+		}
+		log.Info(fmt.Sprintf("%s: Failed to cancel the commit for %s: %s", c.mountPoint, obj.GetName(), err))
+	}
+
+	log.Info(fmt.Sprintf("%s: Commit for %s left to roll back.", c.mountPoint, obj.GetName()))
+	c.status.ConfirmedCommitPhase = netconfv1.AwaitingRollbackPhase
+	setConditions(
+		obj, metav1.ConditionFalse, netconfv1.HealthCheckFailedReason,
+		"Health check failed, the commit is rolled back once the confirm timeout expires: "+c.status.HealthCheckMessage,
+		netconfv1.CommittedCondition, netconfv1.ReadyCondition,
+	)
+	return nil
+}
+
+// rolledBack records the rollback performed by the NETCONF server, once the confirm timeout expired. As a
+// cancelled commit, it completes the execution, though not Ready.
+func rolledBack(c *confirmation, reason string) {
+	c.status.ConfirmedCommitPhase = netconfv1.RolledBackPhase
+	completeExecution(&c.status.RPCStatus)
+	setConditions(
+		c.obj, metav1.ConditionFalse, netconfv1.RolledBackReason, reason, netconfv1.CommittedCondition,
+		netconfv1.ReadyCondition,
+	)
+}
+
+// healthCheck checks the NETCONF session is reachable, using a get selecting no data by default, and
+// when a filter is defined, checks the reply holds the expected data.
+func healthCheck(c *confirmation, s Session) error {
+	check := c.spec.HealthCheck
+	filter := ""
+	if check != nil {
+		filter = check.Filter
+	}
+	if _, err := parseXML(filter); err != nil {
+		return fmt.Errorf("invalid health check filter: %w", err)
+	}
+
+	reply, err := s.SyncRPC(
+		message.NewRPC(fmt.Sprintf(`<get><filter type="subtree">%s</filter></get>`, filter)), c.timeout,
+	)
+	err = checkReply(reply, err)
+	if err != nil || check == nil || check.Expect == "" {
+		return err
+	}
+
+	expected, err := parseXML(check.Expect)
+	if err != nil {
+		return fmt.Errorf("invalid health check expectation: %w", err)
+	}
+	actual, err := dataNodes(reply.RawReply)
+	if err != nil {
+		return fmt.Errorf("invalid get reply: %w", err)
+	}
+	if diff := diffXML("", expected, actual, nil); len(diff) != 0 {
+		return fmt.Errorf("unexpected state: %s", strings.Join(diff, ", "))
+	}
+	return nil
+}
+
+// confirmedCommitRequeue returns the delay before the next step of the confirmed commit, if any.
+func confirmedCommitRequeue(c *confirmation) time.Duration {
+	if c.spec == nil || c.status.ConfirmedCommitGeneration != c.obj.GetGeneration() {
+		return 0
+	}
+
+	switch c.status.ConfirmedCommitPhase {
+	case netconfv1.ConfirmingPhase:
+		check := c.spec.HealthCheck
+		delay := 10 * time.Second
+		if check != nil && c.status.HealthCheckAttempts == 0 && check.InitialDelay > 0 {
+			delay = time.Duration(check.InitialDelay) * time.Second
+		} else if check != nil && c.status.HealthCheckAttempts != 0 && check.Interval > 0 {
+			delay = time.Duration(check.Interval) * time.Second
+		}
+		if remaining := time.Until(c.status.ConfirmDeadline.Time); remaining < delay {
+			// Check the deadline once it expires
+			delay = remaining + time.Second
+		}
+		return delay
+	case netconfv1.AwaitingRollbackPhase:
+		return time.Until(c.status.ConfirmDeadline.Time) + time.Second
+	}
+	return 0
+}
+
+func healthCheckAttempts(spec *netconfv1.ConfirmedCommit) int32 {
+	if spec.HealthCheck == nil || spec.HealthCheck.Attempts <= 0 {
+		return 3
+	}
+	return spec.HealthCheck.Attempts
+}
+
+func hasCapability(capabilities []string, capability string) bool {
+	for _, c := range capabilities {
+		// Capabilities may carry parameters, e.g. `?module=...`
+		if strings.TrimSpace(strings.SplitN(c, "?", 2)[0]) == capability {
+			return true
+		}
+	}
+	return false
+}
+
+func escapeXML(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+func TestConfirmedCommitPhases(t *testing.T) {
+	healthy := interfaces("<name>eth0</name><mtu>9000</mtu>")
+	unhealthy := interfaces("<name>eth0</name><mtu>1500</mtu>")
+	tests := []struct {
+		name string
+		// The phase of the confirmed commit, not yet sent when empty
+		phase netconfv1.ConfirmedCommitPhase
+		// The time left before the confirm timeout expires
+		remaining    time.Duration
+		attempts     int32
+		onFailure    netconfv1.RollbackPolicy
+		capabilities []string
+		state        string
+		errorTags    map[string][]string
+		wantPhase    netconfv1.ConfirmedCommitPhase
+		want         []string
+		wantErr      bool
+		// Whether the execution completed, i.e. its TTL started
+		wantCompleted bool
+	}{
+		{
+			name:         "confirmed commit sent",
+			capabilities: []string{confirmedCommitCapability},
+			wantPhase:    netconfv1.ConfirmingPhase,
+			want:         []string{"commit"},
+		},
+		{name: "capability missing", wantErr: true},
+		{
+			name:         "confirmed commit failed",
+			capabilities: []string{confirmedCommitCapability},
+			errorTags:    map[string][]string{"commit": {"operation-failed"}},
+			want:         []string{"commit"},
+			wantErr:      true,
+		},
+		{
+			name:          "health check passed",
+			phase:         netconfv1.ConfirmingPhase,
+			remaining:     time.Minute,
+			state:         healthy,
+			wantPhase:     netconfv1.ConfirmedPhase,
+			want:          []string{"get", "commit"},
+			wantCompleted: true,
+		},
+		{
+			name:      "confirming commit failed",
+			phase:     netconfv1.ConfirmingPhase,
+			remaining: time.Minute,
+			state:     healthy,
+			errorTags: map[string][]string{"commit": {"operation-failed"}},
+			wantPhase: netconfv1.ConfirmingPhase,
+			want:      []string{"get", "commit"},
+			wantErr:   true,
+		},
+		{
+			name:      "health check failed, attempts left",
+			phase:     netconfv1.ConfirmingPhase,
+			remaining: time.Minute,
+			state:     unhealthy,
+			wantPhase: netconfv1.ConfirmingPhase,
+			want:      []string{"get"},
+		},
+		{
+			name:          "health check failed, cancelled",
+			phase:         netconfv1.ConfirmingPhase,
+			remaining:     time.Minute,
+			attempts:      2,
+			state:         unhealthy,
+			wantPhase:     netconfv1.CancelledPhase,
+			want:          []string{"get", "cancel-commit"},
+			wantCompleted: true,
+		},
+		{
+			name:      "health check failed, cancel-commit failed",
+			phase:     netconfv1.ConfirmingPhase,
+			remaining: time.Minute,
+			attempts:  2,
+			state:     unhealthy,
+			errorTags: map[string][]string{"cancel-commit": {"operation-failed"}},
+			wantPhase: netconfv1.AwaitingRollbackPhase,
+			want:      []string{"get", "cancel-commit"},
+		},
+		{
+			name:      "health check failed, left to roll back",
+			phase:     netconfv1.ConfirmingPhase,
+			remaining: time.Minute,
+			attempts:  2,
+			onFailure: netconfv1.TimeoutRollbackPolicy,
+			state:     unhealthy,
+			wantPhase: netconfv1.AwaitingRollbackPhase,
+			want:      []string{"get"},
+		},
+		{
+			name:          "confirm timeout expired while confirming",
+			phase:         netconfv1.ConfirmingPhase,
+			remaining:     -time.Second,
+			wantPhase:     netconfv1.RolledBackPhase,
+			wantCompleted: true,
+		},
+		{
+			name:      "awaiting rollback",
+			phase:     netconfv1.AwaitingRollbackPhase,
+			remaining: time.Minute,
+			wantPhase: netconfv1.AwaitingRollbackPhase,
+		},
+		{
+			name:          "rolled back",
+			phase:         netconfv1.AwaitingRollbackPhase,
+			remaining:     -time.Second,
+			wantPhase:     netconfv1.RolledBackPhase,
+			wantCompleted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSession(tt.errorTags)
+			s.capabilities = tt.capabilities
+			s.data = map[string]string{"get": tt.state}
+			deadline := metav1.NewTime(time.Now().Add(tt.remaining))
+			obj := &netconfv1.Commit{
+				ObjectMeta: metav1.ObjectMeta{Name: "commit", Generation: 1},
+				Spec: netconfv1.CommitSpec{
+					MountPoint: "device",
+					Confirmed: &netconfv1.ConfirmedCommit{
+						ConfirmTimeout: 300,
+						HealthCheck:    &netconfv1.HealthCheck{Attempts: 3, Filter: interfaces(), Expect: healthy},
+						OnFailure:      tt.onFailure,
+					},
+				},
+				CommitStatus: netconfv1.CommitStatus{
+					RPCStatus:            netconfv1.RPCStatus{SpecHash: "sha256:0"},
+					ConfirmedCommitPhase: tt.phase,
+					PersistID:            "token",
+					ConfirmDeadline:      &deadline,
+					HealthCheckAttempts:  tt.attempts,
+				},
+			}
+			c := commitConfirmation(obj)
+
+			var err error
+			if tt.phase == "" {
+				err = confirmedCommit(s, s, c, logr.Discard())
+			} else {
+				err = checkConfirmation(newTestReconcilerBase(&testClient{}), s, c, logr.Discard())
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if obj.ConfirmedCommitPhase != tt.wantPhase {
+				t.Errorf("phase = %q, want %q", obj.ConfirmedCommitPhase, tt.wantPhase)
+			}
+			if !reflect.DeepEqual(s.operations, tt.want) {
+				t.Errorf("sent %q, want %q", s.operations, tt.want)
+			}
+			if completed := obj.CompletedAt != nil; completed != tt.wantCompleted {
+				t.Errorf("completed = %v, want %v", completed, tt.wantCompleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2021. Alexis de Talhouët
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	"github.com/openshift-telco/go-netconf-client/netconf/message"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"time"
+
+	"github.com/redhat-cop/operator-utils/pkg/util"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=copyconfigs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=copyconfigs/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=copyconfigs/finalizers,verbs=update
+
+// CopyConfigReconciler reconciles a CopyConfig object
+type CopyConfigReconciler struct {
+	util.ReconcilerBase
+	sessions SessionProvider
+}
+
+// AddCopyConfig Add creates a new MountPoint Controller and adds it to the Manager.
+func AddCopyConfig(mgr manager.Manager) error {
+	return addCopyConfig(mgr, newCopyConfigReconciler(mgr))
+}
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *CopyConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var log = logf.Log.WithName(copyConfigControllerName)
+
+	reqLogger := log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+	reqLogger.Info("Reconciling CopyConfig")
+
+	// Fetch the CRD instance
+	instance := &netconfv1.CopyConfig{}
+	err := r.GetClient().Get(context.Background(), req.NamespacedName, instance)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("CopyConfig resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		log.Error(err, "Failed to get CopyConfig")
+		return r.ManageError(ctx, instance, err)
+	}
+
+	// The operation is executed once per spec, unless executed again through its run-id
+	execute, result, err := manageExecution(
+		ctx, r.ReconcilerBase, instance, instance.Spec, instance.Spec.ExecutionPolicy, &instance.RPCStatus, nil, log,
+	)
+	if !execute {
+		return result, err
+	}
+
+	// Managing CR validation
+	if ok, err := r.isValid(instance); !ok {
+		setFailed(instance, err, netconfv1.AppliedCondition)
+		return r.ManageErrorWithRequeue(ctx, instance, err, 2*time.Second)
+	}
+
+	// Managing CR Initialization
+	if ok := r.isInitialized(instance); !ok {
+		err := r.GetClient().Update(context.Background(), instance)
+		if err != nil {
+			log.Error(err, "unable to update instance", "instance", instance)
+			return r.ManageError(ctx, instance, err)
+		}
+		return reconcile.Result{}, nil
+	}
+
+	// Managing CR Finalization
+	if util.IsBeingDeleted(instance) {
+		return reconcile.Result{}, nil
+	}
+
+	// Wait for the retry scheduled following a transient failure, if any
+	if wait := startAttempt(instance.Spec.RetryPolicy, &instance.RPCStatus, instance.Generation); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
+	err = r.manageOperatorLogic(instance, log)
+	if err != nil {
+		return manageRetry(ctx, r.ReconcilerBase, instance, instance.Spec.RetryPolicy, &instance.RPCStatus, err)
+	}
+
+	return manageCompletion(ctx, r.ReconcilerBase, instance, instance.Spec.ExecutionPolicy, &instance.RPCStatus)
+}
+
+func (r *CopyConfigReconciler) isInitialized(obj metav1.Object) bool {
+	_, ok := obj.(*netconfv1.CopyConfig)
+	if !ok {
+		return false
+	}
+	return true
+
+}
+
+func (r *CopyConfigReconciler) isValid(obj metav1.Object) (bool, error) {
+	instance, ok := obj.(*netconfv1.CopyConfig)
+	if !ok {
+		return false, fmt.Errorf("%s is not a CopyConfig object", obj.GetName())
+	}
+
+	exists := CheckMountPointExists(
+		r.ReconcilerBase, r.sessions,
+		types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.MountPoint},
+	)
+	if !exists {
+		return false, mountPointUnavailable(instance.Spec.MountPoint)
+	}
+
+	return true, nil
+}
+
+func (r *CopyConfigReconciler) manageOperatorLogic(copyConfig *netconfv1.CopyConfig, log logr.Logger) error {
+	log.Info(fmt.Sprintf("%s: Send CopyConfig %s.", copyConfig.Spec.MountPoint, copyConfig.Name))
+
+	ok, err := checkDependencies(
+		r.ReconcilerBase, copyConfig, copyConfig.Spec.DependsOn, log, netconfv1.AppliedCondition,
+	)
+	if !ok {
+		return err
+	}
+
+	capabilities := r.sessions.Capabilities(copyConfig.GetMountPointNamespacedName(copyConfig.Spec.MountPoint))
+	target, err := configTarget(copyConfig.Spec.Target, capabilities)
+	if err != nil {
+		err = fmt.Errorf("invalid target: %w", err)
+		setFailed(copyConfig, err, netconfv1.AppliedCondition)
+		return err
+	}
+	source, err := configSource(r.ReconcilerBase, copyConfig.Namespace, copyConfig.Spec.Source, capabilities)
+	if err != nil {
+		err = fmt.Errorf("invalid source: %w", err)
+		setFailed(copyConfig, err, netconfv1.AppliedCondition)
+		return err
+	}
+
+	s, err := r.sessions.Acquire(copyConfig.GetMountPointNamespacedName(copyConfig.Spec.MountPoint))
+	if err != nil {
+		err = mountPointUnavailable(copyConfig.Spec.MountPoint)
+		setFailed(copyConfig, err, netconfv1.AppliedCondition)
+		return err
+	}
+	defer r.sessions.Release(s)
+	reply, err := s.SyncRPC(
+		message.NewRPC(
+			fmt.Sprintf("<copy-config><target>%s</target><source>%s</source></copy-config>", target, source),
+		), copyConfig.Spec.Timeout,
+	)
+
+	err = checkReply(reply, err)
+	if err != nil {
+		log.Info(fmt.Sprintf("%s: Failed to CopyConfig %s.", copyConfig.Spec.MountPoint, copyConfig.Name))
+		setReplyStatus(&copyConfig.RPCStatus, reply, err)
+		setFailed(copyConfig, err, netconfv1.AppliedCondition)
+		return err
+	}
+
+	log.Info(
+		fmt.Sprintf("%s: Successfully executed CopyConfig %s operation.", copyConfig.Spec.MountPoint, copyConfig.Name),
+	)
+	setReplyStatus(&copyConfig.RPCStatus, reply, nil)
+	setSucceeded(copyConfig, netconfv1.AppliedCondition)
+
+	return nil
+}
+
+func newCopyConfigReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &CopyConfigReconciler{
+		ReconcilerBase: util.NewReconcilerBase(
+			mgr.GetClient(), mgr.GetScheme(), mgr.GetConfig(), mgr.GetEventRecorderFor(copyConfigControllerName),
+			mgr.GetAPIReader(),
+		),
+		sessions: sessions,
+	}
+}
+
+func addCopyConfig(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(copyConfigControllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(
+		&source.Kind{Type: &netconfv1.CopyConfig{}}, &handler.EnqueueRequestForObject{},
+		executionPredicate,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch over the dependencies, to start the CopyConfig once they are Ready
+	err = watchDependencies(
+		mgr, c, &netconfv1.CopyConfig{}, &netconfv1.CopyConfigList{},
+		func(obj client.Object) netconfv1.Dependencies { return obj.(*netconfv1.CopyConfig).Spec.DependsOn },
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
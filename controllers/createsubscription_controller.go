@@ -47,6 +47,7 @@ import (
 // CreateSubscriptionReconciler reconciles a CreateSubscription object
 type CreateSubscriptionReconciler struct {
 	util.ReconcilerBase
+	sessions SessionProvider
 	recorder record.EventRecorder
 }
 
@@ -69,6 +70,7 @@ func (r *CreateSubscriptionReconciler) Reconcile(ctx context.Context, req ctrl.R
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			log.Info("CreateSubscription resource not found. Ignoring since object must be deleted")
+			removeSubscribed(createSubscriptionControllerName + "/" + req.NamespacedName.String())
 			return ctrl.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -78,6 +80,7 @@ func (r *CreateSubscriptionReconciler) Reconcile(ctx context.Context, req ctrl.R
 
 	// Managing CR validation
 	if ok, err := r.isValid(instance); !ok {
+		setFailed(instance, err, netconfv1.SubscribedCondition)
 		return r.ManageErrorWithRequeue(ctx, instance, err, 2*time.Second)
 	}
 
@@ -96,9 +99,14 @@ func (r *CreateSubscriptionReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return reconcile.Result{}, nil
 	}
 
+	// Wait for the retry scheduled following a transient failure, if any
+	if wait := startAttempt(instance.Spec.RetryPolicy, &instance.RPCStatus, instance.Generation); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
 	err = r.manageOperatorLogic(instance, log)
 	if err != nil {
-		return r.ManageError(ctx, instance, err)
+		return manageRetry(ctx, r.ReconcilerBase, instance, instance.Spec.RetryPolicy, &instance.RPCStatus, err)
 	}
 
 	return r.ManageSuccess(ctx, instance)
@@ -120,11 +128,11 @@ func (r *CreateSubscriptionReconciler) isValid(obj metav1.Object) (bool, error)
 	}
 
 	exists := CheckMountPointExists(
-		r.ReconcilerBase,
+		r.ReconcilerBase, r.sessions,
 		types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.MountPoint},
 	)
 	if !exists {
-		return false, fmt.Errorf("MountPoint %s doesn't exists", instance.Spec.MountPoint)
+		return false, mountPointUnavailable(instance.Spec.MountPoint)
 	}
 
 	return true, nil
@@ -148,16 +156,30 @@ func (r *CreateSubscriptionReconciler) manageOperatorLogic(obj *netconfv1.Create
 		}
 	}
 
-	s := Sessions[obj.GetMountPointNamespacedName(obj.Spec.MountPoint)]
-	err := s.CreateNotificationStream(
+	s, err := r.sessions.Acquire(obj.GetMountPointNamespacedName(obj.Spec.MountPoint))
+	if err != nil {
+		err = mountPointUnavailable(obj.Spec.MountPoint)
+		setFailed(obj, err, netconfv1.SubscribedCondition)
+		return err
+	}
+	defer r.sessions.Release(s)
+	key := createSubscriptionControllerName + "/" + obj.GetNamespacedName()
+	if isSubscribed(key, s, obj.Generation) {
+		log.Info(fmt.Sprintf("%s: NETCONF subscription %s already created.", obj.Spec.MountPoint, obj.Name))
+		setSucceeded(obj, netconfv1.SubscribedCondition)
+		return nil
+	}
+
+	err = s.CreateNotificationStream(
 		obj.Spec.Timeout, obj.Spec.StopTime, obj.Spec.StartTime, obj.Spec.Stream, callback,
 	)
 	if err != nil {
-		obj.Status = "failed"
+		setFailed(obj, err, netconfv1.SubscribedCondition)
 		return err
 	}
 
-	obj.Status = "subscribed"
+	setSubscribed(key, s, obj.Generation)
+	setSucceeded(obj, netconfv1.SubscribedCondition)
 	return nil
 }
 
@@ -168,6 +190,7 @@ func newCreateSubscriptionReconciler(mgr manager.Manager) reconcile.Reconciler {
 			mgr.GetEventRecorderFor(createSubscriptionControllerName),
 			mgr.GetAPIReader(),
 		),
+		sessions: sessions,
 		recorder: mgr.GetEventRecorderFor(createSubscriptionControllerName),
 	}
 }
@@ -187,5 +210,11 @@ func addCreateSubscription(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Re-create the subscription whenever the MountPoint session is re-established
+	err = c.Watch(&source.Channel{Source: createSubscriptionEvents}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
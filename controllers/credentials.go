@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+	"github.com/redhat-cop/operator-utils/pkg/util"
+)
+
+// Keys expected within the Secrets referenced by MountPointSpec.CredentialsSecretRef
+// and MountPointSpec.PrivateKeySecretRef
+const (
+	credentialsUsernameKey    = "username"
+	credentialsPasswordKey    = "password"
+	credentialsPrivateKeyKey  = "privateKey"
+	credentialsPassphraseKey  = "passphrase"
+	credentialsCertificateKey = "certificate"
+)
+
+// defaultAuthMethods is the order in which the authentication methods are attempted,
+// when not specified in the MountPoint.
+var defaultAuthMethods = []netconfv1.AuthMethod{
+	netconfv1.PublicKeyAuthMethod, netconfv1.KeyboardInteractiveAuthMethod, netconfv1.PasswordAuthMethod,
+}
+
+// credentials hold the material used to authenticate against a NETCONF server.
+type credentials struct {
+	username    string
+	password    string
+	privateKey  []byte
+	passphrase  []byte
+	certificate []byte
+}
+
+// credentialsError is returned when the credentials can't be loaded. The reason is
+// reported on the MountPoint CredentialsAvailable condition.
+type credentialsError struct {
+	reason  string
+	message string
+}
+
+func (e *credentialsError) Error() string {
+	return e.message
+}
+
+func malformedCredentials(format string, a ...interface{}) error {
+	return &credentialsError{reason: netconfv1.SecretMalformedReason, message: fmt.Sprintf(format, a...)}
+}
+
+// getCredentials returns the credentials of the MountPoint, either read from the
+// referenced Secrets, or from the deprecated username and password fields.
+func getCredentials(r util.ReconcilerBase, obj *netconfv1.MountPoint) (*credentials, error) {
+	creds := &credentials{username: obj.Spec.Username, password: obj.Spec.Password}
+
+	if obj.Spec.CredentialsSecretRef != nil {
+		secret, err := getSecret(r, obj.Namespace, obj.Spec.CredentialsSecretRef.Name)
+		if err != nil {
+			return nil, err
+		}
+		creds = &credentials{
+			username:    string(secret.Data[credentialsUsernameKey]),
+			password:    string(secret.Data[credentialsPasswordKey]),
+			privateKey:  secret.Data[credentialsPrivateKeyKey],
+			passphrase:  secret.Data[credentialsPassphraseKey],
+			certificate: secret.Data[credentialsCertificateKey],
+		}
+		if creds.username == "" {
+			return nil, malformedCredentials(
+				"Secret %s has no %s key", obj.Spec.CredentialsSecretRef.Name, credentialsUsernameKey,
+			)
+		}
+	}
+
+	if obj.Spec.PrivateKeySecretRef != nil {
+		secret, err := getSecret(r, obj.Namespace, obj.Spec.PrivateKeySecretRef.Name)
+		if err != nil {
+			return nil, err
+		}
+		creds.privateKey = secret.Data[corev1.SSHAuthPrivateKey]
+		if len(creds.privateKey) == 0 {
+			creds.privateKey = secret.Data[credentialsPrivateKeyKey]
+		}
+		if len(creds.privateKey) == 0 {
+			return nil, malformedCredentials(
+				"Secret %s must have a %s or a %s key", obj.Spec.PrivateKeySecretRef.Name, corev1.SSHAuthPrivateKey,
+				credentialsPrivateKeyKey,
+			)
+		}
+		creds.passphrase = secret.Data[credentialsPassphraseKey]
+		creds.certificate = secret.Data[credentialsCertificateKey]
+	}
+
+	if creds.password == "" && len(creds.privateKey) == 0 {
+		return nil, malformedCredentials(
+			"MountPoint %s has neither a %s nor a %s", obj.Name, credentialsPasswordKey, credentialsPrivateKeyKey,
+		)
+	}
+	return creds, nil
+}
+
+func getSecret(r util.ReconcilerBase, namespace string, name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := r.GetClient().Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, secret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, &credentialsError{
+				reason:  netconfv1.SecretNotFoundReason,
+				message: fmt.Sprintf("Secret %s doesn't exists in namespace %s", name, namespace),
+			}
+		}
+		return nil, err
+	}
+	return secret, nil
+}
+
+// sshAuthMethods builds the SSH authentication methods out of the provided credentials, in the order
+// they should be attempted. When no order is provided, every method for which credentials are available
+// is returned, following the default order.
+func sshAuthMethods(creds *credentials, order []netconfv1.AuthMethod) ([]ssh.AuthMethod, error) {
+	explicit := len(order) != 0
+	if !explicit {
+		order = defaultAuthMethods
+	}
+
+	var methods []ssh.AuthMethod
+	for _, method := range order {
+		switch method {
+		case netconfv1.PublicKeyAuthMethod:
+			if len(creds.privateKey) == 0 {
+				if explicit {
+					return nil, malformedCredentials("%s authentication requires a private key", method)
+				}
+				continue
+			}
+			signers, err := sshSigners(creds)
+			if err != nil {
+				return nil, err
+			}
+			methods = append(methods, ssh.PublicKeys(signers...))
+		case netconfv1.KeyboardInteractiveAuthMethod, netconfv1.PasswordAuthMethod:
+			if creds.password == "" {
+				if explicit {
+					return nil, malformedCredentials("%s authentication requires a password", method)
+				}
+				continue
+			}
+			if method == netconfv1.PasswordAuthMethod {
+				methods = append(methods, ssh.Password(creds.password))
+			} else {
+				methods = append(methods, ssh.KeyboardInteractive(passwordChallenge(creds.password)))
+			}
+		default:
+			return nil, fmt.Errorf("unsupported authentication method %s", method)
+		}
+	}
+	return methods, nil
+}
+
+// sshSigners parses the private key, decrypting it if needed. When an OpenSSH user certificate is
+// provided, the certificate is offered first, followed by the plain key.
+func sshSigners(creds *credentials) ([]ssh.Signer, error) {
+	var signer ssh.Signer
+	var err error
+	if len(creds.passphrase) != 0 {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(creds.privateKey, creds.passphrase)
+	} else {
+		signer, err = ssh.ParsePrivateKey(creds.privateKey)
+	}
+	if err != nil {
+		var missing *ssh.PassphraseMissingError
+		if errors.As(err, &missing) {
+			return nil, malformedCredentials("%s is encrypted, but no %s was provided", credentialsPrivateKeyKey,
+				credentialsPassphraseKey)
+		}
+		return nil, malformedCredentials("unable to parse %s: %s", credentialsPrivateKeyKey, err)
+	}
+
+	if len(creds.certificate) == 0 {
+		return []ssh.Signer{signer}, nil
+	}
+
+	pub, _, _, _, err := ssh.ParseAuthorizedKey(creds.certificate)
+	if err != nil {
+		return nil, malformedCredentials("unable to parse %s: %s", credentialsCertificateKey, err)
+	}
+	cert, ok := pub.(*ssh.Certificate)
+	if !ok {
+		return nil, malformedCredentials("%s is not an OpenSSH certificate", credentialsCertificateKey)
+	}
+	certSigner, err := ssh.NewCertSigner(cert, signer)
+	if err != nil {
+		return nil, malformedCredentials("invalid %s: %s", credentialsCertificateKey, err)
+	}
+	return []ssh.Signer{certSigner, signer}, nil
+}
+
+// passwordChallenge answers every keyboard-interactive question with the password.
+func passwordChallenge(password string) ssh.KeyboardInteractiveChallenge {
+	return func(user, instruction string, questions []string, echos []bool) ([]string, error) {
+		answers := make([]string, len(questions))
+		for i := range questions {
+			answers[i] = password
+		}
+		return answers, nil
+	}
+}
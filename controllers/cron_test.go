@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+)
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{spec: "0 2 * * *"},
+		{spec: "*/15 * * * *"},
+		{spec: "0 0 1,15 * *"},
+		{spec: "5/15 9-17 * jan-jun mon-fri"},
+		{spec: "0 0 * * 7"},
+		{spec: "@daily"},
+		{spec: " @weekly "},
+		{spec: "@every 30s"},
+		{spec: "@every 500ms", wantErr: true},
+		{spec: "@every", wantErr: true},
+		{spec: "@reboot", wantErr: true},
+		{spec: "0 2 * *", wantErr: true},
+		{spec: "0 2 * * * *", wantErr: true},
+		{spec: "60 * * * *", wantErr: true},
+		{spec: "* 24 * * *", wantErr: true},
+		{spec: "* * 0 * *", wantErr: true},
+		{spec: "* * * 13 *", wantErr: true},
+		{spec: "* * * * 8", wantErr: true},
+		{spec: "5-1 * * * *", wantErr: true},
+		{spec: "*/0 * * * *", wantErr: true},
+		{spec: "a * * * *", wantErr: true},
+		{spec: "* * * foo *", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := parseCron(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseCron(%q) error = %v, want error %v", tt.spec, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	tests := []struct {
+		spec string
+		from string
+		want string
+	}{
+		{spec: "0 2 * * *", from: "2026-03-10T01:30:00Z", want: "2026-03-10T02:00:00Z"},
+		{spec: "0 2 * * *", from: "2026-03-10T02:00:00Z", want: "2026-03-11T02:00:00Z"},
+		{spec: "0 2 * * *", from: "2026-03-10T02:00:30Z", want: "2026-03-11T02:00:00Z"},
+		{spec: "*/15 * * * *", from: "2026-03-10T10:07:00Z", want: "2026-03-10T10:15:00Z"},
+		{spec: "5/15 * * * *", from: "2026-03-10T10:51:00Z", want: "2026-03-10T11:05:00Z"},
+		{spec: "0 0 1,15 * *", from: "2026-03-02T00:00:00Z", want: "2026-03-15T00:00:00Z"},
+		{spec: "0 0 31 * *", from: "2026-04-01T00:00:00Z", want: "2026-05-31T00:00:00Z"},
+		{spec: "0 0 29 feb *", from: "2026-03-01T00:00:00Z", want: "2028-02-29T00:00:00Z"},
+		{spec: "0 0 30 feb *", from: "2026-03-01T00:00:00Z", want: "0001-01-01T00:00:00Z"},
+		{spec: "30 9 * * mon-fri", from: "2026-10-16T10:00:00Z", want: "2026-10-19T09:30:00Z"},
+		{spec: "0 0 * * 7", from: "2026-10-16T00:00:00Z", want: "2026-10-18T00:00:00Z"},
+		// Either the day of month, or the day of week
+		{spec: "0 0 13 * fri", from: "2026-10-10T00:00:00Z", want: "2026-10-13T00:00:00Z"},
+		{spec: "0 0 13 * fri", from: "2026-10-13T00:00:00Z", want: "2026-10-16T00:00:00Z"},
+		// Both of them, when either starts with a star
+		{spec: "0 0 */2 * fri", from: "2026-10-02T00:00:00Z", want: "2026-10-09T00:00:00Z"},
+		{spec: "@yearly", from: "2026-10-17T00:00:00Z", want: "2027-01-01T00:00:00Z"},
+		{spec: "@hourly", from: "2026-10-17T23:59:00Z", want: "2026-10-18T00:00:00Z"},
+		{spec: "@every 90s", from: "2026-10-17T10:00:10Z", want: "2026-10-17T10:01:40Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec+" from "+tt.from, func(t *testing.T) {
+			schedule, err := parseCron(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := schedule.next(mustParseTime(t, tt.from))
+			if want := mustParseTime(t, tt.want); !got.Equal(want) {
+				t.Errorf("next() = %s, want %s", got.Format(time.RFC3339), want.Format(time.RFC3339))
+			}
+		})
+	}
+}
+
+func TestCronNextInLocation(t *testing.T) {
+	location, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip(err)
+	}
+	schedule, err := parseCron("30 2 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 02:30 doesn't exist on the day the clocks are set forward, from 02:00 to 03:00
+	got := schedule.next(time.Date(2026, time.March, 28, 12, 0, 0, 0, location))
+	want := time.Date(2026, time.March, 30, 2, 30, 0, 0, location)
+	if !got.Equal(want) {
+		t.Errorf("next() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/redhat-cop/operator-utils/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// Capabilities required by the configurations other than the running and candidate datastores
+const (
+	startupCapability = "urn:ietf:params:netconf:capability:startup:1.0"
+	urlCapability     = "urn:ietf:params:netconf:capability:url:1.0"
+)
+
+// configTarget renders the content of the target, or source, element identifying the configuration, and checks
+// the NETCONF server supports it. The vendored client only renders the running and candidate datastores.
+func configTarget(target netconfv1.ConfigTarget, capabilities []string) (string, error) {
+	switch {
+	case target.Datastore != "" && target.URL != "":
+		return "", fmt.Errorf("only one of datastore and url can be set")
+	case target.URL != "":
+		if !hasCapability(capabilities, urlCapability) {
+			return "", fmt.Errorf("the NETCONF server doesn't support the %s capability", urlCapability)
+		}
+		return "<url>" + escapeXML(target.URL) + "</url>", nil
+	}
+
+	switch target.Datastore {
+	case "":
+		return "", fmt.Errorf("either datastore or url must be set")
+	case "startup":
+		if !hasCapability(capabilities, startupCapability) {
+			return "", fmt.Errorf("the NETCONF server doesn't support the %s capability", startupCapability)
+		}
+	case "running", "candidate":
+	default:
+		return "", fmt.Errorf("unsupported datastore %s", target.Datastore)
+	}
+	return "<" + target.Datastore + "/>", nil
+}
+
+// configSource renders the content of the source element identifying the configuration to copy. An inline
+// configuration, possibly read from a ConfigMap, is checked to be well-formed.
+func configSource(
+	r util.ReconcilerBase, namespace string, source netconfv1.ConfigSource, capabilities []string,
+) (string, error) {
+	set := 0
+	for _, isSet := range []bool{
+		source.Datastore != "", source.URL != "", source.Config != "", source.ConfigMapKeyRef != nil,
+	} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return "", fmt.Errorf("exactly one of datastore, url, config and configMapKeyRef must be set")
+	}
+
+	config := source.Config
+	if source.ConfigMapKeyRef != nil {
+		var err error
+		config, err = configMapKey(r, namespace, source.ConfigMapKeyRef)
+		if err != nil {
+			return "", err
+		}
+	}
+	if config == "" {
+		return configTarget(source.ConfigTarget, capabilities)
+	}
+
+	if _, err := parseXML(config); err != nil {
+		return "", fmt.Errorf("invalid configuration: %w", err)
+	}
+	return "<config>" + config + "</config>", nil
+}
+
+// configMapKey reads the configuration held by the ConfigMap key.
+func configMapKey(r util.ReconcilerBase, namespace string, ref *corev1.ConfigMapKeySelector) (string, error) {
+	configMap := &corev1.ConfigMap{}
+	namespacedName := types.NamespacedName{Namespace: namespace, Name: ref.Name}
+	err := r.GetClient().Get(context.Background(), namespacedName, configMap)
+	if err != nil {
+		return "", fmt.Errorf("failed to read configuration ConfigMap %s: %w", namespacedName, err)
+	}
+	config, ok := configMap.Data[ref.Key]
+	if !ok || config == "" {
+		return "", fmt.Errorf("ConfigMap %s has no %s key", namespacedName, ref.Key)
+	}
+	return config, nil
+}
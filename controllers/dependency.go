@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-logr/logr"
+	"github.com/redhat-cop/operator-utils/pkg/util"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sort"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// dependsOnField indexes the operations by their dependencies, identified as `<kind>/<name>`
+const dependsOnField = ".spec.dependsOn"
+
+// dependentObject is implemented by the kinds that can depend on, or be depended on by, other operations.
+type dependentObject interface {
+	client.Object
+	conditionsAware
+}
+
+// dependencyKinds are the kinds that can be depended on, by kind name.
+var dependencyKinds = map[string]func() dependentObject{
+	"Commit":                func() dependentObject { return &netconfv1.Commit{} },
+	"ConfigBackup":          func() dependentObject { return &netconfv1.ConfigBackup{} },
+	"ConfigRestore":         func() dependentObject { return &netconfv1.ConfigRestore{} },
+	"CopyConfig":            func() dependentObject { return &netconfv1.CopyConfig{} },
+	"CreateSubscription":    func() dependentObject { return &netconfv1.CreateSubscription{} },
+	"DeleteConfig":          func() dependentObject { return &netconfv1.DeleteConfig{} },
+	"DiscardChanges":        func() dependentObject { return &netconfv1.DiscardChanges{} },
+	"EditConfig":            func() dependentObject { return &netconfv1.EditConfig{} },
+	"EstablishSubscription": func() dependentObject { return &netconfv1.EstablishSubscription{} },
+	"Get":                   func() dependentObject { return &netconfv1.Get{} },
+	"GetConfig":             func() dependentObject { return &netconfv1.GetConfig{} },
+	"Lock":                  func() dependentObject { return &netconfv1.Lock{} },
+	"NetworkTransaction":    func() dependentObject { return &netconfv1.NetworkTransaction{} },
+	"RPC":                   func() dependentObject { return &netconfv1.RPC{} },
+	"Unlock":                func() dependentObject { return &netconfv1.Unlock{} },
+	"Validate":              func() dependentObject { return &netconfv1.Validate{} },
+	"Workflow":              func() dependentObject { return &netconfv1.Workflow{} },
+}
+
+func dependencyKindNames() []string {
+	kinds := make([]string, 0, len(dependencyKinds))
+	for kind := range dependencyKinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// checkDependencies checks every dependency exists, and is Ready, and reports the DependencyBlocked condition.
+// Until then, the operation is pending: the conditions, along with Ready, are reported as false with the Pending
+// reason, and the operation is reconciled again once a dependency gets Ready. A dependency that failed for good
+// rather fails the operation, until the dependency gets Ready. It returns whether the operation can be started,
+// along with the error of an invalid dependency, or of the API server.
+func checkDependencies(
+	r util.ReconcilerBase, obj dependentObject, dependencies netconfv1.Dependencies, log logr.Logger,
+	conditionTypes ...string,
+) (bool, error) {
+	for _, dependency := range dependencies {
+		err := validateDependency(r, obj.GetNamespace(), dependency)
+		var condErr *conditionError
+		if err != nil && !errors.As(err, &condErr) {
+			log.Error(err, "Failed to check the dependency.")
+			return false, err
+		}
+		setDependencyBlocked(obj, err)
+		if err == nil {
+			continue
+		}
+		switch conditionReason(err) {
+		case netconfv1.InvalidDependencyReason:
+			log.Error(err, "Invalid dependency.")
+			setFailed(obj, err, conditionTypes...)
+			return false, err
+		case netconfv1.DependencyFailedReason:
+			log.Info(fmt.Sprintf("%s is blocked: %s", obj.GetName(), err))
+			setFailed(obj, err, conditionTypes...)
+			return false, nil
+		}
+		log.Info(fmt.Sprintf("%s is pending: %s", obj.GetName(), err))
+		setConditions(
+			obj, metav1.ConditionFalse, netconfv1.PendingReason, err.Error(),
+			append(conditionTypes, netconfv1.ReadyCondition)...,
+		)
+		return false, nil
+	}
+	return true, nil
+}
+
+// dependencyFailed reports whether the dependency failed for good in its current generation: its retries are
+// exhausted, or the one-shot operation completed without being Ready. It only gets Ready once executed again.
+func dependencyFailed(dependency conditionsAware) bool {
+	ready := meta.FindStatusCondition(dependency.GetConditions(), netconfv1.ReadyCondition)
+	if ready == nil || ready.Status == metav1.ConditionTrue || ready.ObservedGeneration != dependency.GetGeneration() {
+		return false
+	}
+	if ready.Reason == netconfv1.RetriesExhaustedReason {
+		return true
+	}
+	completed, ok := dependency.(interface{ GetCompletedAt() *metav1.Time })
+	return ok && completed.GetCompletedAt() != nil
+}
+
+// watchDependencies indexes the operations of the kind by their dependencies, and enqueues them whenever one of
+// their dependencies is created, or gets Ready.
+func watchDependencies(
+	mgr manager.Manager, c controller.Controller, obj client.Object, list client.ObjectList,
+	dependencies func(obj client.Object) netconfv1.Dependencies,
+) error {
+	err := mgr.GetFieldIndexer().IndexField(
+		context.Background(), obj, dependsOnField, func(obj client.Object) []string {
+			var keys []string
+			for _, dependency := range dependencies(obj) {
+				keys = append(keys, dependency.Kind+"/"+dependency.Name)
+			}
+			return keys
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, kind := range dependencyKindNames() {
+		kind := kind
+		err = c.Watch(
+			&source.Kind{Type: dependencyKinds[kind]()},
+			handler.EnqueueRequestsFromMapFunc(
+				func(dependency client.Object) []reconcile.Request {
+					dependents := list.DeepCopyObject().(client.ObjectList)
+					err := mgr.GetClient().List(
+						context.Background(), dependents, client.InNamespace(dependency.GetNamespace()),
+						client.MatchingFields{dependsOnField: kind + "/" + dependency.GetName()},
+					)
+					if err != nil {
+						return nil
+					}
+					items, err := meta.ExtractList(dependents)
+					if err != nil {
+						return nil
+					}
+					requests := make([]reconcile.Request, 0, len(items))
+					for _, item := range items {
+						dependent, ok := item.(client.Object)
+						if !ok {
+							continue
+						}
+						requests = append(
+							requests, reconcile.Request{
+								NamespacedName: types.NamespacedName{
+									Namespace: dependent.GetNamespace(), Name: dependent.GetName(),
+								},
+							},
+						)
+					}
+					return requests
+				},
+			),
+			dependencyReadyPredicate(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dependencyReadyPredicate filters the events of the dependencies down to their creation, and to the updates
+// making them Ready, in their current generation, or failing them for good.
+func dependencyReadyPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool { return true },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			newObj, ok := e.ObjectNew.(conditionsAware)
+			if !ok {
+				return false
+			}
+			oldObj, ok := e.ObjectOld.(conditionsAware)
+			if dependencyFailed(newObj) {
+				return !ok || !dependencyFailed(oldObj)
+			}
+			if !isConditionTrue(newObj, netconfv1.ReadyCondition) {
+				return false
+			}
+			if !ok {
+				return true
+			}
+			oldReady := meta.FindStatusCondition(oldObj.GetConditions(), netconfv1.ReadyCondition)
+			newReady := meta.FindStatusCondition(newObj.GetConditions(), netconfv1.ReadyCondition)
+			return oldReady == nil || oldReady.Status != metav1.ConditionTrue ||
+				oldReady.ObservedGeneration != newReady.ObservedGeneration
+		},
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
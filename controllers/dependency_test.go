@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+func TestDecodeDependencies(t *testing.T) {
+	lock := netconfv1.DependsOn{Kind: "Lock", Name: "lock-candidate"}
+	tests := []struct {
+		name      string
+		dependsOn string
+		want      netconfv1.Dependencies
+	}{
+		{name: "list", dependsOn: `[{"kind": "Lock", "name": "lock-candidate"}]`, want: netconfv1.Dependencies{lock}},
+		{
+			name:      "single dependency",
+			dependsOn: `{"kind": "Lock", "name": "lock-candidate"}`,
+			want:      netconfv1.Dependencies{lock},
+		},
+		{name: "empty dependency", dependsOn: `{}`},
+		{name: "null", dependsOn: `null`},
+		{name: "other value", dependsOn: `"lock-candidate"`, want: netconfv1.Dependencies{{Kind: `"lock-candidate"`}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var spec netconfv1.CommitSpec
+			if err := json.Unmarshal([]byte(`{"dependsOn": `+tt.dependsOn+`}`), &spec); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(spec.DependsOn, tt.want) {
+				t.Errorf("dependsOn = %v, want %v", spec.DependsOn, tt.want)
+			}
+		})
+	}
+}
+
+// testLock returns a Lock in its first generation, reporting the Ready condition with the reason, true when
+// Succeeded.
+func testLock(name, reason string) *netconfv1.Lock {
+	lock := &netconfv1.Lock{ObjectMeta: metav1.ObjectMeta{Name: name, Generation: 1}}
+	status := metav1.ConditionFalse
+	if reason == netconfv1.SucceededReason {
+		status = metav1.ConditionTrue
+	}
+	setConditions(lock, status, reason, "", netconfv1.ReadyCondition)
+	return lock
+}
+
+func TestCheckDependencies(t *testing.T) {
+	completed := testLock("completed", netconfv1.DataMissingReason)
+	completed.CompletedAt = &metav1.Time{Time: time.Now()}
+	objects := map[string]client.Object{
+		"ready":     testLock("ready", netconfv1.SucceededReason),
+		"failing":   testLock("failing", netconfv1.LockDeniedReason),
+		"exhausted": testLock("exhausted", netconfv1.RetriesExhaustedReason),
+		"completed": completed,
+	}
+	tests := []struct {
+		name        string
+		dependsOn   netconfv1.Dependencies
+		getErr      error
+		want        bool
+		wantErr     bool
+		wantReady   string
+		wantBlocked string
+	}{
+		{
+			name:        "ready",
+			dependsOn:   netconfv1.Dependencies{{Kind: "Lock", Name: "ready"}},
+			want:        true,
+			wantBlocked: netconfv1.DependencyReadyReason,
+		},
+		{
+			name:        "not found",
+			dependsOn:   netconfv1.Dependencies{{Kind: "Lock", Name: "ready"}, {Kind: "Lock", Name: "missing"}},
+			wantReady:   netconfv1.PendingReason,
+			wantBlocked: netconfv1.DependencyNotFoundReason,
+		},
+		{
+			name:        "not ready",
+			dependsOn:   netconfv1.Dependencies{{Kind: "Lock", Name: "failing"}},
+			wantReady:   netconfv1.PendingReason,
+			wantBlocked: netconfv1.DependencyNotReadyReason,
+		},
+		{
+			name:        "retries exhausted",
+			dependsOn:   netconfv1.Dependencies{{Kind: "Lock", Name: "exhausted"}},
+			wantReady:   netconfv1.DependencyFailedReason,
+			wantBlocked: netconfv1.DependencyFailedReason,
+		},
+		{
+			name:        "completed without being ready",
+			dependsOn:   netconfv1.Dependencies{{Kind: "Lock", Name: "completed"}},
+			wantReady:   netconfv1.DependencyFailedReason,
+			wantBlocked: netconfv1.DependencyFailedReason,
+		},
+		{
+			name:        "invalid kind",
+			dependsOn:   netconfv1.Dependencies{{Kind: "MountPoint", Name: "device"}},
+			wantErr:     true,
+			wantReady:   netconfv1.InvalidDependencyReason,
+			wantBlocked: netconfv1.InvalidDependencyReason,
+		},
+		{
+			name:      "API server unavailable",
+			dependsOn: netconfv1.Dependencies{{Kind: "Lock", Name: "ready"}},
+			getErr:    apierrors.NewServiceUnavailable("unavailable"),
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconcilerBase(&testClient{objects: objects, getErr: tt.getErr})
+			obj := &netconfv1.Commit{ObjectMeta: metav1.ObjectMeta{Name: "commit", Generation: 1}}
+
+			got, err := checkDependencies(r, obj, tt.dependsOn, logr.Discard(), netconfv1.CommittedCondition)
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("checkDependencies() = %v, %v, want %v, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+			if reason := conditionReasonOf(obj, netconfv1.ReadyCondition); reason != tt.wantReady {
+				t.Errorf("checkDependencies() reported Ready with reason %q, want %q", reason, tt.wantReady)
+			}
+			if reason := conditionReasonOf(obj, netconfv1.DependencyBlockedCondition); reason != tt.wantBlocked {
+				t.Errorf("checkDependencies() reported DependencyBlocked with reason %q, want %q", reason, tt.wantBlocked)
+			}
+		})
+	}
+}
+
+// conditionReasonOf returns the reason of the condition, if reported.
+func conditionReasonOf(obj conditionsAware, conditionType string) string {
+	if condition := meta.FindStatusCondition(obj.GetConditions(), conditionType); condition != nil {
+		return condition.Reason
+	}
+	return ""
+}
+
+func TestDependencyReadyPredicate(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new *netconfv1.Lock
+		want     bool
+	}{
+		{
+			name: "gets Ready",
+			old:  testLock("lock", netconfv1.LockDeniedReason),
+			new:  testLock("lock", netconfv1.SucceededReason),
+			want: true,
+		},
+		{
+			name: "still Ready",
+			old:  testLock("lock", netconfv1.SucceededReason),
+			new:  testLock("lock", netconfv1.SucceededReason),
+		},
+		{name: "retried", old: testLock("lock", netconfv1.LockDeniedReason), new: testLock("lock", netconfv1.RetryingReason)},
+		{
+			name: "retries exhausted",
+			old:  testLock("lock", netconfv1.RetryingReason),
+			new:  testLock("lock", netconfv1.RetriesExhaustedReason),
+			want: true,
+		},
+		{
+			name: "still failed",
+			old:  testLock("lock", netconfv1.RetriesExhaustedReason),
+			new:  testLock("lock", netconfv1.RetriesExhaustedReason),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := event.UpdateEvent{ObjectOld: tt.old, ObjectNew: tt.new}
+			if got := dependencyReadyPredicate().Update(e); got != tt.want {
+				t.Errorf("dependencyReadyPredicate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
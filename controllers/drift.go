@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+	"time"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// defaultDriftCheckInterval is used when the EditConfig doesn't define its drift check interval.
+const defaultDriftCheckInterval = 300 * time.Second
+
+// manageDrift compares the configuration of the device with the XML payload of the EditConfig, reports the
+// differences, and re-applies the configuration when the drift policy is `enforce`.
+func (r *EditConfigReconciler) manageDrift(obj *netconfv1.EditConfig, payload string, log logr.Logger) error {
+	log.Info(fmt.Sprintf("%s: Check EditConfig %s for drift.", obj.Spec.MountPoint, obj.Name))
+
+	s, err := r.sessions.Acquire(obj.GetMountPointNamespacedName(obj.Spec.MountPoint))
+	if err != nil {
+		err = mountPointUnavailable(obj.Spec.MountPoint)
+		setConditions(obj, metav1.ConditionUnknown, conditionReason(err), err.Error(), netconfv1.DriftedCondition)
+		return err
+	}
+	defer r.sessions.Release(s)
+
+	diff, err := checkDrift(obj, s, payload, r.namespaces(obj))
+	if err != nil {
+		log.Info(fmt.Sprintf("%s: Failed to check EditConfig %s for drift: %s", obj.Spec.MountPoint, obj.Name, err))
+		setConditions(obj, metav1.ConditionUnknown, conditionReason(err), err.Error(), netconfv1.DriftedCondition)
+		return err
+	}
+
+	now := metav1.Now()
+	obj.LastDriftCheckTime = &now
+	obj.Diff = strings.Join(diff, "\n")
+	if len(diff) == 0 {
+		setConditions(
+			obj, metav1.ConditionFalse, netconfv1.InSyncReason, "The configuration matches the XML payload",
+			netconfv1.DriftedCondition,
+		)
+		return nil
+	}
+
+	summary := fmt.Sprintf(
+		"%d difference(s) with the XML payload found in the %s datastore", len(diff), driftSource(obj),
+	)
+	log.Info(fmt.Sprintf("%s: EditConfig %s drifted: %s", obj.Spec.MountPoint, obj.Name, summary))
+	r.GetRecorder().Event(obj, "Warning", netconfv1.ConfigurationDriftedReason, summary)
+	setConditions(obj, metav1.ConditionTrue, netconfv1.ConfigurationDriftedReason, summary, netconfv1.DriftedCondition)
+	if obj.Spec.DriftPolicy != netconfv1.EnforceDriftPolicy {
+		return nil
+	}
+
+	err = r.apply(obj, s, log, configEdit{operation: obj.Spec.Operation, xml: payload})
+	if err != nil {
+		return err
+	}
+	obj.DriftCorrections++
+	obj.LastDriftCorrectionTime = &now
+	setConditions(
+		obj, metav1.ConditionFalse, netconfv1.DriftCorrectedReason, "The configuration was re-applied: "+summary,
+		netconfv1.DriftedCondition,
+	)
+	r.GetRecorder().Event(obj, "Normal", netconfv1.DriftCorrectedReason, "The configuration was re-applied")
+	return nil
+}
+
+// checkDrift retrieves the configuration covered by the XML payload of the EditConfig, and returns its
+// differences with the payload, its list entries being identified by their keys.
+func checkDrift(obj *netconfv1.EditConfig, s Session, payload string, ns *yangNamespaces) ([]string, error) {
+	desired, err := parseXML(payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid XML payload: %w", err)
+	}
+
+	actual, err := getConfigNodes(s, driftSource(obj), desired, obj.Spec.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	return diffXML("", desired, actual, ns), nil
+}
+
+// driftSource returns the datastore the configuration ends up in: `running` once committed, the target otherwise.
+func driftSource(obj *netconfv1.EditConfig) string {
+	if obj.Spec.Commit {
+		return "running"
+	}
+	return obj.Spec.Target
+}
+
+func driftCheckInterval(obj *netconfv1.EditConfig) time.Duration {
+	if obj.Spec.DriftCheckInterval <= 0 {
+		return defaultDriftCheckInterval
+	}
+	return time.Duration(obj.Spec.DriftCheckInterval) * time.Second
+}
+
+// nextDriftCheck returns the time left until the next drift check, as the EditConfig may be reconciled in between,
+// e.g. when an input of its template changes.
+func nextDriftCheck(obj *netconfv1.EditConfig) time.Duration {
+	if obj.LastDriftCheckTime == nil {
+		return 0
+	}
+	return time.Until(obj.LastDriftCheckTime.Add(driftCheckInterval(obj)))
+}
+
+// isApplied reports whether the EditConfig was applied, and committed when requested, in its current generation.
+func isApplied(obj *netconfv1.EditConfig) bool {
+	if obj.Spec.Commit && !isConditionTrue(obj, netconfv1.CommittedCondition) {
+		return false
+	}
+	return isConditionTrue(obj, netconfv1.AppliedCondition)
+}
@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/go-logr/logr"
+	"github.com/openshift-telco/go-netconf-client/netconf/message"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// candidateCapability is required to preview the changes through the candidate datastore
+const candidateCapability = "urn:ietf:params:netconf:capability:candidate:1.0"
+
+// dryRun previews the changes the XML payload of the EditConfig would make to the running configuration, and
+// reports them in its status. Nothing is applied, so neither Applied nor Ready are true.
+func (r *EditConfigReconciler) dryRun(obj *netconfv1.EditConfig, s Session, payload string, log logr.Logger) error {
+	conditions := []string{netconfv1.AppliedCondition}
+	if obj.Spec.Commit {
+		conditions = append(conditions, netconfv1.CommittedCondition)
+	}
+	setConditions(
+		obj, metav1.ConditionFalse, netconfv1.DryRunReason, "Nothing is applied in dry-run mode", conditions...,
+	)
+
+	desired, err := parseXML(payload)
+	if err != nil {
+		err = fmt.Errorf("invalid XML payload: %w", err)
+		setFailed(obj, err, netconfv1.PreviewedCondition)
+		return err
+	}
+
+	var changes []string
+	ns := r.namespaces(obj)
+	capabilities := r.sessions.Capabilities(obj.GetMountPointNamespacedName(obj.Spec.MountPoint))
+	if hasCapability(capabilities, candidateCapability) {
+		changes, err = previewCandidate(obj, s, payload, desired, ns, log)
+	} else {
+		changes, err = previewEdit(obj, s, desired, ns)
+	}
+	if err != nil {
+		log.Info(fmt.Sprintf("%s: Failed to preview EditConfig %s: %s", obj.Spec.MountPoint, obj.Name, err))
+		setFailed(obj, err, netconfv1.PreviewedCondition)
+		return err
+	}
+
+	obj.DryRunDiff = strings.Join(changes, "\n")
+	summary := fmt.Sprintf("%d change(s) to the running configuration previewed, nothing was applied", len(changes))
+	log.Info(fmt.Sprintf("%s: Dry run of EditConfig %s: %s", obj.Spec.MountPoint, obj.Name, summary))
+	r.GetRecorder().Event(obj, "Normal", netconfv1.DryRunReason, summary)
+	setConditions(obj, metav1.ConditionTrue, netconfv1.SucceededReason, summary, netconfv1.PreviewedCondition)
+	setConditions(obj, metav1.ConditionFalse, netconfv1.DryRunReason, summary, netconfv1.ReadyCondition)
+	return nil
+}
+
+// previewCandidate pushes the XML payload to the candidate datastore, within a lock, and returns its differences
+// with the running datastore. The changes are discarded afterwards. As the lock can't be granted while the
+// candidate datastore holds uncommitted changes, the differences are the ones of the XML payload.
+func previewCandidate(
+	obj *netconfv1.EditConfig, s Session, payload string, desired []*xmlNode, ns *yangNamespaces, log logr.Logger,
+) ([]string, error) {
+	reply, err := s.SyncRPC(message.NewLock(message.DatastoreCandidate), obj.Spec.Timeout)
+	err = checkReply(reply, err)
+	if err != nil {
+		setReplyStatus(&obj.RPCStatus, reply, err)
+		return nil, err
+	}
+	defer func() {
+		reply, err := s.SyncRPC(message.NewRPC(discardChangesRPC), obj.Spec.Timeout)
+		if err = checkReply(reply, err); err != nil {
+			log.Info(
+				fmt.Sprintf("%s: Failed to discard changes of EditConfig %s: %s", obj.Spec.MountPoint, obj.Name, err),
+			)
+		}
+		reply, err = s.SyncRPC(message.NewUnlock(message.DatastoreCandidate), obj.Spec.Timeout)
+		if err = checkReply(reply, err); err != nil {
+			log.Info(
+				fmt.Sprintf(
+					"%s: Failed to unlock datastore candidate for EditConfig %s: %s", obj.Spec.MountPoint, obj.Name,
+					err,
+				),
+			)
+		}
+	}()
+
+	reply, err = s.SyncRPC(
+		message.NewEditConfig(message.DatastoreCandidate, obj.Spec.Operation, payload), obj.Spec.Timeout,
+	)
+	err = checkReply(reply, err)
+	setReplyStatus(&obj.RPCStatus, reply, err)
+	if err != nil {
+		return nil, err
+	}
+
+	candidate, err := getConfigNodes(s, message.DatastoreCandidate, desired, obj.Spec.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	running, err := getConfigNodes(s, message.DatastoreRunning, desired, obj.Spec.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	return changesXML("", running, candidate, ns), nil
+}
+
+// previewEdit returns the changes the XML payload would make to the running datastore, by applying it to the
+// configuration of the running datastore it covers, as the NETCONF server would.
+func previewEdit(obj *netconfv1.EditConfig, s Session, desired []*xmlNode, ns *yangNamespaces) ([]string, error) {
+	running, err := getConfigNodes(s, message.DatastoreRunning, desired, obj.Spec.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	return changesXML("", running, editNodes(running, desired, obj.Spec.Operation, ns), ns), nil
+}
+
+// getConfigNodes retrieves the configuration of the datastore covered by the nodes.
+func getConfigNodes(s Session, datastore string, nodes []*xmlNode, timeout int32) ([]*xmlNode, error) {
+	reply, err := s.SyncRPC(
+		message.NewGetConfig(datastore, message.FilterTypeSubtree, subtreeFilter(nodes)), timeout,
+	)
+	err = checkReply(reply, err)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := dataNodes(reply.RawReply)
+	if err != nil {
+		return nil, fmt.Errorf("invalid get-config reply: %w", err)
+	}
+	return config, nil
+}
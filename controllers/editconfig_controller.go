@@ -22,16 +22,22 @@ import (
 	"fmt"
 	"github.com/go-logr/logr"
 	"github.com/openshift-telco/go-netconf-client/netconf/message"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sync"
 	"time"
 
 	"github.com/redhat-cop/operator-utils/pkg/util"
@@ -46,6 +52,11 @@ import (
 // EditConfigReconciler reconciles a EditConfig object
 type EditConfigReconciler struct {
 	util.ReconcilerBase
+	sessions SessionProvider
+	// controller watches the kinds the parameters of the templates are read from, as they are encountered
+	controller  controller.Controller
+	watches     map[schema.GroupKind]bool
+	watchesLock sync.Mutex
 }
 
 // AddEditConfig Add creates a new MountPoint Controller and adds it to the Manager.
@@ -74,8 +85,29 @@ func (r *EditConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return r.ManageError(ctx, instance, err)
 	}
 
+	// Managing CR Finalization, before validation, so the EditConfig can be deleted once its MountPoint is gone.
+	if util.IsBeingDeleted(instance) {
+		if !util.HasFinalizer(instance, editConfigFinalizer) {
+			return reconcile.Result{}, nil
+		}
+		err := r.manageCleanUpLogic(instance, log)
+
+		if err != nil {
+			log.Error(err, "unable to delete instance", "instance", instance)
+			return r.ManageError(ctx, instance, err)
+		}
+		util.RemoveFinalizer(instance, editConfigFinalizer)
+		err = r.GetClient().Update(context.Background(), instance)
+		if err != nil {
+			log.Error(err, "unable to update instance", "instance", instance)
+			return r.ManageError(ctx, instance, err)
+		}
+		return reconcile.Result{}, nil
+	}
+
 	// Managing CR validation
 	if ok, err := r.isValid(instance); !ok {
+		setFailed(instance, err, netconfv1.AppliedCondition)
 		return r.ManageErrorWithRequeue(ctx, instance, err, 2*time.Second)
 	}
 
@@ -89,25 +121,38 @@ func (r *EditConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return reconcile.Result{}, nil
 	}
 
-	// Managing CR Finalization
-	if util.IsBeingDeleted(instance) {
-		return reconcile.Result{}, nil
+	// Wait for the retry scheduled following a transient failure, if any
+	if wait := startAttempt(instance.Spec.RetryPolicy, &instance.RPCStatus, instance.Generation); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
 	}
 
 	err = r.manageOperatorLogic(instance, log)
 	if err != nil {
-		return r.ManageError(ctx, instance, err)
+		return manageRetry(ctx, r.ReconcilerBase, instance, instance.Spec.RetryPolicy, &instance.RPCStatus, err)
 	}
 
-	return r.ManageSuccess(ctx, instance)
+	result, err := r.ManageSuccess(ctx, instance)
+	if err == nil && instance.Spec.DriftPolicy != "" && !instance.Spec.DryRun {
+		// Check for a drift periodically
+		result.RequeueAfter = driftCheckInterval(instance)
+		if next := nextDriftCheck(instance); next > 0 {
+			result.RequeueAfter = next
+		}
+	}
+	return result, err
 }
 
 func (r *EditConfigReconciler) isInitialized(obj metav1.Object) bool {
-	_, ok := obj.(*netconfv1.EditConfig)
+	instance, ok := obj.(*netconfv1.EditConfig)
 	if !ok {
 		return false
 	}
-	return true
+	// The finalizer is only required to prune the configuration
+	if instance.Spec.PrunePolicy == "" || util.HasFinalizer(instance, editConfigFinalizer) {
+		return true
+	}
+	util.AddFinalizer(instance, editConfigFinalizer)
+	return false
 
 }
 
@@ -118,17 +163,47 @@ func (r *EditConfigReconciler) isValid(obj metav1.Object) (bool, error) {
 	}
 
 	exists := CheckMountPointExists(
-		r.ReconcilerBase,
+		r.ReconcilerBase, r.sessions,
 		types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.MountPoint},
 	)
 	if !exists {
-		return false, errors.New(fmt.Sprintf("MountPoint %s doesn't exists", instance.Spec.MountPoint))
+		return false, mountPointUnavailable(instance.Spec.MountPoint)
 	}
 
 	return true, nil
 }
 
 func (r *EditConfigReconciler) manageOperatorLogic(obj *netconfv1.EditConfig, log logr.Logger) error {
+	payload, hash, err := r.payload(obj)
+	if err != nil {
+		log.Error(err, fmt.Sprintf("%s: Failed to render EditConfig %s.", obj.Spec.MountPoint, obj.Name))
+		setFailed(obj, err, netconfv1.AppliedCondition)
+		return err
+	}
+	// The payload rendered from the template changes along with its inputs, within the same generation
+	unchanged := obj.PayloadHash == "" || obj.PayloadHash == hash
+
+	if obj.Spec.DryRun {
+		return r.manageDryRun(obj, payload, hash, unchanged, log)
+	}
+	meta.RemoveStatusCondition(&obj.Conditions, netconfv1.PreviewedCondition)
+	obj.DryRunDiff = ""
+
+	if obj.Spec.DriftPolicy == "" {
+		meta.RemoveStatusCondition(&obj.Conditions, netconfv1.DriftedCondition)
+		obj.Diff = ""
+	}
+	if isApplied(obj) && unchanged {
+		// Already applied in its current generation, only check for a drift, once due.
+		if obj.Spec.DriftPolicy == "" || nextDriftCheck(obj) > 0 {
+			return nil
+		}
+		return r.manageDrift(obj, payload, log)
+	}
+	if !unchanged {
+		log.Info(fmt.Sprintf("%s: The payload of EditConfig %s changed.", obj.Spec.MountPoint, obj.Name))
+	}
+
 	log.Info(
 		fmt.Sprintf(
 			"%s: EditConfig for %s datastore with %s operation.", obj.Spec.MountPoint,
@@ -136,75 +211,213 @@ func (r *EditConfigReconciler) manageOperatorLogic(obj *netconfv1.EditConfig, lo
 		),
 	)
 
-	if !obj.Spec.DependsOn.IsNil() {
-		err := validateDependency(r.ReconcilerBase, obj.Namespace, obj.Spec.DependsOn)
+	ok, err := checkDependencies(r.ReconcilerBase, obj, obj.Spec.DependsOn, log, netconfv1.AppliedCondition)
+	if !ok {
+		return err
+	}
+
+	s, err := r.sessions.Acquire(obj.GetMountPointNamespacedName(obj.Spec.MountPoint))
+	if err != nil {
+		err = mountPointUnavailable(obj.Spec.MountPoint)
+		setFailed(obj, err, netconfv1.AppliedCondition)
+		return err
+	}
+	defer r.sessions.Release(s)
+
+	if obj.Spec.PrunePolicy != "" {
+		err = r.snapshot(obj, s, payload)
 		if err != nil {
-			log.Error(err, "Failed to validate dependency.")
+			log.Error(err, fmt.Sprintf("%s: Failed to snapshot the configuration.", obj.Spec.MountPoint))
+			setFailed(obj, err, netconfv1.AppliedCondition)
 			return err
 		}
 	}
 
-	s := Sessions[obj.GetMountPointNamespacedName(obj.Spec.MountPoint)]
+	err = r.apply(obj, s, log, configEdit{operation: obj.Spec.Operation, xml: payload})
+	if err != nil {
+		return err
+	}
+	obj.PayloadHash = hash
+	return nil
+}
+
+// manageDryRun previews the changes of the XML payload, once per generation, or rendering. The dependency isn't
+// waited for, as nothing is applied.
+func (r *EditConfigReconciler) manageDryRun(
+	obj *netconfv1.EditConfig, payload string, hash string, unchanged bool, log logr.Logger,
+) error {
+	if isConditionTrue(obj, netconfv1.PreviewedCondition) && unchanged {
+		return nil
+	}
+	log.Info(fmt.Sprintf("%s: Dry run of EditConfig %s.", obj.Spec.MountPoint, obj.Name))
 
+	s, err := r.sessions.Acquire(obj.GetMountPointNamespacedName(obj.Spec.MountPoint))
+	if err != nil {
+		err = mountPointUnavailable(obj.Spec.MountPoint)
+		setFailed(obj, err, netconfv1.PreviewedCondition)
+		return err
+	}
+	defer r.sessions.Release(s)
+
+	err = r.dryRun(obj, s, payload, log)
+	if err != nil {
+		return err
+	}
+	obj.PayloadHash = hash
+	return nil
+}
+
+// configEdit is an edit-config to perform against the target datastore of the EditConfig.
+type configEdit struct {
+	operation string
+	xml       string
+}
+
+// apply performs the edit-configs, along with the lock, commit and unlock, as requested by the EditConfig. Upon
+// failure, the changes made to the candidate datastore are discarded, and the lock is released: the session is
+// shared, and a NETCONF server denies the lock it already holds, even to the same session.
+func (r *EditConfigReconciler) apply(
+	obj *netconfv1.EditConfig, s Session, log logr.Logger, edits ...configEdit,
+) (err error) {
+	locked := false
+	defer func() {
+		if err != nil && locked {
+			r.releaseLock(obj, s, log)
+		}
+	}()
 	if obj.Spec.Lock {
 		reply, err := s.SyncRPC(message.NewLock(obj.Spec.Target), obj.Spec.Timeout)
-		if reply == nil {
+		err = checkReply(reply, err)
+		if err != nil {
 			log.Info(
 				fmt.Sprintf(
 					"%s: Failed to lock datastore %s for EditConfig %s", obj.Spec.MountPoint, obj.Spec.Target, obj.Name,
 				),
 			)
-			obj.Status = "failed"
-			obj.RpcReply = reply.RawReply
+			setReplyStatus(&obj.RPCStatus, reply, err)
+			setFailed(obj, err, netconfv1.AppliedCondition)
 			return err
 		}
+		locked = true
 	}
 
-	reply, err := s.SyncRPC(message.NewEditConfig(obj.Spec.Target, obj.Spec.Operation, obj.Spec.XML), obj.Spec.Timeout)
-	if reply == nil {
-		log.Info(fmt.Sprintf("%s: Failed to perform EditConfig %s.", obj.Spec.MountPoint, obj.Name))
-		obj.Status = "failed"
-		obj.RpcReply = reply.RawReply
-		return err
+	var reply *message.RPCReply
+	for _, edit := range edits {
+		var err error
+		reply, err = s.SyncRPC(message.NewEditConfig(obj.Spec.Target, edit.operation, edit.xml), obj.Spec.Timeout)
+		err = checkReply(reply, err)
+		if err != nil {
+			log.Info(fmt.Sprintf("%s: Failed to perform EditConfig %s.", obj.Spec.MountPoint, obj.Name))
+			setReplyStatus(&obj.RPCStatus, reply, err)
+			r.discardCandidate(obj, s, log)
+			setFailed(obj, err, netconfv1.AppliedCondition)
+			return err
+		}
+	}
+
+	if obj.Spec.ValidateBeforeCommit {
+		reply, err := s.SyncRPC(message.NewValidate(obj.Spec.Target), obj.Spec.Timeout)
+		err = checkReply(reply, err)
+		if err != nil {
+			log.Info(fmt.Sprintf("%s: Failed to validate EditConfig %s.", obj.Spec.MountPoint, obj.Name))
+			setReplyStatus(&obj.RPCStatus, reply, err)
+			r.discardChanges(obj, s, log, err)
+			return err
+		}
 	}
+	setConditions(obj, metav1.ConditionTrue, netconfv1.SucceededReason, "", netconfv1.AppliedCondition)
 
+	conditions := []string{netconfv1.AppliedCondition}
 	if obj.Spec.Commit {
+		conditions = append(conditions, netconfv1.CommittedCondition)
 		reply, err := s.SyncRPC(message.NewCommit(), obj.Spec.Timeout)
-		if reply == nil {
+		err = checkReply(reply, err)
+		if err != nil {
 			log.Info(fmt.Sprintf("%s: Failed to commit for EditConfig %s", obj.Spec.MountPoint, obj.Name))
-			obj.Status = "failed"
-			obj.RpcReply = reply.RawReply
+			setReplyStatus(&obj.RPCStatus, reply, err)
+			r.discardCandidate(obj, s, log)
+			setFailed(obj, err, netconfv1.CommittedCondition)
 			return err
 		}
 	}
 
 	if obj.Spec.Unlock {
+		// The lock isn't released again, should the unlock fail
+		locked = false
 		reply, err := s.SyncRPC(message.NewUnlock(obj.Spec.Target), obj.Spec.Timeout)
-		if reply == nil {
+		err = checkReply(reply, err)
+		if err != nil {
 			log.Info(
 				fmt.Sprintf(
 					"%s: Failed to unlock datastore %s for EditConfig %s", obj.Spec.MountPoint, obj.Spec.Target,
 					obj.Name,
 				),
 			)
-			obj.Status = "failed"
-			obj.RpcReply = reply.RawReply
+			setReplyStatus(&obj.RPCStatus, reply, err)
+			setFailed(obj, err)
 			return err
 		}
 	}
 
 	log.Info(fmt.Sprintf("%s: Successfully executed EditConfig %s operation.", obj.Spec.MountPoint, obj.Name))
-	obj.Status = "success"
-	obj.RpcReply = reply.Data
+	setReplyStatus(&obj.RPCStatus, reply, nil)
+	setSucceeded(obj, conditions...)
 	return nil
 }
 
+// discardChanges reverts the candidate datastore to the running configuration, once its validation failed.
+func (r *EditConfigReconciler) discardChanges(obj *netconfv1.EditConfig, s Session, log logr.Logger, err error) {
+	summary := fmt.Sprintf("Validation of the %s datastore failed, the changes were discarded: %s", obj.Spec.Target, err)
+	if discardErr := r.discardCandidate(obj, s, log); discardErr != nil {
+		summary = fmt.Sprintf(
+			"Validation of the %s datastore failed: %s. The changes couldn't be discarded: %s", obj.Spec.Target, err,
+			discardErr,
+		)
+	}
+	r.GetRecorder().Event(obj, "Warning", netconfv1.ValidationFailedReason, summary)
+
+	conditions := []string{netconfv1.AppliedCondition, netconfv1.ReadyCondition}
+	if obj.Spec.Commit {
+		conditions = append(conditions, netconfv1.CommittedCondition)
+	}
+	setConditions(obj, metav1.ConditionFalse, netconfv1.ValidationFailedReason, summary, conditions...)
+}
+
+// discardCandidate reverts the candidate datastore to the running configuration, once the EditConfig failed.
+// Only the candidate datastore holds changes that aren't applied yet.
+func (r *EditConfigReconciler) discardCandidate(obj *netconfv1.EditConfig, s Session, log logr.Logger) error {
+	if obj.Spec.Target != message.DatastoreCandidate {
+		return nil
+	}
+	reply, err := s.SyncRPC(message.NewRPC(discardChangesRPC), obj.Spec.Timeout)
+	err = checkReply(reply, err)
+	if err != nil {
+		log.Info(fmt.Sprintf("%s: Failed to discard changes of EditConfig %s: %s", obj.Spec.MountPoint, obj.Name, err))
+	}
+	return err
+}
+
+// releaseLock unlocks the target datastore, once the EditConfig failed while holding its lock.
+func (r *EditConfigReconciler) releaseLock(obj *netconfv1.EditConfig, s Session, log logr.Logger) {
+	reply, err := s.SyncRPC(message.NewUnlock(obj.Spec.Target), obj.Spec.Timeout)
+	if err = checkReply(reply, err); err != nil {
+		log.Info(
+			fmt.Sprintf(
+				"%s: Failed to unlock datastore %s for EditConfig %s: %s", obj.Spec.MountPoint, obj.Spec.Target,
+				obj.Name, err,
+			),
+		)
+	}
+}
+
 func newEditConfigReconciler(mgr manager.Manager) reconcile.Reconciler {
 	return &EditConfigReconciler{
 		ReconcilerBase: util.NewReconcilerBase(
 			mgr.GetClient(), mgr.GetScheme(), mgr.GetConfig(), mgr.GetEventRecorderFor(editConfigControllerName),
 			mgr.GetAPIReader(),
 		),
+		sessions: sessions,
+		watches:  make(map[schema.GroupKind]bool),
 	}
 }
 
@@ -223,5 +436,100 @@ func addEditConfig(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Re-render the templates whenever their inputs change
+	if reconciler, ok := r.(*EditConfigReconciler); ok {
+		reconciler.controller = c
+	}
+	indexes := map[string]client.IndexerFunc{
+		templateConfigMapRefsField: func(obj client.Object) []string {
+			configMaps, _, _ := parameterRefs(obj.(*netconfv1.EditConfig))
+			return configMaps
+		},
+		templateSecretRefsField: func(obj client.Object) []string {
+			_, secrets, _ := parameterRefs(obj.(*netconfv1.EditConfig))
+			return secrets
+		},
+		templateObjectRefsField: func(obj client.Object) []string {
+			_, _, objects := parameterRefs(obj.(*netconfv1.EditConfig))
+			return objects
+		},
+		templateMountPointField: func(obj client.Object) []string {
+			editConfig := obj.(*netconfv1.EditConfig)
+			if editConfig.Spec.Template == "" {
+				return nil
+			}
+			return []string{editConfig.Spec.MountPoint}
+		},
+	}
+	for field, indexer := range indexes {
+		err = mgr.GetFieldIndexer().IndexField(context.Background(), &netconfv1.EditConfig{}, field, indexer)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.Watch(
+		&source.Kind{Type: &corev1.ConfigMap{}},
+		handler.EnqueueRequestsFromMapFunc(
+			editConfigsReferencing(mgr.GetClient(), templateConfigMapRefsField, client.Object.GetName),
+		),
+		predicate.ResourceVersionChangedPredicate{},
+	)
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(
+		&source.Kind{Type: &corev1.Secret{}},
+		handler.EnqueueRequestsFromMapFunc(
+			editConfigsReferencing(mgr.GetClient(), templateSecretRefsField, client.Object.GetName),
+		),
+		predicate.ResourceVersionChangedPredicate{},
+	)
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(
+		&source.Kind{Type: &netconfv1.MountPoint{}},
+		handler.EnqueueRequestsFromMapFunc(
+			editConfigsReferencing(mgr.GetClient(), templateMountPointField, client.Object.GetName),
+		),
+		predicate.Or(predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{}),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch over the dependencies, to start the EditConfig once they are Ready
+	err = watchDependencies(
+		mgr, c, &netconfv1.EditConfig{}, &netconfv1.EditConfigList{},
+		func(obj client.Object) netconfv1.Dependencies { return obj.(*netconfv1.EditConfig).Spec.DependsOn },
+	)
+	if err != nil {
+		return err
+	}
 	return nil
 }
+
+// editConfigsReferencing maps an object to the EditConfigs referring to it, using the provided field index, and
+// the key identifying the object in this index.
+func editConfigsReferencing(c client.Client, field string, key func(client.Object) string) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		editConfigs := &netconfv1.EditConfigList{}
+		err := c.List(
+			context.Background(), editConfigs, client.InNamespace(obj.GetNamespace()),
+			client.MatchingFields{field: key(obj)},
+		)
+		if err != nil {
+			return nil
+		}
+		requests := make([]reconcile.Request, len(editConfigs.Items))
+		for i, editConfig := range editConfigs.Items {
+			requests[i] = reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: editConfig.Namespace, Name: editConfig.Name},
+			}
+		}
+		return requests
+	}
+}
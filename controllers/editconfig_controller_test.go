@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/openshift-telco/go-netconf-client/netconf"
+	"github.com/openshift-telco/go-netconf-client/netconf/message"
+	"github.com/redhat-cop/operator-utils/pkg/util"
+	"k8s.io/client-go/tools/record"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// testSession is a Session behaving as a NETCONF server: it denies the lock of a datastore it already holds, and
+// replies to the RPCs with the error-tags scripted for their operation, e.g. `commit`, one error-tag per RPC. An
+// empty error-tag lets the RPC succeed. It is also the SessionProvider of its MountPoint.
+type testSession struct {
+	errorTags map[string][]string
+	// The content of the data element replied, by operation
+	data         map[string]string
+	locked       map[string]bool
+	capabilities []string
+	operations   []string
+	// The RPCs received, in XML
+	rpcs []string
+}
+
+func newTestSession(errorTags map[string][]string) *testSession {
+	return &testSession{errorTags: errorTags, locked: make(map[string]bool)}
+}
+
+func (s *testSession) SyncRPC(operation message.RPCMethod, _ int32) (*message.RPCReply, error) {
+	data, err := xml.Marshal(operation)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := parseXML(string(data))
+	if err != nil {
+		return nil, err
+	}
+	rpc := nodes[0].children[0]
+	name := rpc.name.Local
+	s.operations = append(s.operations, name)
+	s.rpcs = append(s.rpcs, string(data))
+
+	tag := ""
+	if tags := s.errorTags[name]; len(tags) != 0 {
+		tag, s.errorTags[name] = tags[0], tags[1:]
+	}
+	if target := rpc.child("target"); tag == "" && target != nil {
+		datastore := target.children[0].name.Local
+		switch {
+		case name == "lock" && s.locked[datastore]:
+			tag = "lock-denied"
+		case name == "lock":
+			s.locked[datastore] = true
+		case name == "unlock" && !s.locked[datastore]:
+			tag = "operation-failed"
+		case name == "unlock":
+			delete(s.locked, datastore)
+		}
+	}
+
+	reply := &message.RPCReply{Ok: tag == "", RawReply: "<rpc-reply><ok/></rpc-reply>"}
+	if data, ok := s.data[name]; ok && tag == "" {
+		reply = &message.RPCReply{Data: data, RawReply: "<rpc-reply><data>" + data + "</data></rpc-reply>"}
+	}
+	if tag != "" {
+		reply.Errors = []message.RPCError{{Type: "application", Tag: tag, Severity: "error", Message: tag}}
+	}
+	return reply, nil
+}
+
+func (s *testSession) CreateNotificationStream(int32, string, string, string, netconf.Callback) error {
+	return nil
+}
+
+func (s *testSession) RegisterListener(string, netconf.Callback) {}
+
+func (s *testSession) RemoveListener(string) {}
+
+func (s *testSession) Acquire(string) (Session, error) {
+	return s, nil
+}
+
+func (s *testSession) Release(Session) {}
+
+func (s *testSession) Healthy(string) bool {
+	return true
+}
+
+func (s *testSession) Capabilities(string) []string {
+	return s.capabilities
+}
+
+func TestEditConfigApply(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    string
+		errorTags map[string][]string
+		want      []string
+		wantErr   bool
+		// Whether the datastore is left locked
+		wantLocked bool
+	}{
+		{
+			name: "applied",
+			want: []string{"lock", "edit-config", "validate", "commit", "unlock"},
+		},
+		{
+			name:      "lock denied",
+			errorTags: map[string][]string{"lock": {"lock-denied"}},
+			want:      []string{"lock"},
+			wantErr:   true,
+		},
+		{
+			name:      "edit failed",
+			errorTags: map[string][]string{"edit-config": {"invalid-value"}},
+			want:      []string{"lock", "edit-config", "discard-changes", "unlock"},
+			wantErr:   true,
+		},
+		{
+			name:      "edit of the running datastore failed",
+			target:    message.DatastoreRunning,
+			errorTags: map[string][]string{"edit-config": {"invalid-value"}},
+			want:      []string{"lock", "edit-config", "unlock"},
+			wantErr:   true,
+		},
+		{
+			name:      "validation failed",
+			errorTags: map[string][]string{"validate": {"operation-failed"}},
+			want:      []string{"lock", "edit-config", "validate", "discard-changes", "unlock"},
+			wantErr:   true,
+		},
+		{
+			name:      "commit failed",
+			errorTags: map[string][]string{"commit": {"operation-failed"}},
+			want:      []string{"lock", "edit-config", "validate", "commit", "discard-changes", "unlock"},
+			wantErr:   true,
+		},
+		{
+			name:      "discard failed",
+			errorTags: map[string][]string{"edit-config": {"invalid-value"}, "discard-changes": {"operation-failed"}},
+			want:      []string{"lock", "edit-config", "discard-changes", "unlock"},
+			wantErr:   true,
+		},
+		{
+			name:       "unlock failed",
+			errorTags:  map[string][]string{"unlock": {"operation-failed"}},
+			want:       []string{"lock", "edit-config", "validate", "commit", "unlock"},
+			wantErr:    true,
+			wantLocked: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &EditConfigReconciler{
+				ReconcilerBase: util.NewReconcilerBase(nil, nil, nil, record.NewFakeRecorder(10), nil),
+			}
+			obj := &netconfv1.EditConfig{
+				Spec: netconfv1.EditConfigSpec{
+					Target:               message.DatastoreCandidate,
+					Lock:                 true,
+					ValidateBeforeCommit: true,
+					Commit:               true,
+					Unlock:               true,
+				},
+			}
+			if tt.target != "" {
+				obj.Spec.Target = tt.target
+				obj.Spec.Commit = false
+				obj.Spec.ValidateBeforeCommit = false
+			}
+			s := newTestSession(tt.errorTags)
+
+			err := r.apply(obj, s, logr.Discard(), configEdit{operation: "merge", xml: interfaces("<name>eth0</name>")})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(s.operations, tt.want) {
+				t.Errorf("apply() sent %q, want %q", s.operations, tt.want)
+			}
+			if locked := s.locked[obj.Spec.Target]; locked != tt.wantLocked {
+				t.Errorf("apply() left the datastore locked = %v, want %v", locked, tt.wantLocked)
+			}
+		})
+	}
+}
@@ -9,7 +9,7 @@ You may obtain a copy of the License at
 
 Unless required by applicable law or agreed to in writing, software
 distributed under the License is distributed on an "AS IS" BASIS,
-WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+WITHOUT WARRANTIES OR netconfv1.SubscribedConditionITIONS OF ANY KIND, either express or implied.
 See the License for the specific language governing permissions and
 limitations under the License.
 */
@@ -47,6 +47,7 @@ import (
 // EstablishSubscriptionReconciler reconciles a EstablishSubscription object
 type EstablishSubscriptionReconciler struct {
 	util.ReconcilerBase
+	sessions SessionProvider
 	recorder record.EventRecorder
 }
 
@@ -78,6 +79,7 @@ func (r *EstablishSubscriptionReconciler) Reconcile(ctx context.Context, req ctr
 
 	// Managing CR validation
 	if ok, err := r.isValid(instance); !ok {
+		setFailed(instance, err, netconfv1.SubscribedCondition)
 		return r.ManageErrorWithRequeue(ctx, instance, err, 2*time.Second)
 	}
 
@@ -111,9 +113,14 @@ func (r *EstablishSubscriptionReconciler) Reconcile(ctx context.Context, req ctr
 		return reconcile.Result{}, nil
 	}
 
+	// Wait for the retry scheduled following a transient failure, if any
+	if wait := startAttempt(instance.Spec.RetryPolicy, &instance.RPCStatus, instance.Generation); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
 	err = r.manageOperatorLogic(instance, log)
 	if err != nil {
-		return r.ManageError(ctx, instance, err)
+		return manageRetry(ctx, r.ReconcilerBase, instance, instance.Spec.RetryPolicy, &instance.RPCStatus, err)
 	}
 
 	return r.ManageSuccess(ctx, instance)
@@ -139,11 +146,11 @@ func (r *EstablishSubscriptionReconciler) isValid(obj metav1.Object) (bool, erro
 	}
 
 	exists := CheckMountPointExists(
-		r.ReconcilerBase,
+		r.ReconcilerBase, r.sessions,
 		types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.MountPoint},
 	)
 	if !exists {
-		return false, fmt.Errorf("MountPoint %s doesn't exists", instance.Spec.MountPoint)
+		return false, mountPointUnavailable(instance.Spec.MountPoint)
 	}
 
 	err := ValidateXML(instance.Spec.XML, message.EstablishSubscription{})
@@ -154,17 +161,24 @@ func (r *EstablishSubscriptionReconciler) isValid(obj metav1.Object) (bool, erro
 	return true, nil
 }
 func (r *EstablishSubscriptionReconciler) manageCleanUpLogic(obj *netconfv1.EstablishSubscription) error {
+	removeSubscribed(establishSubscriptionControllerName + "/" + obj.GetNamespacedName())
+
 	subID := obj.SubscriptionID
 	if subID != "" {
-		s := Sessions[obj.GetMountPointNamespacedName(obj.Spec.MountPoint)]
-		s.Listener.Remove(subID)
+		s, err := r.sessions.Acquire(obj.GetMountPointNamespacedName(obj.Spec.MountPoint))
+		if err != nil {
+			// Without session, the subscription is already gone.
+			return nil
+		}
+		defer r.sessions.Release(s)
+		s.RemoveListener(subID)
 
 		// FIXME move to proper operation
 		delSub := fmt.Sprintf(
 			"<delete-subscription xmlns=\"urn:ietf:params:xml:ns:yang:ietf-event-notifications\"><subscription-id>%s</subscription-id></delete-subscription>",
 			subID,
 		)
-		_, err := s.SyncRPC(message.NewRPC(delSub), 1)
+		_, err = s.SyncRPC(message.NewRPC(delSub), 1)
 		if err != nil {
 			return err
 		}
@@ -178,19 +192,30 @@ func (r *EstablishSubscriptionReconciler) manageOperatorLogic(
 ) error {
 	log.Info(fmt.Sprintf("%s: Establish NETCONF subscription %s.", obj.Spec.MountPoint, obj.Name))
 
-	s := Sessions[obj.GetMountPointNamespacedName(obj.Spec.MountPoint)]
-	reply, err := s.SyncRPC(message.NewEstablishSubscription(obj.Spec.XML), obj.Spec.Timeout)
+	s, err := r.sessions.Acquire(obj.GetMountPointNamespacedName(obj.Spec.MountPoint))
+	if err != nil {
+		err = mountPointUnavailable(obj.Spec.MountPoint)
+		setFailed(obj, err, netconfv1.SubscribedCondition)
+		return err
+	}
+	defer r.sessions.Release(s)
+	key := establishSubscriptionControllerName + "/" + obj.GetNamespacedName()
+	if isSubscribed(key, s, obj.Generation) {
+		log.Info(fmt.Sprintf("%s: NETCONF subscription %s already established.", obj.Spec.MountPoint, obj.Name))
+		setSucceeded(obj, netconfv1.SubscribedCondition)
+		return nil
+	}
 
-	if err != nil || reply.Errors != nil {
+	reply, err := s.SyncRPC(message.NewEstablishSubscription(obj.Spec.XML), obj.Spec.Timeout)
+	err = checkReply(reply, err)
+	if err != nil {
 		log.Info(
 			fmt.Sprintf(
-				"%s: Failed to Establish NETCONF Subscription %s with error %d.", obj.Spec.MountPoint, obj.Name, err,
+				"%s: Failed to Establish NETCONF Subscription %s with error %s.", obj.Spec.MountPoint, obj.Name, err,
 			),
 		)
-		obj.Status = "failed"
-		if reply != nil {
-			obj.RpcReply = reply.RawReply
-		}
+		setReplyStatus(&obj.RPCStatus, reply, err)
+		setFailed(obj, err, netconfv1.SubscribedCondition)
 		return err
 	}
 
@@ -220,10 +245,12 @@ func (r *EstablishSubscriptionReconciler) manageOperatorLogic(
 
 	// Register a new listener for upcoming NETCONF notifications for
 	// that particular stream, identified by its subscriptionID
-	s.Listener.Register(reply.SubscriptionID, notificationCallback)
+	s.RegisterListener(reply.SubscriptionID, notificationCallback)
+	setSubscribed(key, s, obj.Generation)
 
-	obj.Status = "subscribed"
+	setReplyStatus(&obj.RPCStatus, reply, nil)
 	obj.SubscriptionID = reply.SubscriptionID
+	setSucceeded(obj, netconfv1.SubscribedCondition)
 
 	return nil
 }
@@ -235,6 +262,7 @@ func newEstablishSubscriptionReconciler(mgr manager.Manager) reconcile.Reconcile
 			mgr.GetEventRecorderFor(establishSubscriptionControllerName),
 			mgr.GetAPIReader(),
 		),
+		sessions: sessions,
 		recorder: mgr.GetEventRecorderFor(establishSubscriptionControllerName),
 	}
 }
@@ -254,5 +282,11 @@ func addEstablishSubscription(mgr manager.Manager, r reconcile.Reconciler) error
 		return err
 	}
 
+	// Re-establish the subscription whenever the MountPoint session is re-established
+	err = c.Watch(&source.Channel{Source: establishSubscriptionEvents}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"github.com/go-logr/logr"
+	"github.com/redhat-cop/operator-utils/pkg/util"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"time"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// runIDAnnotation executes a one-shot operation again whenever its value changes, like its spec.runID
+const runIDAnnotation = "netconf.openshift-telco.io/run-id"
+
+// executionPredicate reconciles the one-shot operations upon a change of their spec, or of their annotations, so
+// that they can be executed again through the run-id annotation.
+var executionPredicate = predicate.Or(
+	util.ResourceGenerationOrFinalizerChangedPredicate{}, predicate.AnnotationChangedPredicate{},
+)
+
+// manageExecution reports whether the one-shot operation is to be executed: once it completed the execution of its
+// spec, it isn't executed again until its spec, or run-id annotation, changes, and is deleted once its TTL
+// expired. Before executing it again, reset clears the state the operation keeps for its current generation, if any.
+func manageExecution(
+	ctx context.Context, r util.ReconcilerBase, obj dependentObject, spec interface{},
+	policy netconfv1.ExecutionPolicy, status *netconfv1.RPCStatus, reset func(), log logr.Logger,
+) (bool, reconcile.Result, error) {
+	hash, err := executionHash(spec, obj.GetAnnotations()[runIDAnnotation])
+	if err != nil {
+		return false, reconcile.Result{}, err
+	}
+
+	// Operations completed before their executions were recorded
+	ready := meta.FindStatusCondition(obj.GetConditions(), netconfv1.ReadyCondition)
+	if status.SpecHash == "" && isConditionTrue(obj, netconfv1.ReadyCondition) {
+		status.SpecHash = hash
+		status.CompletedAt = &ready.LastTransitionTime
+		err = r.GetClient().Status().Update(ctx, obj)
+		if err != nil {
+			return false, reconcile.Result{}, err
+		}
+	}
+
+	if status.SpecHash != hash {
+		if status.SpecHash != "" {
+			log.Info(fmt.Sprintf("Execute %s again, as its spec, or its run-id, changed.", obj.GetName()))
+			status.CompletedAt = nil
+			status.Retry = nil
+			if reset != nil {
+				reset()
+			}
+		}
+		status.SpecHash = hash
+		return true, reconcile.Result{}, nil
+	}
+	if status.CompletedAt == nil {
+		return true, reconcile.Result{}, nil
+	}
+
+	// Completed: delete the operation once its TTL expired
+	expiry, ok := executionExpiry(policy, status)
+	if !ok {
+		return false, reconcile.Result{}, nil
+	}
+	if wait := time.Until(expiry); wait > 0 {
+		return false, reconcile.Result{RequeueAfter: wait}, nil
+	}
+	log.Info(fmt.Sprintf("Delete %s, completed at %s, as its TTL expired.", obj.GetName(), status.CompletedAt))
+	err = r.GetClient().Delete(ctx, obj)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, reconcile.Result{}, err
+	}
+	return false, reconcile.Result{}, nil
+}
+
+// manageCompletion records the completion of the one-shot operation once Ready, and requeues it until its TTL
+// expires, if any.
+func manageCompletion(
+	ctx context.Context, r util.ReconcilerBase, obj dependentObject, policy netconfv1.ExecutionPolicy,
+	status *netconfv1.RPCStatus,
+) (reconcile.Result, error) {
+	if isConditionTrue(obj, netconfv1.ReadyCondition) {
+		completeExecution(status)
+	}
+	result, err := r.ManageSuccess(ctx, obj)
+	if err != nil || status.CompletedAt == nil {
+		return result, err
+	}
+	if expiry, ok := executionExpiry(policy, status); ok {
+		result.RequeueAfter = time.Until(expiry) + time.Second
+	}
+	return result, err
+}
+
+// completeExecution records the completion of the execution of the one-shot operation, if not already. The other
+// kinds don't record their executions.
+func completeExecution(status *netconfv1.RPCStatus) {
+	if status.SpecHash == "" || status.CompletedAt != nil {
+		return
+	}
+	now := metav1.Now()
+	status.CompletedAt = &now
+}
+
+// executionHash returns the hash of the spec, along with the run-id annotation. The settings of the execution,
+// i.e. the TTL and the retry policy, aren't hashed: changing them doesn't execute the operation again.
+func executionHash(spec interface{}, runID string) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	var fields map[string]interface{}
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return "", err
+	}
+	delete(fields, "ttlSecondsAfterFinished")
+	delete(fields, "retryPolicy")
+	// The members of the maps are sorted once marshaled
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(append(append(data, '\n'), runID...))), nil
+}
+
+// executionExpiry returns the time the completed operation is deleted, if any.
+func executionExpiry(policy netconfv1.ExecutionPolicy, status *netconfv1.RPCStatus) (time.Time, bool) {
+	if policy.TTLSecondsAfterFinished == nil || status.CompletedAt == nil {
+		return time.Time{}, false
+	}
+	return status.CompletedAt.Add(time.Duration(*policy.TTLSecondsAfterFinished) * time.Second), true
+}
@@ -0,0 +1,228 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/redhat-cop/operator-utils/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// testClient is a client.Client holding the objects by name, and recording the deletions and the status updates.
+// Its other methods aren't implemented.
+type testClient struct {
+	client.Client
+	objects       map[string]client.Object
+	getErr        error
+	deleted       []string
+	statusUpdates int
+}
+
+func (c *testClient) Get(_ context.Context, key client.ObjectKey, obj client.Object) error {
+	if c.getErr != nil {
+		return c.getErr
+	}
+	stored, ok := c.objects[key.Name]
+	if !ok || reflect.TypeOf(stored) != reflect.TypeOf(obj) {
+		return apierrors.NewNotFound(schema.GroupResource{}, key.Name)
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(stored.DeepCopyObject()).Elem())
+	return nil
+}
+
+func (c *testClient) Create(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+	if c.objects == nil {
+		c.objects = make(map[string]client.Object)
+	}
+	c.objects[obj.GetName()] = obj
+	return nil
+}
+
+// List only lists ConfigMaps.
+func (c *testClient) List(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	configMaps, ok := list.(*corev1.ConfigMapList)
+	if !ok {
+		return fmt.Errorf("unsupported list %T", list)
+	}
+	options := (&client.ListOptions{}).ApplyOptions(opts)
+	for _, obj := range c.objects {
+		configMap, ok := obj.(*corev1.ConfigMap)
+		if ok && (options.LabelSelector == nil || options.LabelSelector.Matches(labels.Set(configMap.Labels))) {
+			configMaps.Items = append(configMaps.Items, *configMap.DeepCopy())
+		}
+	}
+	return nil
+}
+
+func (c *testClient) Delete(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+	c.deleted = append(c.deleted, obj.GetName())
+	delete(c.objects, obj.GetName())
+	return nil
+}
+
+func (c *testClient) Status() client.StatusWriter {
+	return &testStatusWriter{c: c}
+}
+
+type testStatusWriter struct {
+	client.StatusWriter
+	c *testClient
+}
+
+func (w *testStatusWriter) Update(context.Context, client.Object, ...client.UpdateOption) error {
+	w.c.statusUpdates++
+	return nil
+}
+
+func newTestReconcilerBase(c client.Client) util.ReconcilerBase {
+	scheme := runtime.NewScheme()
+	_ = netconfv1.AddToScheme(scheme)
+	return util.NewReconcilerBase(c, scheme, nil, record.NewFakeRecorder(10), nil)
+}
+
+func TestExecutionHash(t *testing.T) {
+	ttl := int32(60)
+	spec := netconfv1.LockSpec{MountPoint: "device", Target: "candidate"}
+	hash := func(spec netconfv1.LockSpec, runID string) string {
+		t.Helper()
+		h, err := executionHash(spec, runID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	base := hash(spec, "")
+
+	tests := []struct {
+		name    string
+		spec    netconfv1.LockSpec
+		runID   string
+		changed bool
+	}{
+		{name: "same spec", spec: spec},
+		{name: "spec changed", spec: netconfv1.LockSpec{MountPoint: "device", Target: "running"}, changed: true},
+		{
+			name: "spec.runID changed",
+			spec: netconfv1.LockSpec{
+				MountPoint: "device", Target: "candidate", ExecutionPolicy: netconfv1.ExecutionPolicy{RunID: "1"},
+			},
+			changed: true,
+		},
+		{name: "run-id annotation changed", spec: spec, runID: "1", changed: true},
+		{
+			name: "TTL changed",
+			spec: netconfv1.LockSpec{
+				MountPoint: "device", Target: "candidate",
+				ExecutionPolicy: netconfv1.ExecutionPolicy{TTLSecondsAfterFinished: &ttl},
+			},
+		},
+		{
+			name: "retry policy changed",
+			spec: netconfv1.LockSpec{
+				MountPoint: "device", Target: "candidate", RetryPolicy: &netconfv1.RetryPolicy{MaxAttempts: 5},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if changed := hash(tt.spec, tt.runID) != base; changed != tt.changed {
+				t.Errorf("executionHash() changed = %v, want %v", changed, tt.changed)
+			}
+		})
+	}
+}
+
+func TestManageExecution(t *testing.T) {
+	ttl := int32(60)
+	spec := netconfv1.LockSpec{MountPoint: "device", Target: "candidate"}
+	hash, err := executionHash(spec, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ago := func(d time.Duration) *metav1.Time {
+		at := metav1.NewTime(time.Now().Add(-d))
+		return &at
+	}
+
+	tests := []struct {
+		name        string
+		specHash    string
+		completedAt *metav1.Time
+		ttl         *int32
+		runID       string
+		wantExecute bool
+		wantReset   bool
+		wantRequeue bool
+		wantDeleted bool
+	}{
+		{name: "first execution", wantExecute: true},
+		{name: "in progress", specHash: hash, wantExecute: true},
+		{name: "completed", specHash: hash, completedAt: ago(time.Hour)},
+		{name: "completed, TTL pending", specHash: hash, completedAt: ago(time.Second), ttl: &ttl, wantRequeue: true},
+		{name: "completed, TTL expired", specHash: hash, completedAt: ago(time.Hour), ttl: &ttl, wantDeleted: true},
+		{name: "spec changed", specHash: "sha256:0", completedAt: ago(time.Hour), wantExecute: true, wantReset: true},
+		{
+			name:        "run-id changed",
+			specHash:    hash,
+			completedAt: ago(time.Hour),
+			runID:       "1",
+			wantExecute: true,
+			wantReset:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &testClient{}
+			obj := &netconfv1.Lock{
+				ObjectMeta: metav1.ObjectMeta{Name: "lock", Annotations: map[string]string{}},
+				Spec:       spec,
+				RPCStatus: netconfv1.RPCStatus{
+					SpecHash:    tt.specHash,
+					CompletedAt: tt.completedAt,
+					Retry:       &netconfv1.RetryStatus{Attempts: 2},
+				},
+			}
+			obj.Spec.TTLSecondsAfterFinished = tt.ttl
+			if tt.runID != "" {
+				obj.Annotations[runIDAnnotation] = tt.runID
+			}
+			reset := false
+
+			execute, result, err := manageExecution(
+				context.Background(), newTestReconcilerBase(c), obj, obj.Spec, obj.Spec.ExecutionPolicy,
+				&obj.RPCStatus, func() { reset = true }, logr.Discard(),
+			)
+			if err != nil {
+				t.Fatalf("manageExecution() error = %v", err)
+			}
+			if execute != tt.wantExecute {
+				t.Errorf("manageExecution() execute = %v, want %v", execute, tt.wantExecute)
+			}
+			if reset != tt.wantReset {
+				t.Errorf("manageExecution() reset = %v, want %v", reset, tt.wantReset)
+			}
+			if requeue := result.RequeueAfter > 0; requeue != tt.wantRequeue {
+				t.Errorf("manageExecution() requeued after %s, want requeue %v", result.RequeueAfter, tt.wantRequeue)
+			}
+			if deleted := len(c.deleted) != 0; deleted != tt.wantDeleted {
+				t.Errorf("manageExecution() deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			if tt.wantExecute && obj.CompletedAt != nil {
+				t.Errorf("manageExecution() kept completedAt %s, executing again", obj.CompletedAt)
+			}
+		})
+	}
+}
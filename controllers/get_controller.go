@@ -46,6 +46,7 @@ import (
 // GetReconciler reconciles a Get object
 type GetReconciler struct {
 	util.ReconcilerBase
+	sessions SessionProvider
 }
 
 // AddGet Add creates a new MountPoint Controller and adds it to the Manager.
@@ -74,8 +75,17 @@ func (r *GetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		return r.ManageError(ctx, instance, err)
 	}
 
+	// The operation is executed once per spec, unless executed again through its run-id
+	execute, result, err := manageExecution(
+		ctx, r.ReconcilerBase, instance, instance.Spec, instance.Spec.ExecutionPolicy, &instance.RPCStatus, nil, log,
+	)
+	if !execute {
+		return result, err
+	}
+
 	// Managing CR validation
 	if ok, err := r.isValid(instance); !ok {
+		setFailed(instance, err, netconfv1.AppliedCondition)
 		return r.ManageErrorWithRequeue(ctx, instance, err, 2*time.Second)
 	}
 
@@ -109,12 +119,17 @@ func (r *GetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		return reconcile.Result{}, nil
 	}
 
+	// Wait for the retry scheduled following a transient failure, if any
+	if wait := startAttempt(instance.Spec.RetryPolicy, &instance.RPCStatus, instance.Generation); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
 	err = r.manageOperatorLogic(instance, log)
 	if err != nil {
-		return r.ManageError(ctx, instance, err)
+		return manageRetry(ctx, r.ReconcilerBase, instance, instance.Spec.RetryPolicy, &instance.RPCStatus, err)
 	}
 
-	return r.ManageSuccess(ctx, instance)
+	return manageCompletion(ctx, r.ReconcilerBase, instance, instance.Spec.ExecutionPolicy, &instance.RPCStatus)
 }
 
 func (r *GetReconciler) isInitialized(obj metav1.Object) bool {
@@ -133,11 +148,11 @@ func (r *GetReconciler) isValid(obj metav1.Object) (bool, error) {
 	}
 
 	exists := CheckMountPointExists(
-		r.ReconcilerBase,
+		r.ReconcilerBase, r.sessions,
 		types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.MountPoint},
 	)
 	if !exists {
-		return false, fmt.Errorf("MountPoint %s doesn't exists", instance.Spec.MountPoint)
+		return false, mountPointUnavailable(instance.Spec.MountPoint)
 	}
 
 	return true, nil
@@ -147,19 +162,26 @@ func (r *GetReconciler) manageOperatorLogic(get *netconfv1.Get, log logr.Logger)
 	log.Info(fmt.Sprintf("%s: Get with filter: %s.", get.Spec.MountPoint, get.Spec.FilterType))
 
 	m := message.NewGet(get.Spec.FilterType, get.Spec.FilterXML)
-	s := Sessions[get.GetMountPointNamespacedName(get.Spec.MountPoint)]
+	s, err := r.sessions.Acquire(get.GetMountPointNamespacedName(get.Spec.MountPoint))
+	if err != nil {
+		err = mountPointUnavailable(get.Spec.MountPoint)
+		setFailed(get, err, netconfv1.AppliedCondition)
+		return err
+	}
+	defer r.sessions.Release(s)
 
 	reply, err := s.SyncRPC(m, get.Spec.Timeout)
-	if err != nil || reply.Errors != nil {
+	err = checkReply(reply, err)
+	if err != nil {
 		log.Info(fmt.Sprintf("%s: Failed to Get %s.", get.Spec.MountPoint, get.Name))
-		get.Status = "failed"
-		get.RpcReply = reply.RawReply
+		setReplyStatus(&get.RPCStatus, reply, err)
+		setFailed(get, err, netconfv1.AppliedCondition)
 		return err
 	}
 
 	log.Info(fmt.Sprintf("%s: Successfully executed get operation %s.", get.Spec.MountPoint, get.Name))
-	get.Status = "success"
-	get.RpcReply = reply.Data
+	setReplyStatus(&get.RPCStatus, reply, nil)
+	setSucceeded(get, netconfv1.AppliedCondition)
 
 	return nil
 }
@@ -170,6 +192,7 @@ func newGetReconciler(mgr manager.Manager) reconcile.Reconciler {
 			mgr.GetClient(), mgr.GetScheme(), mgr.GetConfig(), mgr.GetEventRecorderFor(getControllerName),
 			mgr.GetAPIReader(),
 		),
+		sessions: sessions,
 	}
 }
 
@@ -182,7 +205,7 @@ func addGet(mgr manager.Manager, r reconcile.Reconciler) error {
 
 	err = c.Watch(
 		&source.Kind{Type: &netconfv1.Get{}}, &handler.EnqueueRequestForObject{},
-		util.ResourceGenerationOrFinalizerChangedPredicate{},
+		executionPredicate,
 	)
 	if err != nil {
 		return err
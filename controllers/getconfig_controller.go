@@ -47,6 +47,7 @@ import (
 // GetConfigReconciler reconciles a GetConfig object
 type GetConfigReconciler struct {
 	util.ReconcilerBase
+	sessions SessionProvider
 }
 
 // AddGetConfig Add creates a new MountPoint Controller and adds it to the Manager.
@@ -75,8 +76,17 @@ func (r *GetConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return r.ManageError(ctx, instance, err)
 	}
 
+	// The operation is executed once per spec, unless executed again through its run-id
+	execute, result, err := manageExecution(
+		ctx, r.ReconcilerBase, instance, instance.Spec, instance.Spec.ExecutionPolicy, &instance.RPCStatus, nil, log,
+	)
+	if !execute {
+		return result, err
+	}
+
 	// Managing CR validation
 	if ok, err := r.isValid(instance); !ok {
+		setFailed(instance, err, netconfv1.AppliedCondition)
 		return r.ManageErrorWithRequeue(ctx, instance, err, 2*time.Second)
 	}
 
@@ -95,12 +105,17 @@ func (r *GetConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return reconcile.Result{}, nil
 	}
 
+	// Wait for the retry scheduled following a transient failure, if any
+	if wait := startAttempt(instance.Spec.RetryPolicy, &instance.RPCStatus, instance.Generation); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
 	err = r.manageOperatorLogic(instance, log)
 	if err != nil {
-		return r.ManageError(ctx, instance, err)
+		return manageRetry(ctx, r.ReconcilerBase, instance, instance.Spec.RetryPolicy, &instance.RPCStatus, err)
 	}
 
-	return r.ManageSuccess(ctx, instance)
+	return manageCompletion(ctx, r.ReconcilerBase, instance, instance.Spec.ExecutionPolicy, &instance.RPCStatus)
 }
 
 func (r *GetConfigReconciler) isInitialized(obj metav1.Object) bool {
@@ -119,11 +134,11 @@ func (r *GetConfigReconciler) isValid(obj metav1.Object) (bool, error) {
 	}
 
 	exists := CheckMountPointExists(
-		r.ReconcilerBase,
+		r.ReconcilerBase, r.sessions,
 		types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.MountPoint},
 	)
 	if !exists {
-		return false, fmt.Errorf("MountPoint %s doesn't exists", instance.Spec.MountPoint)
+		return false, mountPointUnavailable(instance.Spec.MountPoint)
 	}
 
 	return true, nil
@@ -132,24 +147,54 @@ func (r *GetConfigReconciler) isValid(obj metav1.Object) (bool, error) {
 func (r *GetConfigReconciler) manageOperatorLogic(obj *netconfv1.GetConfig, log logr.Logger) error {
 	log.Info(fmt.Sprintf("%s: Send GetConfig %s for %s datastore.", obj.Spec.MountPoint, obj.Name, obj.Spec.Target))
 
-	// TODO implement filtering
-	s := Sessions[obj.GetMountPointNamespacedName(obj.Spec.MountPoint)]
-	reply, err := s.SyncRPC(message.NewGetConfig(obj.Spec.Target, "", ""), obj.Spec.Timeout)
+	ns := namespacesFor(r.sessions, obj.GetMountPointNamespacedName(obj.Spec.MountPoint), obj.Spec.YANGMapping)
+	filterType, filter := "", obj.Spec.Filter
+	if obj.Spec.FilterJSON != nil {
+		if filter != "" {
+			err := fmt.Errorf("only one of filter and filterJSON can be set")
+			setFailed(obj, err, netconfv1.AppliedCondition)
+			return err
+		}
+		var err error
+		filter, err = jsonToXML(obj.Spec.FilterJSON, ns)
+		if err != nil {
+			setFailed(obj, err, netconfv1.AppliedCondition)
+			return err
+		}
+	}
+	if filter != "" {
+		filterType = message.FilterTypeSubtree
+	}
+
+	s, err := r.sessions.Acquire(obj.GetMountPointNamespacedName(obj.Spec.MountPoint))
+	if err != nil {
+		err = mountPointUnavailable(obj.Spec.MountPoint)
+		setFailed(obj, err, netconfv1.AppliedCondition)
+		return err
+	}
+	defer r.sessions.Release(s)
+	reply, err := s.SyncRPC(message.NewGetConfig(obj.Spec.Target, filterType, filter), obj.Spec.Timeout)
 
-	if err != nil || reply.Errors != nil {
+	err = checkReply(reply, err)
+	if err != nil {
 		log.Info(
 			fmt.Sprintf(
 				"%s: Failed to GetConfig for %s datastore %s.", obj.Spec.MountPoint, obj.Spec.Target, obj.Name,
 			),
 		)
-		obj.Status = "failed"
-		obj.RpcReply = reply.RawReply
+		setReplyStatus(&obj.RPCStatus, reply, err)
+		setFailed(obj, err, netconfv1.AppliedCondition)
 		return err
 	}
 
 	log.Info(fmt.Sprintf("%s: Successfully executed GetConfig operation %s.", obj.Spec.MountPoint, obj.Name))
-	obj.Status = "success"
-	obj.RpcReply = reply.Data
+	setReplyStatus(&obj.RPCStatus, reply, nil)
+	err = setReplyJSON(&obj.RPCStatus, obj.Spec.ReplyFormat, reply, ns)
+	if err != nil {
+		setFailed(obj, err, netconfv1.AppliedCondition)
+		return err
+	}
+	setSucceeded(obj, netconfv1.AppliedCondition)
 	return nil
 }
 
@@ -159,6 +204,7 @@ func newGetConfigReconciler(mgr manager.Manager) reconcile.Reconciler {
 			mgr.GetClient(), mgr.GetScheme(), mgr.GetConfig(), mgr.GetEventRecorderFor(getConfigControllerName),
 			mgr.GetAPIReader(),
 		),
+		sessions: sessions,
 	}
 }
 
@@ -171,7 +217,7 @@ func addGetConfig(mgr manager.Manager, r reconcile.Reconciler) error {
 
 	err = c.Watch(
 		&source.Kind{Type: &netconfv1.GetConfig{}}, &handler.EnqueueRequestForObject{},
-		util.ResourceGenerationOrFinalizerChangedPredicate{},
+		executionPredicate,
 	)
 	if err != nil {
 		return err
@@ -0,0 +1,302 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"net"
+	"strings"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+	"github.com/redhat-cop/operator-utils/pkg/util"
+)
+
+// knownHostsKey is the key holding the known_hosts content within the ConfigMap
+// referenced by HostKeyPolicy.KnownHostsConfigMapRef
+const knownHostsKey = "known_hosts"
+
+// hostKeyMismatchError is returned when the host key presented by the NETCONF server
+// doesn't match the one expected by the MountPoint host key policy.
+type hostKeyMismatchError struct {
+	reason  string
+	message string
+}
+
+func (e *hostKeyMismatchError) Error() string {
+	return e.message
+}
+
+// hostKeyVerifier verifies the host key presented by the NETCONF server, according to the
+// MountPoint host key policy. The ssh package doesn't wrap the callback error, hence the
+// verifier keeps track of the presented key, and of the mismatch, if any.
+type hostKeyVerifier struct {
+	mode         netconfv1.HostKeyPolicyMode
+	target       string
+	knownHosts   []byte
+	fingerprints []string
+	trustedKey   string
+
+	// The host key presented by the server
+	key ssh.PublicKey
+	// Set when the presented host key doesn't match the policy
+	mismatch *hostKeyMismatchError
+}
+
+// newHostKeyVerifier loads the material required by the host key policy of the MountPoint.
+func newHostKeyVerifier(r util.ReconcilerBase, obj *netconfv1.MountPoint) (*hostKeyVerifier, error) {
+	v := &hostKeyVerifier{mode: netconfv1.InsecureHostKeyPolicy, target: withDefaultPort(obj.Spec.Target)}
+	policy := obj.Spec.HostKeyPolicy
+	if policy == nil {
+		return v, nil
+	}
+	v.mode = policy.Mode
+
+	switch v.mode {
+	case netconfv1.StrictHostKeyPolicy:
+		if policy.KnownHostsConfigMapRef == nil {
+			return nil, fmt.Errorf("host key policy %s requires a knownHostsConfigMapRef", v.mode)
+		}
+		configMap := &corev1.ConfigMap{}
+		namespacedName := types.NamespacedName{Namespace: obj.Namespace, Name: policy.KnownHostsConfigMapRef.Name}
+		err := r.GetClient().Get(context.Background(), namespacedName, configMap)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read known_hosts ConfigMap %s: %w", namespacedName, err)
+		}
+		knownHosts, ok := configMap.Data[knownHostsKey]
+		if !ok {
+			return nil, fmt.Errorf("ConfigMap %s has no %s key", namespacedName, knownHostsKey)
+		}
+		v.knownHosts = []byte(knownHosts)
+	case netconfv1.PinnedHostKeyPolicy:
+		if len(policy.Fingerprints) == 0 {
+			return nil, fmt.Errorf("host key policy %s requires at least one fingerprint", v.mode)
+		}
+		v.fingerprints = policy.Fingerprints
+	case netconfv1.TrustOnFirstUseHostKeyPolicy:
+		v.trustedKey = obj.HostKey
+	}
+	return v, nil
+}
+
+// callback implements ssh.HostKeyCallback
+func (v *hostKeyVerifier) callback(_ string, remote net.Addr, key ssh.PublicKey) error {
+	v.key = key
+
+	var err *hostKeyMismatchError
+	switch v.mode {
+	case netconfv1.StrictHostKeyPolicy:
+		err = v.verifyKnownHosts(remote, key)
+	case netconfv1.PinnedHostKeyPolicy:
+		err = v.verifyFingerprint(key)
+	case netconfv1.TrustOnFirstUseHostKeyPolicy:
+		err = v.verifyTrustedKey(key)
+	}
+	if err != nil {
+		v.mismatch = err
+		return err
+	}
+	return nil
+}
+
+func (v *hostKeyVerifier) verifyFingerprint(key ssh.PublicKey) *hostKeyMismatchError {
+	fingerprint := normalizeFingerprint(ssh.FingerprintSHA256(key))
+	for _, pinned := range v.fingerprints {
+		if normalizeFingerprint(pinned) == fingerprint {
+			return nil
+		}
+	}
+	return &hostKeyMismatchError{
+		reason: netconfv1.HostKeyChangedReason,
+		message: fmt.Sprintf(
+			"host key %s presented by %s doesn't match any of the pinned fingerprints",
+			ssh.FingerprintSHA256(key), v.target,
+		),
+	}
+}
+
+func (v *hostKeyVerifier) verifyTrustedKey(key ssh.PublicKey) *hostKeyMismatchError {
+	if v.trustedKey == "" {
+		// First use, the key is recorded in the status once connected.
+		return nil
+	}
+	trusted, _, _, _, err := ssh.ParseAuthorizedKey([]byte(v.trustedKey))
+	if err == nil && bytes.Equal(trusted.Marshal(), key.Marshal()) {
+		return nil
+	}
+	return &hostKeyMismatchError{
+		reason: netconfv1.HostKeyChangedReason,
+		message: fmt.Sprintf(
+			"host key %s presented by %s doesn't match the key trusted on first use",
+			ssh.FingerprintSHA256(key), v.target,
+		),
+	}
+}
+
+func (v *hostKeyVerifier) verifyKnownHosts(remote net.Addr, key ssh.PublicKey) *hostKeyMismatchError {
+	hosts := []string{knownHostsAddress(v.target)}
+	if remote != nil && remote.String() != v.target {
+		hosts = append(hosts, knownHostsAddress(remote.String()))
+	}
+
+	var authorities []ssh.PublicKey
+	var known, matched bool
+	rest := v.knownHosts
+	for len(rest) > 0 {
+		marker, patterns, pub, _, next, err := ssh.ParseKnownHosts(rest)
+		if err != nil {
+			// Either the end of the content, or an invalid line, which ssh.ParseKnownHosts can't skip.
+			break
+		}
+		rest = next
+		if !knownHostsMatch(patterns, hosts) {
+			continue
+		}
+		// The markers are returned without their leading @
+		switch marker {
+		case "revoked":
+			if bytes.Equal(pub.Marshal(), key.Marshal()) {
+				return &hostKeyMismatchError{
+					reason:  netconfv1.HostKeyRevokedReason,
+					message: fmt.Sprintf("host key %s presented by %s is revoked", ssh.FingerprintSHA256(key), v.target),
+				}
+			}
+		case "cert-authority":
+			authorities = append(authorities, pub)
+		default:
+			known = true
+			matched = matched || bytes.Equal(pub.Marshal(), key.Marshal())
+		}
+	}
+	if matched {
+		return nil
+	}
+
+	if _, ok := key.(*ssh.Certificate); ok && len(authorities) != 0 {
+		checker := &ssh.CertChecker{
+			IsHostAuthority: func(auth ssh.PublicKey, _ string) bool {
+				for _, authority := range authorities {
+					if bytes.Equal(authority.Marshal(), auth.Marshal()) {
+						return true
+					}
+				}
+				return false
+			},
+		}
+		err := checker.CheckHostKey(v.target, remote, key)
+		if err == nil {
+			return nil
+		}
+		return &hostKeyMismatchError{
+			reason:  netconfv1.HostKeyChangedReason,
+			message: fmt.Sprintf("host certificate presented by %s is invalid: %s", v.target, err),
+		}
+	}
+
+	if known {
+		return &hostKeyMismatchError{
+			reason: netconfv1.HostKeyChangedReason,
+			message: fmt.Sprintf(
+				"host key %s presented by %s doesn't match known_hosts", ssh.FingerprintSHA256(key), v.target,
+			),
+		}
+	}
+	return &hostKeyMismatchError{
+		reason:  netconfv1.HostKeyUnknownReason,
+		message: fmt.Sprintf("host %s is not in known_hosts", v.target),
+	}
+}
+
+// withDefaultPort appends the NETCONF over SSH default port when none is specified.
+func withDefaultPort(target string) string {
+	if _, _, err := net.SplitHostPort(target); err != nil {
+		return net.JoinHostPort(target, "830")
+	}
+	return target
+}
+
+// knownHostsAddress formats an address the way it is written in known_hosts, i.e. `host`
+// when using port 22, `[host]:port` otherwise.
+func knownHostsAddress(address string) string {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	if port == "22" {
+		return host
+	}
+	return "[" + host + "]:" + port
+}
+
+// knownHostsMatch reports whether any of the hosts matches the known_hosts patterns.
+// A matching negated pattern prevails over any other pattern.
+func knownHostsMatch(patterns []string, hosts []string) bool {
+	matched := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(pattern, "!")
+		for _, host := range hosts {
+			if !hostPatternMatch(pattern, host) {
+				continue
+			}
+			if negated {
+				return false
+			}
+			matched = true
+		}
+	}
+	return matched
+}
+
+// hostPatternMatch matches a host against a single known_hosts pattern, either hashed
+// (`|1|salt|hash`), or holding `*` and `?` wildcards.
+func hostPatternMatch(pattern string, host string) bool {
+	if strings.HasPrefix(pattern, "|1|") {
+		parts := strings.Split(pattern[len("|1|"):], "|")
+		if len(parts) != 2 {
+			return false
+		}
+		salt, err := base64.StdEncoding.DecodeString(parts[0])
+		if err != nil {
+			return false
+		}
+		hash, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return false
+		}
+		mac := hmac.New(sha1.New, salt)
+		mac.Write([]byte(host))
+		return hmac.Equal(mac.Sum(nil), hash)
+	}
+	return wildcardMatch(strings.ToLower(pattern), strings.ToLower(host))
+}
+
+func wildcardMatch(pattern string, s string) bool {
+	if pattern == "" {
+		return s == ""
+	}
+	switch pattern[0] {
+	case '*':
+		for i := 0; i <= len(s); i++ {
+			if wildcardMatch(pattern[1:], s[i:]) {
+				return true
+			}
+		}
+		return false
+	case '?':
+		return s != "" && wildcardMatch(pattern[1:], s[1:])
+	default:
+		return s != "" && pattern[0] == s[0] && wildcardMatch(pattern[1:], s[1:])
+	}
+}
+
+// normalizeFingerprint allows fingerprints to be provided with or without the `SHA256:`
+// prefix and the base64 padding.
+func normalizeFingerprint(fingerprint string) string {
+	return strings.TrimRight(strings.TrimPrefix(strings.TrimSpace(fingerprint), "SHA256:"), "=")
+}
@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"golang.org/x/crypto/ssh"
+	"net"
+	"strings"
+	"testing"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+func newTestSigner(t *testing.T) ssh.Signer {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+func hashedHost(host string) string {
+	salt := []byte("0123456789abcdefghij")
+	mac := hmac.New(sha1.New, salt)
+	mac.Write([]byte(host))
+	return "|1|" + base64.StdEncoding.EncodeToString(salt) + "|" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestKnownHostsMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		hosts    []string
+		want     bool
+	}{
+		{"plain", []string{"router1"}, []string{"router1"}, true},
+		{"case insensitive", []string{"Router1"}, []string{"router1"}, true},
+		{"other host", []string{"router2"}, []string{"router1"}, false},
+		{"port", []string{"[router1]:830"}, []string{"[router1]:830"}, true},
+		{"other port", []string{"[router1]:830"}, []string{"router1"}, false},
+		{"star wildcard", []string{"*.lab"}, []string{"pe1.lab"}, true},
+		{"question mark wildcard", []string{"pe?.lab"}, []string{"pe12.lab"}, false},
+		{"negated", []string{"*.lab", "!pe1.lab"}, []string{"pe1.lab"}, false},
+		{"negated other host", []string{"*.lab", "!pe1.lab"}, []string{"pe2.lab"}, true},
+		{"any of the hosts", []string{"10.0.0.1"}, []string{"router1", "10.0.0.1"}, true},
+		{"hashed", []string{hashedHost("[router1]:830")}, []string{"[router1]:830"}, true},
+		{"hashed other host", []string{hashedHost("[router1]:830")}, []string{"[router2]:830"}, false},
+		{"malformed hash", []string{"|1|notbase64|"}, []string{"router1"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := knownHostsMatch(tt.patterns, tt.hosts); got != tt.want {
+				t.Errorf("knownHostsMatch(%v, %v) = %v, want %v", tt.patterns, tt.hosts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKnownHostsAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{withDefaultPort("router1"), "[router1]:830"},
+		{withDefaultPort("router1:22"), "router1"},
+		{withDefaultPort("10.0.0.1:2022"), "[10.0.0.1]:2022"},
+		{withDefaultPort("::1"), "[::1]:830"},
+	}
+	for _, tt := range tests {
+		if got := knownHostsAddress(tt.address); got != tt.want {
+			t.Errorf("knownHostsAddress(%q) = %q, want %q", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyKnownHosts(t *testing.T) {
+	hostKey := newTestSigner(t)
+	otherKey := newTestSigner(t)
+	authority := newTestSigner(t)
+
+	cert := &ssh.Certificate{
+		Key:             hostKey.PublicKey(),
+		CertType:        ssh.HostCert,
+		ValidPrincipals: []string{"router1"},
+		ValidBefore:     ssh.CertTimeInfinity,
+	}
+	if err := cert.SignCert(rand.Reader, authority); err != nil {
+		t.Fatal(err)
+	}
+
+	line := func(marker string, pattern string, key ssh.PublicKey) string {
+		return strings.TrimSpace(strings.TrimSpace(marker+" "+pattern) + " " + string(ssh.MarshalAuthorizedKey(key)))
+	}
+	tests := []struct {
+		name       string
+		knownHosts []string
+		key        ssh.PublicKey
+		wantReason string
+	}{
+		{
+			name:       "known",
+			knownHosts: []string{line("", "[router1]:830", hostKey.PublicKey())},
+			key:        hostKey.PublicKey(),
+		},
+		{
+			name:       "hashed",
+			knownHosts: []string{line("", hashedHost("[router1]:830"), hostKey.PublicKey())},
+			key:        hostKey.PublicKey(),
+		},
+		{
+			name:       "changed",
+			knownHosts: []string{line("", "[router1]:830", otherKey.PublicKey())},
+			key:        hostKey.PublicKey(),
+			wantReason: netconfv1.HostKeyChangedReason,
+		},
+		{
+			name:       "unknown",
+			knownHosts: []string{line("", "[router2]:830", hostKey.PublicKey())},
+			key:        hostKey.PublicKey(),
+			wantReason: netconfv1.HostKeyUnknownReason,
+		},
+		{
+			name: "revoked",
+			knownHosts: []string{
+				line("", "[router1]:830", hostKey.PublicKey()),
+				line("@revoked", "*", hostKey.PublicKey()),
+			},
+			key:        hostKey.PublicKey(),
+			wantReason: netconfv1.HostKeyRevokedReason,
+		},
+		{
+			name:       "cert-authority",
+			knownHosts: []string{line("@cert-authority", "*", authority.PublicKey())},
+			key:        cert,
+		},
+		{
+			name:       "other cert-authority",
+			knownHosts: []string{line("@cert-authority", "*", otherKey.PublicKey())},
+			key:        cert,
+			wantReason: netconfv1.HostKeyChangedReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &hostKeyVerifier{
+				mode:       netconfv1.StrictHostKeyPolicy,
+				target:     withDefaultPort("router1"),
+				knownHosts: []byte(strings.Join(tt.knownHosts, "\n") + "\n"),
+			}
+			remote := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 830}
+			err := v.callback("router1:830", remote, tt.key)
+			switch {
+			case tt.wantReason == "" && err != nil:
+				t.Errorf("unexpected mismatch: %s", err)
+			case tt.wantReason != "" && v.mismatch == nil:
+				t.Errorf("expected a %s mismatch", tt.wantReason)
+			case tt.wantReason != "" && v.mismatch.reason != tt.wantReason:
+				t.Errorf("mismatch reason = %s, want %s", v.mismatch.reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestVerifyFingerprint(t *testing.T) {
+	key := newTestSigner(t).PublicKey()
+	fingerprint := ssh.FingerprintSHA256(key)
+	tests := []struct {
+		name   string
+		pinned []string
+		ok     bool
+	}{
+		{"prefixed", []string{fingerprint}, true},
+		{"unprefixed", []string{strings.TrimPrefix(fingerprint, "SHA256:")}, true},
+		{"padded", []string{fingerprint + "="}, true},
+		{"other", []string{ssh.FingerprintSHA256(newTestSigner(t).PublicKey())}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &hostKeyVerifier{mode: netconfv1.PinnedHostKeyPolicy, target: "router1:830", fingerprints: tt.pinned}
+			if err := v.verifyFingerprint(key); (err == nil) != tt.ok {
+				t.Errorf("verifyFingerprint() = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
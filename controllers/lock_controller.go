@@ -45,6 +45,7 @@ import (
 // LockReconciler reconciles a Lock object
 type LockReconciler struct {
 	util.ReconcilerBase
+	sessions SessionProvider
 }
 
 // AddLock Add creates a new MountPoint Controller and adds it to the Manager.
@@ -73,8 +74,17 @@ func (r *LockReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		return r.ManageError(ctx, instance, err)
 	}
 
+	// The operation is executed once per spec, unless executed again through its run-id
+	execute, result, err := manageExecution(
+		ctx, r.ReconcilerBase, instance, instance.Spec, instance.Spec.ExecutionPolicy, &instance.RPCStatus, nil, log,
+	)
+	if !execute {
+		return result, err
+	}
+
 	// Managing CR validation
 	if ok, err := r.isValid(instance); !ok {
+		setFailed(instance, err, netconfv1.AppliedCondition)
 		return r.ManageErrorWithRequeue(ctx, instance, err, 2*time.Second)
 	}
 
@@ -93,12 +103,17 @@ func (r *LockReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		return reconcile.Result{}, nil
 	}
 
+	// Wait for the retry scheduled following a transient failure, if any
+	if wait := startAttempt(instance.Spec.RetryPolicy, &instance.RPCStatus, instance.Generation); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
 	err = r.manageOperatorLogic(instance, log)
 	if err != nil {
-		return r.ManageError(ctx, instance, err)
+		return manageRetry(ctx, r.ReconcilerBase, instance, instance.Spec.RetryPolicy, &instance.RPCStatus, err)
 	}
 
-	return r.ManageSuccess(ctx, instance)
+	return manageCompletion(ctx, r.ReconcilerBase, instance, instance.Spec.ExecutionPolicy, &instance.RPCStatus)
 }
 
 func (r *LockReconciler) isInitialized(obj metav1.Object) bool {
@@ -117,11 +132,11 @@ func (r *LockReconciler) isValid(obj metav1.Object) (bool, error) {
 	}
 
 	exists := CheckMountPointExists(
-		r.ReconcilerBase,
+		r.ReconcilerBase, r.sessions,
 		types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.MountPoint},
 	)
 	if !exists {
-		return false, fmt.Errorf("MountPoint %s doesn't exists", instance.Spec.MountPoint)
+		return false, mountPointUnavailable(instance.Spec.MountPoint)
 	}
 
 	return true, nil
@@ -130,18 +145,25 @@ func (r *LockReconciler) isValid(obj metav1.Object) (bool, error) {
 func (r *LockReconciler) manageOperatorLogic(lock *netconfv1.Lock, log logr.Logger) error {
 	log.Info(fmt.Sprintf("%s: Send Lock %s on %s datastore.", lock.Spec.MountPoint, lock.Name, lock.Spec.Target))
 
-	s := Sessions[lock.GetMountPointNamespacedName(lock.Spec.MountPoint)]
+	s, err := r.sessions.Acquire(lock.GetMountPointNamespacedName(lock.Spec.MountPoint))
+	if err != nil {
+		err = mountPointUnavailable(lock.Spec.MountPoint)
+		setFailed(lock, err, netconfv1.AppliedCondition)
+		return err
+	}
+	defer r.sessions.Release(s)
 	reply, err := s.SyncRPC(message.NewLock(lock.Spec.Target), lock.Spec.Timeout)
 
-	if err != nil || reply.Errors != nil {
+	err = checkReply(reply, err)
+	if err != nil {
 		log.Info(fmt.Sprintf("%s: Failed to Lock %s.", lock.Spec.MountPoint, lock.Name))
-		lock.Status = "failed"
-		lock.RpcReply = reply.RawReply
+		setReplyStatus(&lock.RPCStatus, reply, err)
+		setFailed(lock, err, netconfv1.AppliedCondition)
 		return err
 	}
 	log.Info(fmt.Sprintf("%s: Successfully executed lock %s operation.", lock.Spec.MountPoint, lock.Name))
-	lock.Status = "success"
-	lock.RpcReply = reply.Data
+	setReplyStatus(&lock.RPCStatus, reply, nil)
+	setSucceeded(lock, netconfv1.AppliedCondition)
 	return nil
 }
 
@@ -151,6 +173,7 @@ func newLockReconciler(mgr manager.Manager) reconcile.Reconciler {
 			mgr.GetClient(), mgr.GetScheme(), mgr.GetConfig(), mgr.GetEventRecorderFor(lockControllerName),
 			mgr.GetAPIReader(),
 		),
+		sessions: sessions,
 	}
 }
 
@@ -163,7 +186,7 @@ func addLock(mgr manager.Manager, r reconcile.Reconciler) error {
 
 	err = c.Watch(
 		&source.Kind{Type: &netconfv1.Lock{}}, &handler.EnqueueRequestForObject{},
-		util.ResourceGenerationOrFinalizerChangedPredicate{},
+		executionPredicate,
 	)
 	if err != nil {
 		return err
@@ -18,20 +18,28 @@ package controllers
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"github.com/go-logr/logr"
 	"github.com/openshift-telco/go-netconf-client/netconf"
 	"github.com/openshift-telco/go-netconf-client/netconf/message"
 	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	"strings"
 	"time"
 
 	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
@@ -41,10 +49,20 @@ import (
 //+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=mountpoints,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=mountpoints/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=mountpoints/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// secretRefsField indexes MountPoints by the name of the Secrets holding their credentials
+const secretRefsField = ".spec.secretRefs"
+
+// configMapRefsField indexes MountPoints by the name of the ConfigMaps they refer to, e.g. known_hosts
+const configMapRefsField = ".spec.configMapRefs"
 
 // MountPointReconciler reconciles a MountPoint object
 type MountPointReconciler struct {
 	util.ReconcilerBase
+	sessions *sessionManager
 }
 
 // AddMountPoint Add creates a new MountPoint Controller and adds it to the Manager.
@@ -75,6 +93,7 @@ func (r *MountPointReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	// Managing CR validation
 	if ok, err := r.isValid(instance); !ok {
+		setFailed(instance, err, netconfv1.ConnectedCondition)
 		return r.ManageError(ctx, instance, err)
 	}
 
@@ -131,53 +150,168 @@ func (r *MountPointReconciler) isInitialized(obj metav1.Object) bool {
 }
 
 func (r *MountPointReconciler) isValid(obj metav1.Object) (bool, error) {
-	_, ok := obj.(*netconfv1.MountPoint)
+	instance, ok := obj.(*netconfv1.MountPoint)
 	if !ok {
 		return false, fmt.Errorf("%s is not an MountPoint object", obj.GetName())
 	}
+	if instance.Spec.Transport == netconfv1.TLSTransport {
+		if instance.Spec.TLS == nil {
+			return false, fmt.Errorf("MountPoint %s uses the tls transport, but has no tls configuration", instance.Name)
+		}
+	} else if instance.Spec.CredentialsSecretRef == nil && instance.Spec.Username == "" {
+		return false, fmt.Errorf("MountPoint %s has no credentialsSecretRef", instance.Name)
+	}
+	if instance.Spec.CallHome == nil && instance.Spec.Target == "" {
+		return false, fmt.Errorf("MountPoint %s has no target", instance.Name)
+	}
 
 	return true, nil
 }
 
 func (r *MountPointReconciler) manageCleanUpLogic(mountPoint *netconfv1.MountPoint) error {
-	s := Sessions[mountPoint.GetNamespacedName()]
-	if s != nil {
-		// Close NETCONF session. If fails, kill the session.
-		rpc, err := s.SyncRPC(message.NewCloseSession(), mountPoint.Spec.Timeout)
-		if err != nil || rpc.Errors != nil {
-			// If there is a failure here, there is nothing we can do.
-			_, _ = s.SyncRPC(message.NewKillSession(string(rune(s.SessionID))), mountPoint.Spec.Timeout)
-		}
-
-		// remove cached session from inventory
-		delete(Sessions, mountPoint.GetNamespacedName())
+	stopSupervisor(mountPoint.GetNamespacedName())
 
-		// blindly remove stream handler
-		s.Listener.Remove(message.NetconfNotificationStreamHandler)
+	// remove cached session from inventory, and close it
+	return r.sessions.Remove(mountPoint.GetNamespacedName())
+}
 
-		return s.Close()
+// closeSession gracefully closes the NETCONF session. If this fails, the session is killed.
+func closeSession(s *netconf.Session, timeout int32) error {
+	rpc, err := s.SyncRPC(message.NewCloseSession(), timeout)
+	if err != nil || rpc.Errors != nil {
+		// If there is a failure here, there is nothing we can do.
+		_, _ = s.SyncRPC(message.NewKillSession(string(rune(s.SessionID))), timeout)
 	}
-	return nil
+
+	// blindly remove stream handler
+	s.Listener.Remove(message.NetconfNotificationStreamHandler)
+
+	return s.Close()
 }
 
 func (r *MountPointReconciler) manageOperatorLogic(obj *netconfv1.MountPoint, log logr.Logger) error {
+	if obj.Spec.CallHome != nil {
+		return r.awaitCallHome(obj, log)
+	}
+
 	log.Info(fmt.Sprintf("%s: Create Netconf connection to %s.", obj.Name, obj.Spec.Target))
 
-	sshConfig := &ssh.ClientConfig{
-		User:            obj.Spec.Username,
-		Auth:            []ssh.AuthMethod{ssh.Password(obj.Spec.Username)},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	session, transport, err := r.connect(obj, log)
+	if err != nil {
+		if !r.sessions.Healthy(obj.GetNamespacedName()) {
+			setNotConnected(obj, netconfv1.Disconnected, err.Error())
+		}
+		return err
 	}
 
+	// Replace the previous session, if any, e.g. when re-dialing upon credentials change.
+	r.bind(obj, session, transport)
+
+	setConnected(obj)
+	log.Info(fmt.Sprintf("%s: Successfully connected.", obj.Name))
+
+	// Subscriptions don't survive their session.
+	go resyncSubscriptions(r.GetClient(), obj.DeepCopy())
+
+	return nil
+}
+
+// connect establishes a NETCONF session with the server defined by the MountPoint. The outcome of the
+// credentials loading and of the server authentication is reported in the MountPoint status.
+func (r *MountPointReconciler) connect(
+	obj *netconfv1.MountPoint, log logr.Logger,
+) (*netconf.Session, *supervisedTransport, error) {
 	var session *netconf.Session
 	var err error
+	if obj.Spec.Transport == netconfv1.TLSTransport {
+		session, err = r.dialTLS(obj, log)
+	} else {
+		session, err = r.dialSSH(obj, log)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transport, err := r.hello(obj, session, log)
+	if err != nil {
+		return nil, nil, err
+	}
+	return session, transport, nil
+}
+
+// hello exchanges the hello-messages over the newly established session, and starts watching over its transport.
+func (r *MountPointReconciler) hello(
+	obj *netconfv1.MountPoint, session *netconf.Session, log logr.Logger,
+) (*supervisedTransport, error) {
+	// Watch over the transport, to detect the loss of the session
+	transport := newSupervisedTransport(session.Transport)
+	session.Transport = transport
+
+	// Send our hello using default capabilities + additional capabilities, as defined in the CR.
+	capabilities := netconf.DefaultCapabilities
+	for _, capability := range obj.Spec.AdditionalCapabilities {
+		capabilities = append(capabilities, capability)
+	}
+
+	err := session.SendHello(&message.Hello{Capabilities: capabilities})
+	if err != nil {
+		log.Error(err, fmt.Sprintf("%s: Failed to send hello-message", obj.Name))
+		_ = session.Close()
+		return nil, err
+	}
+
+	obj.Capabilities = session.Capabilities
+
+	return transport, nil
+}
+
+// bind makes the session the one of the MountPoint, replacing the previous one, if any, and supervises it.
+func (r *MountPointReconciler) bind(obj *netconfv1.MountPoint, session *netconf.Session, transport *supervisedTransport) {
+	stopSupervisor(obj.GetNamespacedName())
+	_ = r.sessions.Put(obj.GetNamespacedName(), session, transport, obj.Spec.Timeout)
+	startSupervisor(r, obj, transport)
+}
+
+// dialSSH establishes the SSH connection, verifying the host key presented by the NETCONF server.
+func (r *MountPointReconciler) dialSSH(obj *netconfv1.MountPoint, log logr.Logger) (*netconf.Session, error) {
+	var auth []ssh.AuthMethod
+	creds, err := getCredentials(r.ReconcilerBase, obj)
+	if err == nil {
+		auth, err = sshAuthMethods(creds, obj.Spec.AuthMethods)
+	}
+	setCredentialsCondition(obj, err)
+	if err != nil {
+		log.Error(err, fmt.Sprintf("%s: Failed to load credentials.", obj.Name))
+		return nil, err
+	}
+
+	verifier, err := newHostKeyVerifier(r.ReconcilerBase, obj)
+	if err != nil {
+		log.Error(err, fmt.Sprintf("%s: Failed to load host key policy.", obj.Name))
+		return nil, err
+	}
+
+	sshConfig := &ssh.ClientConfig{
+		User:            creds.username,
+		Auth:            auth,
+		HostKeyCallback: verifier.callback,
+	}
+
+	var session *netconf.Session
 
-	// Establish SSH session
+	// Establish SSH session, on the address the host key is verified for
+	address := withDefaultPort(obj.Spec.Target)
 	if obj.Spec.Timeout == 0 {
-		session, err = netconf.DialSSH(obj.Spec.Target, sshConfig)
+		session, err = netconf.DialSSH(address, sshConfig)
 	} else {
 		timeout := time.Duration(obj.Spec.Timeout) * time.Second
-		session, err = netconf.DialSSHTimeout(obj.Spec.Target, sshConfig, timeout)
+		session, err = netconf.DialSSHTimeout(address, sshConfig, timeout)
+	}
+	if verifier.mismatch != nil {
+		log.Info(fmt.Sprintf("%s: Refusing to connect to %s: %s", obj.Name, obj.Spec.Target, verifier.mismatch))
+		r.GetRecorder().Event(obj, "Warning", netconfv1.HostKeyMismatchCondition, verifier.mismatch.Error())
+		setHostKeyCondition(obj, verifier.mismatch)
+		return nil, verifier.mismatch
 	}
 	if err != nil {
 		log.Error(
@@ -186,30 +320,101 @@ func (r *MountPointReconciler) manageOperatorLogic(obj *netconfv1.MountPoint, lo
 				obj.Name, obj.Spec.Target,
 			),
 		)
-		obj.Status = "failed"
-		return err
+		return nil, err
 	}
 
-	// Send our hello using default capabilities + additional capabilities, as defined in the CR.
-	capabilities := netconf.DefaultCapabilities
-	for _, capability := range obj.Spec.AdditionalCapabilities {
-		capabilities = append(capabilities, capability)
+	obj.HostKey = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(verifier.key)))
+	obj.HostKeyFingerprint = ssh.FingerprintSHA256(verifier.key)
+	obj.TLS = nil
+	setHostKeyCondition(obj, nil)
+
+	return session, nil
+}
+
+// dialTLS establishes the TLS connection, using mutual X.509 authentication, as per RFC 7589.
+func (r *MountPointReconciler) dialTLS(obj *netconfv1.MountPoint, log logr.Logger) (*netconf.Session, error) {
+	config, cert, err := newTLSConfig(r.ReconcilerBase, obj)
+	setCredentialsCondition(obj, err)
+	if err != nil {
+		log.Error(err, fmt.Sprintf("%s: Failed to load TLS configuration.", obj.Name))
+		return nil, err
 	}
 
-	err = session.SendHello(&message.Hello{Capabilities: capabilities})
+	timeout := time.Duration(obj.Spec.Timeout) * time.Second
+	conn, err := dialTLS(withDefaultTLSPort(obj.Spec.Target), config, timeout)
 	if err != nil {
-		log.Error(err, fmt.Sprintf("%s: Failed to send hello-message", obj.Name))
-		obj.Status = "failed"
-		return err
+		log.Error(err, fmt.Sprintf("%s: Failed to established TLS connection to %s.", obj.Name, obj.Spec.Target))
+		return nil, err
 	}
+	setTLSStatus(obj, conn, cert)
 
-	obj.Status = "connected"
-	obj.Capabilities = session.Capabilities
+	return netconf.NewSession(newConnTransport(conn, timeout)), nil
+}
 
-	Sessions[obj.GetNamespacedName()] = session
-	log.Info(fmt.Sprintf("%s: Successfully connected.", obj.Name))
+// setTLSStatus reports the TLS connection in the MountPoint status.
+func setTLSStatus(obj *netconfv1.MountPoint, conn *tls.Conn, cert *tls.Certificate) {
+	obj.TLS = tlsStatus(conn.ConnectionState(), cert, obj.Spec.TLS.CertToName)
+	obj.HostKey = ""
+	obj.HostKeyFingerprint = ""
+	meta.RemoveStatusCondition(&obj.Conditions, netconfv1.HostKeyMismatchCondition)
+}
 
-	return nil
+// setCredentialsCondition reports whether the credentials could be loaded. Errors unrelated
+// to the credentials content, e.g. failure to reach the API server, leave the condition untouched.
+func setCredentialsCondition(obj *netconfv1.MountPoint, err error) {
+	condition := metav1.Condition{
+		Type:               netconfv1.CredentialsAvailableCondition,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: obj.GetGeneration(),
+		Reason:             netconfv1.CredentialsLoadedReason,
+	}
+	if err != nil {
+		var credsErr *credentialsError
+		if !errors.As(err, &credsErr) {
+			return
+		}
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = credsErr.reason
+		condition.Message = credsErr.message
+	}
+	meta.SetStatusCondition(&obj.Conditions, condition)
+}
+
+// setHostKeyCondition reports whether the host key presented by the NETCONF server matches the host key policy.
+func setHostKeyCondition(obj *netconfv1.MountPoint, mismatch *hostKeyMismatchError) {
+	condition := metav1.Condition{
+		Type:               netconfv1.HostKeyMismatchCondition,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: obj.GetGeneration(),
+		Reason:             netconfv1.HostKeyVerifiedReason,
+	}
+	if mismatch != nil {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = mismatch.reason
+		condition.Message = mismatch.message
+	}
+	meta.SetStatusCondition(&obj.Conditions, condition)
+}
+
+// mountPointsReferencing maps an object to the MountPoints referring to it, using the provided field index.
+func mountPointsReferencing(c client.Client, field string) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		mountPoints := &netconfv1.MountPointList{}
+		err := c.List(
+			context.Background(), mountPoints, client.InNamespace(obj.GetNamespace()),
+			client.MatchingFields{field: obj.GetName()},
+		)
+		if err != nil {
+			return nil
+		}
+		requests := make([]reconcile.Request, len(mountPoints.Items))
+		for i, mountPoint := range mountPoints.Items {
+			requests[i] = reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: mountPoint.Namespace, Name: mountPoint.Name},
+			}
+		}
+		return requests
+	}
 }
 
 func newMountPointReconciler(mgr manager.Manager) reconcile.Reconciler {
@@ -218,6 +423,7 @@ func newMountPointReconciler(mgr manager.Manager) reconcile.Reconciler {
 			mgr.GetClient(), mgr.GetScheme(), mgr.GetConfig(), mgr.GetEventRecorderFor(mountPointControllerName),
 			mgr.GetAPIReader(),
 		),
+		sessions: sessions,
 	}
 }
 
@@ -236,5 +442,64 @@ func addMountPoint(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Re-dial whenever a Secret holding the credentials changes
+	err = mgr.GetFieldIndexer().IndexField(
+		context.Background(), &netconfv1.MountPoint{}, secretRefsField,
+		func(obj client.Object) []string {
+			mountPoint := obj.(*netconfv1.MountPoint)
+			var names []string
+			if mountPoint.Spec.CredentialsSecretRef != nil {
+				names = append(names, mountPoint.Spec.CredentialsSecretRef.Name)
+			}
+			if mountPoint.Spec.PrivateKeySecretRef != nil {
+				names = append(names, mountPoint.Spec.PrivateKeySecretRef.Name)
+			}
+			if mountPoint.Spec.TLS != nil {
+				names = append(names, mountPoint.Spec.TLS.ClientCertificateSecretRef.Name)
+				if mountPoint.Spec.TLS.CABundleSecretRef != nil {
+					names = append(names, mountPoint.Spec.TLS.CABundleSecretRef.Name)
+				}
+			}
+			return names
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(
+		&source.Kind{Type: &corev1.Secret{}},
+		handler.EnqueueRequestsFromMapFunc(mountPointsReferencing(mgr.GetClient(), secretRefsField)),
+		predicate.ResourceVersionChangedPredicate{},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Re-dial whenever the known_hosts ConfigMap changes
+	err = mgr.GetFieldIndexer().IndexField(
+		context.Background(), &netconfv1.MountPoint{}, configMapRefsField,
+		func(obj client.Object) []string {
+			mountPoint := obj.(*netconfv1.MountPoint)
+			policy := mountPoint.Spec.HostKeyPolicy
+			if policy == nil || policy.KnownHostsConfigMapRef == nil {
+				return nil
+			}
+			return []string{policy.KnownHostsConfigMapRef.Name}
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(
+		&source.Kind{Type: &corev1.ConfigMap{}},
+		handler.EnqueueRequestsFromMapFunc(mountPointsReferencing(mgr.GetClient(), configMapRefsField)),
+		predicate.ResourceVersionChangedPredicate{},
+	)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
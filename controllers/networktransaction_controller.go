@@ -0,0 +1,211 @@
+/*
+Copyright 2021. Alexis de Talhouët
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"time"
+
+	"github.com/redhat-cop/operator-utils/pkg/util"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=networktransactions,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=networktransactions/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=networktransactions/finalizers,verbs=update
+
+// NetworkTransactionReconciler reconciles a NetworkTransaction object
+type NetworkTransactionReconciler struct {
+	util.ReconcilerBase
+	sessions SessionProvider
+}
+
+// AddNetworkTransaction creates a new NetworkTransaction Controller and adds it to the Manager.
+func AddNetworkTransaction(mgr manager.Manager) error {
+	return addNetworkTransaction(mgr, newNetworkTransactionReconciler(mgr))
+}
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *NetworkTransactionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var log = logf.Log.WithName(networkTransactionControllerName)
+
+	reqLogger := log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+	reqLogger.Info("Reconciling NetworkTransaction")
+
+	// Fetch the CRD instance
+	instance := &netconfv1.NetworkTransaction{}
+	err := r.GetClient().Get(context.Background(), req.NamespacedName, instance)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("NetworkTransaction resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		log.Error(err, "Failed to get NetworkTransaction")
+		return r.ManageError(ctx, instance, err)
+	}
+
+	// The operation is executed once per spec, unless executed again through its run-id
+	execute, result, err := manageExecution(
+		ctx, r.ReconcilerBase, instance, instance.Spec, instance.Spec.ExecutionPolicy, &instance.RPCStatus,
+		func() { instance.ObservedGeneration = 0 }, log,
+	)
+	if !execute {
+		return result, err
+	}
+
+	// The transaction is executed once per generation, whatever its outcome, unless retried. Failed transactions
+	// which completed before their executions were recorded aren't executed again either.
+	if instance.ObservedGeneration == instance.Generation && transactionCompleted(instance.Phase) &&
+		!retryScheduled(&instance.RPCStatus, instance.Generation) {
+		return reconcile.Result{}, nil
+	}
+
+	// Managing CR validation
+	if ok, err := r.isValid(instance); !ok {
+		setFailed(instance, err, netconfv1.CommittedCondition)
+		return r.ManageErrorWithRequeue(ctx, instance, err, 2*time.Second)
+	}
+
+	// Managing CR Finalization
+	if util.IsBeingDeleted(instance) {
+		return reconcile.Result{}, nil
+	}
+
+	// Wait for the retry scheduled following a transient failure, if any
+	if wait := startAttempt(instance.Spec.RetryPolicy, &instance.RPCStatus, instance.Generation); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
+	err = r.manageOperatorLogic(instance, log)
+	if transactionCompleted(instance.Phase) {
+		// Completed, whatever its outcome, unless retried
+		completeExecution(&instance.RPCStatus)
+	}
+	if err != nil {
+		return manageRetry(ctx, r.ReconcilerBase, instance, instance.Spec.RetryPolicy, &instance.RPCStatus, err)
+	}
+
+	return manageCompletion(ctx, r.ReconcilerBase, instance, instance.Spec.ExecutionPolicy, &instance.RPCStatus)
+}
+
+// isValid checks each MountPoint is listed once. Their sessions are checked once the transaction is started.
+func (r *NetworkTransactionReconciler) isValid(obj metav1.Object) (bool, error) {
+	instance, ok := obj.(*netconfv1.NetworkTransaction)
+	if !ok {
+		return false, fmt.Errorf("%s is not a NetworkTransaction object", obj.GetName())
+	}
+
+	mountPoints := make(map[string]bool, len(instance.Spec.Devices))
+	for _, device := range instance.Spec.Devices {
+		if mountPoints[device.MountPoint] {
+			return false, fmt.Errorf("MountPoint %s is listed more than once", device.MountPoint)
+		}
+		mountPoints[device.MountPoint] = true
+	}
+
+	return true, nil
+}
+
+// manageOperatorLogic starts the transaction once per generation, then confirms its commits, until they are all
+// confirmed, or the confirm timeout expires. Until started, e.g. while the dependency isn't Ready, the transaction
+// is Pending, and retried.
+func (r *NetworkTransactionReconciler) manageOperatorLogic(obj *netconfv1.NetworkTransaction, log logr.Logger) error {
+	if obj.ObservedGeneration == obj.Generation && obj.Phase == netconfv1.ConfirmingTransactionPhase &&
+		len(obj.Devices) == len(obj.Spec.Devices) {
+		return r.manageConfirmation(obj, log)
+	}
+
+	obj.ObservedGeneration = obj.Generation
+	obj.Phase = netconfv1.PendingTransactionPhase
+	obj.PersistID = ""
+	obj.ConfirmDeadline = nil
+	obj.CompletionTime = nil
+	obj.Devices = make([]netconfv1.TransactionDeviceStatus, len(obj.Spec.Devices))
+	for i, device := range obj.Spec.Devices {
+		obj.Devices[i] = netconfv1.TransactionDeviceStatus{
+			MountPoint: device.MountPoint, Phase: netconfv1.PendingDevicePhase,
+		}
+	}
+
+	ok, err := checkDependencies(r.ReconcilerBase, obj, obj.Spec.DependsOn, log, netconfv1.CommittedCondition)
+	if !ok {
+		return err
+	}
+
+	devices, release, err := r.acquire(obj)
+	if err != nil {
+		setFailed(obj, err, netconfv1.CommittedCondition)
+		return err
+	}
+	defer release()
+
+	log.Info(fmt.Sprintf("Start NetworkTransaction %s on %d device(s).", obj.Name, len(devices)))
+	return r.transact(obj, devices, log)
+}
+
+func newNetworkTransactionReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &NetworkTransactionReconciler{
+		ReconcilerBase: util.NewReconcilerBase(
+			mgr.GetClient(), mgr.GetScheme(), mgr.GetConfig(),
+			mgr.GetEventRecorderFor(networkTransactionControllerName), mgr.GetAPIReader(),
+		),
+		sessions: sessions,
+	}
+}
+
+func addNetworkTransaction(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(networkTransactionControllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(
+		&source.Kind{Type: &netconfv1.NetworkTransaction{}}, &handler.EnqueueRequestForObject{},
+		executionPredicate,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch over the dependencies, to start the NetworkTransaction once they are Ready
+	err = watchDependencies(
+		mgr, c, &netconfv1.NetworkTransaction{}, &netconfv1.NetworkTransactionList{},
+		func(obj client.Object) netconfv1.Dependencies {
+			return obj.(*netconfv1.NetworkTransaction).Spec.DependsOn
+		},
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
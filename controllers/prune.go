@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	"github.com/openshift-telco/go-netconf-client/netconf/message"
+	"github.com/redhat-cop/operator-utils/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;delete
+
+// Keys of the ConfigMap holding the snapshot of an EditConfig
+const (
+	// snapshotKey holds the configuration, as it was before the EditConfig introduced or modified it.
+	snapshotKey = "snapshot.xml"
+	// introducedKey holds the removal of the elements the EditConfig introduced.
+	introducedKey = "introduced.xml"
+)
+
+// pruneSnapshotName returns the name of the ConfigMap holding the snapshot of the EditConfig: the one already
+// recorded, if any, or one derived from its UID, so that it can't be created before the EditConfig.
+func pruneSnapshotName(obj *netconfv1.EditConfig) string {
+	if obj.PruneSnapshot != "" {
+		return obj.PruneSnapshot
+	}
+	return fmt.Sprintf("editconfig-%s-snapshot", obj.UID)
+}
+
+// snapshot records the configuration covered by the XML payload, before it is applied, so it can be pruned when
+// the EditConfig is deleted. The snapshot is taken upon the first application; for the next generations, only
+// the elements not covered yet are added, so the snapshot keeps the configuration as it was before the EditConfig.
+func (r *EditConfigReconciler) snapshot(obj *netconfv1.EditConfig, s Session, payload string) error {
+	desired, err := parseXML(payload)
+	if err != nil {
+		return fmt.Errorf("invalid XML payload: %w", err)
+	}
+
+	actual, err := getConfigNodes(s, driftSource(obj), desired, obj.Spec.Timeout)
+	if err != nil {
+		return err
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: obj.Namespace, Name: pruneSnapshotName(obj)},
+	}
+	_, err = controllerutil.CreateOrUpdate(
+		context.Background(), r.GetClient(), configMap, func() error {
+			if !configMap.CreationTimestamp.IsZero() && !metav1.IsControlledBy(configMap, obj) {
+				return fmt.Errorf("the ConfigMap isn't owned by EditConfig %s", obj.Name)
+			}
+			snapshot, err := parseXML(configMap.Data[snapshotKey])
+			if err != nil {
+				return err
+			}
+			introduced, err := parseXML(configMap.Data[introducedKey])
+			if err != nil {
+				return err
+			}
+			if configMap.Data == nil {
+				configMap.Data = make(map[string]string)
+			}
+			ns := r.namespaces(obj)
+			configMap.Data[snapshotKey] = renderXML(mergeNodes(snapshot, actual, ns))
+			configMap.Data[introducedKey] = renderXML(mergeNodes(introduced, introducedNodes(desired, actual, ns), ns))
+			// Garbage collected along with the EditConfig, once released by the prune
+			util.AddFinalizer(configMap, editConfigFinalizer)
+			return controllerutil.SetControllerReference(obj, configMap, r.GetScheme())
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record the snapshot in ConfigMap %s: %w", configMap.Name, err)
+	}
+	obj.PruneSnapshot = configMap.Name
+	return nil
+}
+
+// manageCleanUpLogic prunes the configuration, according to the prune policy: the elements the EditConfig
+// introduced are removed and, with the `restore` policy, the elements it modified are first restored from
+// the snapshot. The lock, commit and unlock are performed as requested by the EditConfig. The snapshot is then
+// released, as its finalizer keeps it from the garbage collector until the prune is over.
+func (r *EditConfigReconciler) manageCleanUpLogic(obj *netconfv1.EditConfig, log logr.Logger) error {
+	configMap := &corev1.ConfigMap{}
+	err := r.GetClient().Get(
+		context.Background(), types.NamespacedName{Namespace: obj.Namespace, Name: pruneSnapshotName(obj)}, configMap,
+	)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// Never applied
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(configMap, obj) {
+		log.Info(fmt.Sprintf("ConfigMap %s isn't owned by EditConfig %s, which isn't pruned.", configMap.Name, obj.Name))
+		r.GetRecorder().Event(
+			obj, "Warning", "PruneSkipped", fmt.Sprintf("ConfigMap %s isn't owned by the EditConfig", configMap.Name),
+		)
+		return nil
+	}
+
+	err = r.prune(obj, configMap, log)
+	if err != nil {
+		return err
+	}
+	return r.releaseSnapshot(configMap)
+}
+
+// prune applies the snapshot of the EditConfig, if its prune policy is still set.
+func (r *EditConfigReconciler) prune(obj *netconfv1.EditConfig, configMap *corev1.ConfigMap, log logr.Logger) error {
+	if obj.Spec.PrunePolicy == "" {
+		return nil
+	}
+
+	err := r.GetClient().Get(
+		context.Background(), types.NamespacedName{Namespace: obj.Namespace, Name: obj.Spec.MountPoint},
+		&netconfv1.MountPoint{},
+	)
+	if apierrors.IsNotFound(err) {
+		log.Info(fmt.Sprintf("%s: MountPoint is gone, EditConfig %s isn't pruned.", obj.Spec.MountPoint, obj.Name))
+		r.GetRecorder().Event(
+			obj, "Warning", "PruneSkipped", fmt.Sprintf("MountPoint %s doesn't exists", obj.Spec.MountPoint),
+		)
+		return nil
+	}
+
+	var edits []configEdit
+	if obj.Spec.PrunePolicy == netconfv1.RestorePrunePolicy && configMap.Data[snapshotKey] != "" {
+		edits = append(
+			edits, configEdit{operation: message.DefaultOperationTypeMerge, xml: configMap.Data[snapshotKey]},
+		)
+	}
+	if configMap.Data[introducedKey] != "" {
+		// Only the elements carrying the remove operation are affected
+		edits = append(
+			edits, configEdit{operation: message.DefaultOperationTypeNone, xml: configMap.Data[introducedKey]},
+		)
+	}
+	if len(edits) == 0 {
+		return nil
+	}
+
+	s, err := r.sessions.Acquire(obj.GetMountPointNamespacedName(obj.Spec.MountPoint))
+	if err != nil {
+		return mountPointUnavailable(obj.Spec.MountPoint)
+	}
+	defer r.sessions.Release(s)
+
+	log.Info(fmt.Sprintf("%s: Prune EditConfig %s.", obj.Spec.MountPoint, obj.Name))
+	err = r.apply(obj, s, log, edits...)
+	if err != nil {
+		return err
+	}
+	r.GetRecorder().Event(
+		obj, "Normal", "Pruned", fmt.Sprintf("Configuration pruned, using the %s policy", obj.Spec.PrunePolicy),
+	)
+	return nil
+}
+
+// releaseSnapshot removes the finalizer of the snapshot, and deletes it.
+func (r *EditConfigReconciler) releaseSnapshot(configMap *corev1.ConfigMap) error {
+	if util.HasFinalizer(configMap, editConfigFinalizer) {
+		util.RemoveFinalizer(configMap, editConfigFinalizer)
+		err := r.GetClient().Update(context.Background(), configMap)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to release the snapshot in ConfigMap %s: %w", configMap.Name, err)
+		}
+	}
+	err := r.GetClient().Delete(context.Background(), configMap)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete the snapshot in ConfigMap %s: %w", configMap.Name, err)
+	}
+	return nil
+}
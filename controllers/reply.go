@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/openshift-telco/go-netconf-client/netconf/message"
+	"strings"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// timeoutMessage is the error returned by the NETCONF client when no rpc-reply is received in time.
+const timeoutMessage = "timeout while executing request"
+
+// rpcReplyError is returned when the NETCONF server replied with at least one rpc-error of severity error.
+type rpcReplyError struct {
+	errors []message.RPCError
+}
+
+func (e *rpcReplyError) Error() string {
+	messages := make([]string, len(e.errors))
+	for i := range e.errors {
+		messages[i] = fmt.Sprintf("%s: %s", e.errors[i].Tag, strings.TrimSpace(e.errors[i].Message))
+	}
+	return "rpc-error " + strings.Join(messages, ", ")
+}
+
+// rpcTransportError is returned when the RPC couldn't be sent, or its reply received, e.g. upon a timeout.
+type rpcTransportError struct {
+	category netconfv1.ErrorCategory
+	err      error
+}
+
+func (e *rpcTransportError) Error() string {
+	return e.err.Error()
+}
+
+func (e *rpcTransportError) Unwrap() error {
+	return e.err
+}
+
+// checkReply returns the error of the RPC, either the one returned while sending it, e.g. a timeout,
+// or the rpc-errors the NETCONF server replied with. Warnings aren't considered as errors.
+func checkReply(reply *message.RPCReply, err error) error {
+	if err != nil {
+		category := netconfv1.TransportErrorCategory
+		if err.Error() == timeoutMessage {
+			category = netconfv1.TimeoutErrorCategory
+		}
+		return &rpcTransportError{category: category, err: err}
+	}
+	if reply == nil {
+		return &rpcTransportError{
+			category: netconfv1.TransportErrorCategory, err: errors.New("no rpc-reply received"),
+		}
+	}
+	var rpcErrors []message.RPCError
+	for _, rpcError := range reply.Errors {
+		if rpcError.Severity != "warning" {
+			rpcErrors = append(rpcErrors, rpcError)
+		}
+	}
+	if len(rpcErrors) != 0 {
+		return &rpcReplyError{errors: rpcErrors}
+	}
+	return nil
+}
+
+// setReplyStatus reports the outcome of the RPC, as returned by checkReply, in the status: the data of the
+// reply upon success, otherwise the raw reply, if any, along with the category of the failure. The rpc-errors,
+// warnings included, are always reported.
+func setReplyStatus(status *netconfv1.RPCStatus, reply *message.RPCReply, err error) {
+	status.ErrorCategory = errorCategory(err)
+	status.RPCErrors = nil
+	if reply == nil {
+		return
+	}
+
+	if err != nil {
+		status.RpcReply = reply.RawReply
+	} else {
+		status.RpcReply = reply.Data
+	}
+	for i := range reply.Errors {
+		status.RPCErrors = append(status.RPCErrors, rpcError(reply.Errors[i]))
+	}
+}
+
+func errorCategory(err error) netconfv1.ErrorCategory {
+	var replyErr *rpcReplyError
+	if errors.As(err, &replyErr) {
+		return netconfv1.RPCErrorCategory
+	}
+	var transportErr *rpcTransportError
+	if errors.As(err, &transportErr) {
+		return transportErr.category
+	}
+	return ""
+}
+
+// rpcError converts the rpc-error decoded by the NETCONF client. As the client exposes the whole content of the
+// rpc-error as its info, the error-app-tag and the error-info are decoded out of it.
+func rpcError(e message.RPCError) netconfv1.RPCError {
+	rpcError := netconfv1.RPCError{
+		Type:     strings.TrimSpace(e.Type),
+		Tag:      strings.TrimSpace(e.Tag),
+		Severity: strings.TrimSpace(e.Severity),
+		Path:     strings.TrimSpace(e.Path),
+		Message:  strings.TrimSpace(e.Message),
+	}
+
+	var content struct {
+		AppTag string `xml:"error-app-tag"`
+		Info   struct {
+			Content string `xml:",innerxml"`
+		} `xml:"error-info"`
+	}
+	if err := xml.Unmarshal([]byte("<rpc-error>"+e.Info+"</rpc-error>"), &content); err == nil {
+		rpcError.AppTag = strings.TrimSpace(content.AppTag)
+		rpcError.Info = strings.TrimSpace(content.Info.Content)
+	}
+	return rpcError
+}
@@ -0,0 +1,341 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"github.com/go-logr/logr"
+	"github.com/openshift-telco/go-netconf-client/netconf/message"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"path"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"strings"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// gitShowScript prints the commit the revision resolves to, on the first line, followed by the file at this
+// revision. The errors are written to the termination message of the container, rather than mixed with the output.
+const gitShowScript = `set -e
+exec 2>/dev/termination-log
+cd "$REPOSITORY"
+git config --global --add safe.directory "$REPOSITORY" || true
+git rev-parse --verify "$REVISION^{commit}"
+git show "$REVISION:$FILE"
+`
+
+func restoreConfirmation(obj *netconfv1.ConfigRestore) *confirmation {
+	return &confirmation{
+		obj:        obj,
+		mountPoint: obj.Spec.MountPoint,
+		timeout:    obj.Spec.Timeout,
+		spec:       obj.Spec.Confirmed,
+		status:     &obj.CommitStatus,
+	}
+}
+
+// restoreSource resolves the configuration to restore, which can't be empty. For a Git revision, the file is read
+// by a Job: until it completes, the source is pending.
+func (r *ConfigRestoreReconciler) restoreSource(obj *netconfv1.ConfigRestore) (string, bool, error) {
+	config, pending, err := r.readSource(obj)
+	if err != nil || pending {
+		return "", pending, err
+	}
+	if strings.TrimSpace(config) == "" {
+		return "", false, fmt.Errorf("the configuration to restore is empty")
+	}
+	return config, false, nil
+}
+
+func (r *ConfigRestoreReconciler) readSource(obj *netconfv1.ConfigRestore) (string, bool, error) {
+	source := obj.Spec.Source
+	if (source.ConfigMapKeyRef == nil) == (source.Git == nil) {
+		return "", false, fmt.Errorf("exactly one of configMapKeyRef and git must be set")
+	}
+	if source.ConfigMapKeyRef != nil {
+		config, err := configMapKey(r.ReconcilerBase, obj.Namespace, source.ConfigMapKeyRef)
+		if err != nil {
+			return "", false, err
+		}
+		return config, false, r.checkPartialBackup(obj)
+	}
+
+	name := sourceJobName(obj)
+	obj.SourceJob = name
+	job := &batchv1.Job{}
+	err := r.GetClient().Get(context.Background(), types.NamespacedName{Namespace: obj.Namespace, Name: name}, job)
+	if apierrors.IsNotFound(err) {
+		return "", true, r.createGitShowJob(obj, name)
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobFailed:
+			return "", false, fmt.Errorf(
+				"Job %s failed to read revision %s of %s: %s", name, source.Git.Revision, source.Git.File,
+				condition.Message,
+			)
+		case batchv1.JobComplete:
+			config, err := r.gitShowOutput(obj, name)
+			return config, false, err
+		}
+	}
+	return "", true, nil
+}
+
+// checkPartialBackup refuses to replace the whole datastore with a ConfigBackup version taken using a filter, as
+// the configuration outside of the filter would be deleted. Such a version is restored using the merge method.
+func (r *ConfigRestoreReconciler) checkPartialBackup(obj *netconfv1.ConfigRestore) error {
+	ref := obj.Spec.Source.ConfigMapKeyRef
+	configMap := &corev1.ConfigMap{}
+	namespacedName := types.NamespacedName{Namespace: obj.Namespace, Name: ref.Name}
+	err := r.GetClient().Get(context.Background(), namespacedName, configMap)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration ConfigMap %s: %w", namespacedName, err)
+	}
+	filter, ok := configMap.Annotations[filterAnnotation]
+	if !ok || obj.Spec.Method == netconfv1.MergeRestoreMethod {
+		return nil
+	}
+	return fmt.Errorf(
+		"ConfigMap %s only holds the configuration selected by the filter %s: use the %s method, rather than "+
+			"replacing the whole datastore", namespacedName, filter, netconfv1.MergeRestoreMethod,
+	)
+}
+
+// sourceJobName returns the name of the Job reading the Git revision for the current execution of the
+// ConfigRestore, so that the revision is read again once its spec, or its run-id, changes.
+func sourceJobName(obj *netconfv1.ConfigRestore) string {
+	hash := strings.TrimPrefix(obj.SpecHash, "sha256:")
+	if len(hash) > 10 {
+		hash = hash[:10]
+	}
+	return fmt.Sprintf("configrestore-%s-%s", obj.Name, hash)
+}
+
+func (r *ConfigRestoreReconciler) createGitShowJob(obj *netconfv1.ConfigRestore, name string) error {
+	git := obj.Spec.Source.Git
+	image := git.Image
+	if image == "" {
+		image = defaultGitImage
+	}
+	revision := git.Revision
+	if revision == "" {
+		revision = "HEAD"
+	}
+
+	backoffLimit := int32(2)
+	ttl := int32(3600)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Namespace: obj.Namespace, Name: name},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    "git",
+							Image:   image,
+							Command: []string{"sh", "-c", gitShowScript},
+							Env: []corev1.EnvVar{
+								{Name: "REPOSITORY", Value: path.Join("/repository", git.Path)},
+								{Name: "REVISION", Value: revision},
+								{Name: "FILE", Value: git.File},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "repository", MountPath: "/repository", ReadOnly: true},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "repository",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: git.PersistentVolumeClaimName,
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	err := controllerutil.SetControllerReference(obj, job, r.GetScheme())
+	if err != nil {
+		return err
+	}
+	err = r.GetClient().Create(context.Background(), job)
+	if err != nil {
+		return fmt.Errorf("failed to create Job %s: %w", name, err)
+	}
+	return nil
+}
+
+// gitShowOutput reads the output of the Job reading the Git revision, from the logs of its succeeded pod.
+func (r *ConfigRestoreReconciler) gitShowOutput(obj *netconfv1.ConfigRestore, name string) (string, error) {
+	pods := &corev1.PodList{}
+	err := r.GetClient().List(
+		context.Background(), pods, client.InNamespace(obj.Namespace), client.MatchingLabels{"job-name": name},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to list the pods of Job %s: %w", name, err)
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+		clientset, err := kubernetes.NewForConfig(r.GetRestConfig())
+		if err != nil {
+			return "", err
+		}
+		logs, err := clientset.CoreV1().Pods(obj.Namespace).GetLogs(
+			pod.Name, &corev1.PodLogOptions{Container: "git"},
+		).DoRaw(context.Background())
+		if err != nil {
+			return "", fmt.Errorf("failed to read the logs of pod %s: %w", pod.Name, err)
+		}
+		output := strings.SplitN(string(logs), "\n", 2)
+		if len(output) != 2 {
+			return "", fmt.Errorf("unexpected output of Job %s", name)
+		}
+		obj.Revision = strings.TrimSpace(output[0])
+		return strings.TrimSpace(output[1]), nil
+	}
+	return "", fmt.Errorf("Job %s has no succeeded pod, e.g. it was cleaned up", name)
+}
+
+// restore pushes the configuration to the target datastore, within a lock. The candidate datastore is then
+// committed, using a confirmed commit if requested, or its changes discarded upon failure.
+func (r *ConfigRestoreReconciler) restore(
+	obj *netconfv1.ConfigRestore, s Session, config string, log logr.Logger,
+) error {
+	datastore := obj.Spec.Target
+	if datastore == "" {
+		datastore = message.DatastoreCandidate
+	}
+	if datastore == "startup" && obj.Spec.Method != netconfv1.CopyConfigRestoreMethod {
+		err := fmt.Errorf("the startup datastore can only be restored using copyConfig")
+		setFailed(obj, err, netconfv1.AppliedCondition)
+		return err
+	}
+	target, err := configTarget(
+		netconfv1.ConfigTarget{Datastore: datastore},
+		r.sessions.Capabilities(obj.GetMountPointNamespacedName(obj.Spec.MountPoint)),
+	)
+	if err != nil {
+		setFailed(obj, err, netconfv1.AppliedCondition)
+		return err
+	}
+
+	reply, err := s.SyncRPC(message.NewRPC("<lock><target>"+target+"</target></lock>"), obj.Spec.Timeout)
+	err = checkReply(reply, err)
+	if err != nil {
+		log.Info(
+			fmt.Sprintf(
+				"%s: Failed to lock datastore %s for ConfigRestore %s", obj.Spec.MountPoint, datastore, obj.Name,
+			),
+		)
+		setReplyStatus(&obj.RPCStatus, reply, err)
+		setFailed(obj, err, netconfv1.AppliedCondition)
+		return err
+	}
+	defer func() {
+		reply, err := s.SyncRPC(message.NewRPC("<unlock><target>"+target+"</target></unlock>"), obj.Spec.Timeout)
+		if err = checkReply(reply, err); err != nil {
+			log.Info(
+				fmt.Sprintf(
+					"%s: Failed to unlock datastore %s for ConfigRestore %s: %s", obj.Spec.MountPoint, datastore,
+					obj.Name, err,
+				),
+			)
+		}
+	}()
+
+	operation := message.DefaultOperationTypeReplace
+	if obj.Spec.Method == netconfv1.MergeRestoreMethod {
+		operation = message.DefaultOperationTypeMerge
+	}
+	push := fmt.Sprintf(
+		"<edit-config><target>%s</target><default-operation>%s</default-operation><config>%s</config>"+
+			"</edit-config>", target, operation, config,
+	)
+	if obj.Spec.Method == netconfv1.CopyConfigRestoreMethod {
+		push = fmt.Sprintf(
+			"<copy-config><target>%s</target><source><config>%s</config></source></copy-config>", target, config,
+		)
+	}
+	reply, err = s.SyncRPC(message.NewRPC(push), obj.Spec.Timeout)
+	err = checkReply(reply, err)
+	setReplyStatus(&obj.RPCStatus, reply, err)
+	if err != nil {
+		log.Info(fmt.Sprintf("%s: Failed to push ConfigRestore %s.", obj.Spec.MountPoint, obj.Name))
+		r.discardChanges(obj, s, datastore, log)
+		setFailed(obj, err, netconfv1.AppliedCondition)
+		return err
+	}
+	now := metav1.Now()
+	obj.RestoreTime = &now
+	obj.Hash = fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(config)))
+
+	if datastore != message.DatastoreCandidate {
+		log.Info(fmt.Sprintf("%s: Successfully restored ConfigRestore %s.", obj.Spec.MountPoint, obj.Name))
+		setSucceeded(obj, netconfv1.AppliedCondition)
+		return nil
+	}
+
+	if obj.Spec.Confirmed != nil {
+		err = confirmedCommit(r.sessions, s, restoreConfirmation(obj), log)
+		if err != nil {
+			r.discardChanges(obj, s, datastore, log)
+			setFailed(obj, err, netconfv1.AppliedCondition)
+			return err
+		}
+		setConditions(obj, metav1.ConditionTrue, netconfv1.SucceededReason, "", netconfv1.AppliedCondition)
+		return nil
+	}
+
+	reply, err = s.SyncRPC(message.NewCommit(), obj.Spec.Timeout)
+	err = checkReply(reply, err)
+	setReplyStatus(&obj.RPCStatus, reply, err)
+	if err != nil {
+		log.Info(fmt.Sprintf("%s: Failed to commit ConfigRestore %s.", obj.Spec.MountPoint, obj.Name))
+		r.discardChanges(obj, s, datastore, log)
+		setFailed(obj, err, netconfv1.AppliedCondition, netconfv1.CommittedCondition)
+		return err
+	}
+	log.Info(fmt.Sprintf("%s: Successfully restored ConfigRestore %s.", obj.Spec.MountPoint, obj.Name))
+	setSucceeded(obj, netconfv1.AppliedCondition, netconfv1.CommittedCondition)
+	return nil
+}
+
+// discardChanges reverts the candidate datastore to the running configuration, once the restore failed.
+func (r *ConfigRestoreReconciler) discardChanges(
+	obj *netconfv1.ConfigRestore, s Session, datastore string, log logr.Logger,
+) {
+	if datastore != message.DatastoreCandidate {
+		return
+	}
+	reply, err := s.SyncRPC(message.NewRPC(discardChangesRPC), obj.Spec.Timeout)
+	if err = checkReply(reply, err); err != nil {
+		log.Info(
+			fmt.Sprintf("%s: Failed to discard changes of ConfigRestore %s: %s", obj.Spec.MountPoint, obj.Name, err),
+		)
+	}
+}
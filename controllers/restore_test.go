@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/openshift-telco/go-netconf-client/netconf/message"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+func TestReadSourceConfigMap(t *testing.T) {
+	config := interfaces("<name>eth0</name>")
+	backup := func(name, filter string) *corev1.ConfigMap {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{}},
+			Data:       map[string]string{backupKey: config},
+		}
+		if filter != "" {
+			configMap.Annotations[filterAnnotation] = filter
+		}
+		return configMap
+	}
+	c := &testClient{
+		objects: map[string]client.Object{
+			"whole":    backup("whole", ""),
+			"filtered": backup("filtered", interfaces()),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		configMap string
+		method    netconfv1.RestoreMethod
+		wantErr   bool
+	}{
+		{name: "whole datastore replaced", configMap: "whole"},
+		{name: "whole datastore merged", configMap: "whole", method: netconfv1.MergeRestoreMethod},
+		{name: "filtered version replacing the datastore", configMap: "filtered", wantErr: true},
+		{
+			name:      "filtered version copied",
+			configMap: "filtered",
+			method:    netconfv1.CopyConfigRestoreMethod,
+			wantErr:   true,
+		},
+		{name: "filtered version merged", configMap: "filtered", method: netconfv1.MergeRestoreMethod},
+		{name: "missing ConfigMap", configMap: "missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ConfigRestoreReconciler{ReconcilerBase: newTestReconcilerBase(c)}
+			obj := &netconfv1.ConfigRestore{
+				Spec: netconfv1.ConfigRestoreSpec{
+					Method: tt.method,
+					Source: netconfv1.RestoreSource{
+						ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: tt.configMap},
+							Key:                  backupKey,
+						},
+					},
+				},
+			}
+
+			got, pending, err := r.readSource(obj)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if pending || (err == nil && got != config) {
+				t.Errorf("readSource() = %s, pending %v, want %s", got, pending, config)
+			}
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        netconfv1.RestoreMethod
+		errorTags     map[string][]string
+		wantOperation string
+		want          []string
+		wantErr       bool
+	}{
+		{
+			name:          "replaced",
+			wantOperation: "replace",
+			want:          []string{"lock", "edit-config", "commit", "unlock"},
+		},
+		{
+			name:          "merged",
+			method:        netconfv1.MergeRestoreMethod,
+			wantOperation: "merge",
+			want:          []string{"lock", "edit-config", "commit", "unlock"},
+		},
+		{
+			name:   "copied",
+			method: netconfv1.CopyConfigRestoreMethod,
+			want:   []string{"lock", "copy-config", "commit", "unlock"},
+		},
+		{
+			name:          "push failed",
+			errorTags:     map[string][]string{"edit-config": {"invalid-value"}},
+			wantOperation: "replace",
+			want:          []string{"lock", "edit-config", "discard-changes", "unlock"},
+			wantErr:       true,
+		},
+		{
+			name:          "commit failed",
+			errorTags:     map[string][]string{"commit": {"operation-failed"}},
+			wantOperation: "replace",
+			want:          []string{"lock", "edit-config", "commit", "discard-changes", "unlock"},
+			wantErr:       true,
+		},
+		{
+			name:      "lock denied",
+			errorTags: map[string][]string{"lock": {"lock-denied"}},
+			want:      []string{"lock"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSession(tt.errorTags)
+			r := &ConfigRestoreReconciler{ReconcilerBase: newTestReconcilerBase(&testClient{}), sessions: s}
+			obj := &netconfv1.ConfigRestore{
+				ObjectMeta: metav1.ObjectMeta{Name: "restore"},
+				Spec:       netconfv1.ConfigRestoreSpec{MountPoint: "device", Method: tt.method},
+			}
+
+			err := r.restore(obj, s, interfaces("<name>eth0</name>"), logr.Discard())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("restore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(s.operations, tt.want) {
+				t.Errorf("restore() sent %q, want %q", s.operations, tt.want)
+			}
+			if tt.wantOperation != "" {
+				operation := "<default-operation>" + tt.wantOperation + "</default-operation>"
+				if !strings.Contains(s.rpcs[1], operation) {
+					t.Errorf("restore() sent %s, want %s", s.rpcs[1], operation)
+				}
+			}
+			if s.locked[message.DatastoreCandidate] {
+				t.Errorf("restore() left the candidate datastore locked")
+			}
+		})
+	}
+}
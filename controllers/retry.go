@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/redhat-cop/operator-utils/pkg/util"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"math"
+	"math/rand"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"strings"
+	"time"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// defaultRetryOn are the error-tags retried upon by default: the datastore, or a resource, is held by another
+// session, which is expected to release it.
+var defaultRetryOn = []string{"lock-denied", "in-use", "resource-denied"}
+
+// startAttempt records a new attempt of the operation, unless a retry is scheduled later on, in which case it
+// returns how long to wait for it. The attempts following a transient failure are counted along with the first one,
+// while any other reconciliation, e.g. following an update, starts counting again.
+func startAttempt(policy *netconfv1.RetryPolicy, status *netconfv1.RPCStatus, generation int64) time.Duration {
+	if policy == nil {
+		status.Retry = nil
+		return 0
+	}
+	retry := status.Retry
+	if retry == nil || retry.ObservedGeneration != generation || retry.NextRetryTime == nil {
+		status.Retry = &netconfv1.RetryStatus{ObservedGeneration: generation, Attempts: 1}
+		return 0
+	}
+	if wait := time.Until(retry.NextRetryTime.Time); wait > 0 {
+		return wait
+	}
+	retry.Attempts++
+	retry.NextRetryTime = nil
+	return 0
+}
+
+// retryScheduled reports whether the operation is retried, following a transient failure in its current
+// generation.
+func retryScheduled(status *netconfv1.RPCStatus, generation int64) bool {
+	return status.Retry != nil && status.Retry.ObservedGeneration == generation && status.Retry.NextRetryTime != nil
+}
+
+// manageRetry manages the failure of the operation according to its retry policy. Upon an rpc-error bearing one
+// of the retriable error-tags, the operation is requeued once the backoff elapsed, until the attempts are
+// exhausted; the other rpc-errors aren't retried. The other failures, e.g. the MountPoint being unavailable, and
+// the ones occurring while a commit awaits its confirmation, are retried as if no retry policy was set. Without
+// retry policy, a one-shot operation is still completed upon an rpc-error that isn't retriable by default.
+func manageRetry(
+	ctx context.Context, r util.ReconcilerBase, obj dependentObject, policy *netconfv1.RetryPolicy,
+	status *netconfv1.RPCStatus, err error,
+) (reconcile.Result, error) {
+	var replyErr *rpcReplyError
+	ready := meta.FindStatusCondition(obj.GetConditions(), netconfv1.ReadyCondition)
+	if !errors.As(err, &replyErr) || ready != nil && ready.Reason == netconfv1.ConfirmationPendingReason {
+		return r.ManageError(ctx, obj, err)
+	}
+	if policy == nil || status.Retry == nil {
+		if status.SpecHash == "" || isRetriable(policy, replyErr) {
+			return r.ManageError(ctx, obj, err)
+		}
+		r.GetRecorder().Event(obj, "Warning", conditionReason(err), err.Error())
+		completeExecution(status)
+		return reconcile.Result{Requeue: true}, r.GetClient().Status().Update(ctx, obj)
+	}
+
+	retry := status.Retry
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = 3
+	}
+	switch {
+	case !isRetriable(policy, replyErr):
+		r.GetRecorder().Event(obj, "Warning", conditionReason(err), err.Error())
+	case retry.Attempts >= maxAttempts:
+		message := fmt.Sprintf("No longer retried after %d attempt(s): %s", retry.Attempts, err)
+		r.GetRecorder().Event(obj, "Warning", netconfv1.RetriesExhaustedReason, message)
+		setConditions(
+			obj, metav1.ConditionFalse, netconfv1.RetriesExhaustedReason, message, netconfv1.ReadyCondition,
+		)
+	default:
+		delay := retryBackoff(policy, retry.Attempts)
+		next := metav1.NewTime(time.Now().Add(delay))
+		retry.NextRetryTime = &next
+		// A retried operation isn't completed yet
+		status.CompletedAt = nil
+		message := fmt.Sprintf(
+			"Attempt %d of %d failed, retried at %s: %s", retry.Attempts, maxAttempts,
+			next.UTC().Format(time.RFC3339), err,
+		)
+		r.GetRecorder().Event(obj, "Warning", netconfv1.RetryingReason, message)
+		setConditions(obj, metav1.ConditionFalse, netconfv1.RetryingReason, message, netconfv1.ReadyCondition)
+		return reconcile.Result{RequeueAfter: delay}, r.GetClient().Status().Update(ctx, obj)
+	}
+	// The failure is final for this generation. A one-shot operation is completed, and requeued for its TTL to be
+	// enforced, if any.
+	completeExecution(status)
+	return reconcile.Result{Requeue: status.CompletedAt != nil}, r.GetClient().Status().Update(ctx, obj)
+}
+
+// isRetriable reports whether every rpc-error bears one of the retriable error-tags, the default ones without
+// retry policy.
+func isRetriable(policy *netconfv1.RetryPolicy, replyErr *rpcReplyError) bool {
+	var retryOn []string
+	if policy != nil {
+		retryOn = policy.RetryOn
+	}
+	if len(retryOn) == 0 {
+		retryOn = defaultRetryOn
+	}
+	for _, rpcError := range replyErr.errors {
+		if !containsString(retryOn, strings.TrimSpace(rpcError.Tag)) {
+			return false
+		}
+	}
+	return true
+}
+
+// retryBackoff returns the delay before the next attempt: the backoff, doubled upon each retry, and randomly
+// increased or decreased by the jitter, up to the maximum backoff.
+func retryBackoff(policy *netconfv1.RetryPolicy, attempts int32) time.Duration {
+	backoff, maxBackoff := policy.Backoff, policy.MaxBackoff
+	if backoff == 0 {
+		backoff = 5
+	}
+	if maxBackoff == 0 {
+		maxBackoff = 300
+	}
+	delay := float64(backoff) * math.Pow(2, float64(attempts-1))
+	delay *= 1 + float64(policy.Jitter)/100*(2*rand.Float64()-1)
+	return time.Duration(math.Max(math.Min(delay, float64(maxBackoff)), 1) * float64(time.Second))
+}
@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/openshift-telco/go-netconf-client/netconf/message"
+	"github.com/redhat-cop/operator-utils/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   netconfv1.RetryPolicy
+		attempts int32
+		min, max time.Duration
+	}{
+		{name: "default backoff", attempts: 1, min: 5 * time.Second, max: 5 * time.Second},
+		{name: "doubled upon each retry", attempts: 3, min: 20 * time.Second, max: 20 * time.Second},
+		{
+			name:     "capped by the default maximum backoff",
+			attempts: 10,
+			min:      300 * time.Second,
+			max:      300 * time.Second,
+		},
+		{
+			name:     "custom backoff",
+			policy:   netconfv1.RetryPolicy{Backoff: 2, MaxBackoff: 60},
+			attempts: 2,
+			min:      4 * time.Second,
+			max:      4 * time.Second,
+		},
+		{
+			name:     "capped by the maximum backoff",
+			policy:   netconfv1.RetryPolicy{Backoff: 2, MaxBackoff: 60},
+			attempts: 6,
+			min:      60 * time.Second,
+			max:      60 * time.Second,
+		},
+		{
+			name:     "jitter",
+			policy:   netconfv1.RetryPolicy{Backoff: 10, Jitter: 20},
+			attempts: 1,
+			min:      8 * time.Second,
+			max:      12 * time.Second,
+		},
+		{
+			name:     "jitter, capped by the maximum backoff",
+			policy:   netconfv1.RetryPolicy{Backoff: 10, MaxBackoff: 10, Jitter: 50},
+			attempts: 1,
+			min:      5 * time.Second,
+			max:      10 * time.Second,
+		},
+		{
+			name:     "at least a second",
+			policy:   netconfv1.RetryPolicy{Backoff: 1, Jitter: 100},
+			attempts: 1,
+			min:      time.Second,
+			max:      2 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The jitter is random: draw several delays
+			for i := 0; i < 100; i++ {
+				if got := retryBackoff(&tt.policy, tt.attempts); got < tt.min || got > tt.max {
+					t.Fatalf("retryBackoff() = %s, want between %s and %s", got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestIsRetriable(t *testing.T) {
+	tests := []struct {
+		name    string
+		retryOn []string
+		tags    []string
+		want    bool
+	}{
+		{name: "lock-denied", tags: []string{"lock-denied"}, want: true},
+		{name: "in-use, resource-denied", tags: []string{"in-use", "resource-denied"}, want: true},
+		{name: "tag surrounded by spaces", tags: []string{"\n  lock-denied\n"}, want: true},
+		{name: "other tag", tags: []string{"invalid-value"}},
+		{name: "one tag not retriable", tags: []string{"lock-denied", "invalid-value"}},
+		{name: "custom tags", retryOn: []string{"invalid-value"}, tags: []string{"invalid-value"}, want: true},
+		{name: "default tags replaced", retryOn: []string{"invalid-value"}, tags: []string{"lock-denied"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replyErr := &rpcReplyError{}
+			for _, tag := range tt.tags {
+				replyErr.errors = append(replyErr.errors, message.RPCError{Tag: tag, Severity: "error"})
+			}
+			if got := isRetriable(&netconfv1.RetryPolicy{RetryOn: tt.retryOn}, replyErr); got != tt.want {
+				t.Errorf("isRetriable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfterLock(t *testing.T) {
+	r := &EditConfigReconciler{
+		ReconcilerBase: util.NewReconcilerBase(nil, nil, nil, record.NewFakeRecorder(10), nil),
+	}
+	obj := &netconfv1.EditConfig{
+		Spec: netconfv1.EditConfigSpec{
+			Target: message.DatastoreCandidate, Lock: true, Commit: true, Unlock: true,
+			RetryPolicy: &netconfv1.RetryPolicy{MaxAttempts: 3},
+		},
+	}
+	// The edit fails once, the datastore being in use, then succeeds
+	s := newTestSession(map[string][]string{"edit-config": {"in-use"}})
+	edit := configEdit{operation: "merge", xml: interfaces("<name>eth0</name>")}
+
+	var replyErr *rpcReplyError
+	if err := r.apply(obj, s, logr.Discard(), edit); !errors.As(err, &replyErr) ||
+		!isRetriable(obj.Spec.RetryPolicy, replyErr) {
+		t.Fatalf("apply() error = %v, want a retriable error", err)
+	}
+	if err := r.apply(obj, s, logr.Discard(), edit); err != nil {
+		t.Fatalf("apply() retried, error = %v", err)
+	}
+	if s.locked[message.DatastoreCandidate] {
+		t.Error("apply() left the datastore locked")
+	}
+}
+
+func TestManageRetry(t *testing.T) {
+	tests := []struct {
+		name          string
+		specHash      string
+		policy        *netconfv1.RetryPolicy
+		attempts      int32
+		tag           string
+		wantErr       bool
+		wantCompleted bool
+	}{
+		{name: "one-shot, retriable by default", specHash: "sha256:0", tag: "lock-denied", wantErr: true},
+		{name: "one-shot, not retriable", specHash: "sha256:0", tag: "data-missing", wantCompleted: true},
+		{name: "not retriable", tag: "data-missing", wantErr: true},
+		{
+			name:     "one-shot, retried",
+			specHash: "sha256:0",
+			policy:   &netconfv1.RetryPolicy{MaxAttempts: 3},
+			attempts: 1,
+			tag:      "lock-denied",
+		},
+		{
+			name:          "one-shot, retries exhausted",
+			specHash:      "sha256:0",
+			policy:        &netconfv1.RetryPolicy{MaxAttempts: 3},
+			attempts:      3,
+			tag:           "lock-denied",
+			wantCompleted: true,
+		},
+		{
+			name:          "one-shot, not retriable by the policy",
+			specHash:      "sha256:0",
+			policy:        &netconfv1.RetryPolicy{MaxAttempts: 3},
+			attempts:      1,
+			tag:           "data-missing",
+			wantCompleted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &testClient{}
+			obj := &netconfv1.Lock{
+				ObjectMeta: metav1.ObjectMeta{Name: "lock", Generation: 1},
+				RPCStatus:  netconfv1.RPCStatus{SpecHash: tt.specHash},
+			}
+			if tt.policy != nil {
+				obj.Retry = &netconfv1.RetryStatus{ObservedGeneration: 1, Attempts: tt.attempts}
+			}
+			err := &rpcReplyError{errors: []message.RPCError{{Tag: tt.tag, Severity: "error"}}}
+			setFailed(obj, err, netconfv1.AppliedCondition)
+
+			_, gotErr := manageRetry(context.Background(), newTestReconcilerBase(c), obj, tt.policy, &obj.RPCStatus, err)
+			if (gotErr != nil) != tt.wantErr {
+				t.Errorf("manageRetry() error = %v, wantErr %v", gotErr, tt.wantErr)
+			}
+			if completed := obj.CompletedAt != nil; completed != tt.wantCompleted {
+				t.Errorf("manageRetry() completed = %v, want %v", completed, tt.wantCompleted)
+			}
+			if c.statusUpdates == 0 {
+				t.Error("manageRetry() didn't update the status")
+			}
+		})
+	}
+}
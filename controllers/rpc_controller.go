@@ -45,6 +45,7 @@ import (
 // RPCReconciler reconciles a RPC object
 type RPCReconciler struct {
 	util.ReconcilerBase
+	sessions SessionProvider
 }
 
 // AddRPC Add creates a new MountPoint Controller and adds it to the Manager.
@@ -73,8 +74,17 @@ func (r *RPCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		return r.ManageError(ctx, instance, err)
 	}
 
+	// The operation is executed once per spec, unless executed again through its run-id
+	execute, result, err := manageExecution(
+		ctx, r.ReconcilerBase, instance, instance.Spec, instance.Spec.ExecutionPolicy, &instance.RPCStatus, nil, log,
+	)
+	if !execute {
+		return result, err
+	}
+
 	// Managing CR validation
 	if ok, err := r.isValid(instance); !ok {
+		setFailed(instance, err, netconfv1.AppliedCondition)
 		return r.ManageErrorWithRequeue(ctx, instance, err, 2*time.Second)
 	}
 
@@ -109,12 +119,17 @@ func (r *RPCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	}
 
 	// Managing Logic
+	// Wait for the retry scheduled following a transient failure, if any
+	if wait := startAttempt(instance.Spec.RetryPolicy, &instance.RPCStatus, instance.Generation); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
 	err = r.manageOperatorLogic(instance, log)
 	if err != nil {
-		return r.ManageError(ctx, instance, err)
+		return manageRetry(ctx, r.ReconcilerBase, instance, instance.Spec.RetryPolicy, &instance.RPCStatus, err)
 	}
 
-	return r.ManageSuccess(ctx, instance)
+	return manageCompletion(ctx, r.ReconcilerBase, instance, instance.Spec.ExecutionPolicy, &instance.RPCStatus)
 }
 
 func (r *RPCReconciler) isInitialized(obj metav1.Object) bool {
@@ -137,11 +152,11 @@ func (r *RPCReconciler) isValid(obj metav1.Object) (bool, error) {
 	}
 
 	exists := CheckMountPointExists(
-		r.ReconcilerBase,
+		r.ReconcilerBase, r.sessions,
 		types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.MountPoint},
 	)
 	if !exists {
-		return false, fmt.Errorf("MountPoint %s doesn't exists", instance.Spec.MountPoint)
+		return false, mountPointUnavailable(instance.Spec.MountPoint)
 	}
 
 	return true, nil
@@ -157,19 +172,46 @@ func (r *RPCReconciler) isValid(obj metav1.Object) (bool, error) {
 func (r *RPCReconciler) manageOperatorLogic(obj *netconfv1.RPC, log logr.Logger) error {
 	log.Info(fmt.Sprintf("%s: Send RPC %s.", obj.Spec.MountPoint, obj.Name))
 
-	s := Sessions[obj.GetMountPointNamespacedName(obj.Spec.MountPoint)]
-	reply, err := s.SyncRPC(message.NewRPC(obj.Spec.XML), obj.Spec.Timeout)
+	s, err := r.sessions.Acquire(obj.GetMountPointNamespacedName(obj.Spec.MountPoint))
+	if err != nil {
+		err = mountPointUnavailable(obj.Spec.MountPoint)
+		setFailed(obj, err, netconfv1.AppliedCondition)
+		return err
+	}
+	defer r.sessions.Release(s)
+
+	ns := namespacesFor(r.sessions, obj.GetMountPointNamespacedName(obj.Spec.MountPoint), obj.Spec.YANGMapping)
+	payload := obj.Spec.XML
+	if obj.Spec.JSON != nil {
+		if payload != "" {
+			err = fmt.Errorf("only one of xml and json can be set")
+			setFailed(obj, err, netconfv1.AppliedCondition)
+			return err
+		}
+		payload, err = jsonToXML(obj.Spec.JSON, ns)
+		if err != nil {
+			setFailed(obj, err, netconfv1.AppliedCondition)
+			return err
+		}
+	}
+	reply, err := s.SyncRPC(message.NewRPC(payload), obj.Spec.Timeout)
 
-	if err != nil || reply.Errors != nil {
+	err = checkReply(reply, err)
+	if err != nil {
 		log.Info(fmt.Sprintf("%s: Failed to send RPC %s.", obj.Spec.MountPoint, obj.Name))
-		obj.Status = "failed"
-		obj.RpcReply = reply.RawReply
+		setReplyStatus(&obj.RPCStatus, reply, err)
+		setFailed(obj, err, netconfv1.AppliedCondition)
 		return err
 	}
 
 	log.Info(fmt.Sprintf("%s: Successfully executed RPC %s operation.", obj.Spec.MountPoint, obj.Name))
-	obj.Status = "success"
-	obj.RpcReply = reply.Data
+	setReplyStatus(&obj.RPCStatus, reply, nil)
+	err = setReplyJSON(&obj.RPCStatus, obj.Spec.ReplyFormat, reply, ns)
+	if err != nil {
+		setFailed(obj, err, netconfv1.AppliedCondition)
+		return err
+	}
+	setSucceeded(obj, netconfv1.AppliedCondition)
 
 	return nil
 }
@@ -180,6 +222,7 @@ func newRPCReconciler(mgr manager.Manager) reconcile.Reconciler {
 			mgr.GetClient(), mgr.GetScheme(), mgr.GetConfig(), mgr.GetEventRecorderFor(rpcControllerName),
 			mgr.GetAPIReader(),
 		),
+		sessions: sessions,
 	}
 }
 
@@ -192,7 +235,7 @@ func addRPC(mgr manager.Manager, r reconcile.Reconciler) error {
 
 	err = c.Watch(
 		&source.Kind{Type: &netconfv1.RPC{}}, &handler.EnqueueRequestForObject{},
-		util.ResourceGenerationOrFinalizerChangedPredicate{},
+		executionPredicate,
 	)
 	if err != nil {
 		return err
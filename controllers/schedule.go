@@ -0,0 +1,284 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"strings"
+	"time"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// scheduledKinds are the kinds of the operations a ScheduledOperation can create.
+var scheduledKinds = []string{
+	"Commit", "ConfigRestore", "CopyConfig", "DeleteConfig", "DiscardChanges", "EditConfig", "Get", "GetConfig", "Lock",
+	"NetworkTransaction", "RPC", "Unlock", "Validate", "Workflow",
+}
+
+// runInProgressReasons are the reasons of the Ready condition of an operation still in progress: the run is
+// completed once its operation is Ready, or reports another reason.
+var runInProgressReasons = []string{
+	netconfv1.PendingReason,
+	netconfv1.RetryingReason,
+	netconfv1.ConfirmationPendingReason,
+	netconfv1.HealthCheckFailedReason,
+	netconfv1.SourcePendingReason,
+}
+
+// maintenanceWindow opens upon its schedule, for its duration.
+type maintenanceWindow struct {
+	start    *cronSchedule
+	duration time.Duration
+}
+
+// parseScheduledOperation parses the schedule, the maintenance windows and the time zone of the
+// ScheduledOperation.
+func parseScheduledOperation(
+	spec netconfv1.ScheduledOperationSpec,
+) (*cronSchedule, []maintenanceWindow, *time.Location, error) {
+	schedule, err := parseCron(spec.Schedule)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	windows := make([]maintenanceWindow, 0, len(spec.MaintenanceWindows))
+	for _, window := range spec.MaintenanceWindows {
+		start, err := parseCron(window.Start)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid maintenance window: %w", err)
+		}
+		if start.every != 0 {
+			// An interval counts from the time the windows are checked, rather than opening them at set times
+			return nil, nil, nil, fmt.Errorf(
+				"invalid maintenance window %q: the start must use the cron format, rather than @every", window.Start,
+			)
+		}
+		if window.Duration <= 0 {
+			return nil, nil, nil, fmt.Errorf("invalid maintenance window %q: the duration must be set", window.Start)
+		}
+		windows = append(windows, maintenanceWindow{start: start, duration: time.Duration(window.Duration) * time.Second})
+	}
+	location, err := time.LoadLocation(spec.TimeZone)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid time zone %q: %w", spec.TimeZone, err)
+	}
+	return schedule, windows, location, nil
+}
+
+// inMaintenanceWindow reports whether one of the windows is open: a window opened within its duration before the
+// provided time.
+func inMaintenanceWindow(windows []maintenanceWindow, t time.Time) bool {
+	for _, window := range windows {
+		opening := window.start.next(t.Add(-window.duration))
+		if !opening.IsZero() && !opening.After(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextMaintenanceWindow returns the time the next window opens, if any.
+func nextMaintenanceWindow(windows []maintenanceWindow, t time.Time) time.Time {
+	var next time.Time
+	for _, window := range windows {
+		opening := window.start.next(t)
+		if !opening.IsZero() && (next.IsZero() || opening.Before(next)) {
+			next = opening
+		}
+	}
+	return next
+}
+
+// scheduled reports the next time the ScheduledOperation is reconciled, for a run, or the opening of a maintenance
+// window.
+func scheduled(obj *netconfv1.ScheduledOperation, next time.Time, message string) {
+	nextSchedule := metav1.NewTime(next)
+	obj.NextScheduleTime = &nextSchedule
+	setConditions(
+		obj, metav1.ConditionTrue, netconfv1.SucceededReason,
+		fmt.Sprintf("%s at %s", message, next.Format(time.RFC3339)), netconfv1.ReadyCondition,
+	)
+}
+
+// startRun creates the operation of the run due, once the concurrency policy is applied to the active runs.
+func (r *ScheduledOperationReconciler) startRun(
+	obj *netconfv1.ScheduledOperation, due time.Time, log logr.Logger,
+) error {
+	now := metav1.Now()
+	run := netconfv1.ScheduledRun{
+		Kind:         obj.Spec.Operation.Kind,
+		ScheduleTime: metav1.NewTime(due),
+		Result:       netconfv1.RunningResult,
+	}
+
+	var active []string
+	for i := range obj.History {
+		if obj.History[i].Result == netconfv1.RunningResult {
+			active = append(active, obj.History[i].Name)
+		}
+	}
+	if len(active) != 0 {
+		switch obj.Spec.ConcurrencyPolicy {
+		case netconfv1.ForbidConcurrent:
+			run.Result = netconfv1.SkippedResult
+			run.Message = fmt.Sprintf("Skipped, as %s is still active", strings.Join(active, ", "))
+			log.Info(fmt.Sprintf("Run of ScheduledOperation %s skipped: %s is still active.", obj.Name, active))
+			r.GetRecorder().Event(obj, "Warning", netconfv1.RunSkippedReason, run.Message)
+			obj.History = append([]netconfv1.ScheduledRun{run}, obj.History...)
+			return nil
+		case netconfv1.ReplaceConcurrent:
+			for i := range obj.History {
+				previous := &obj.History[i]
+				if previous.Result != netconfv1.RunningResult {
+					continue
+				}
+				err := r.deleteOperation(obj, previous)
+				if err != nil {
+					return err
+				}
+				completeRun(previous, netconfv1.ReplacedResult, "Replaced by the next run", now.Time)
+				r.GetRecorder().Event(
+					obj, "Normal", netconfv1.RunReplacedReason,
+					fmt.Sprintf("%s %s replaced by the next run", previous.Kind, previous.Name),
+				)
+			}
+		}
+	}
+
+	var spec map[string]interface{}
+	err := json.Unmarshal(obj.Spec.Operation.Spec.Raw, &spec)
+	if err != nil {
+		return fmt.Errorf("invalid operation spec: %w", err)
+	}
+	operation := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	operation.SetAPIVersion(netconfv1.GroupVersion.String())
+	operation.SetKind(run.Kind)
+	operation.SetNamespace(obj.Namespace)
+	operation.SetName(fmt.Sprintf("%s-%d", obj.Name, due.Unix()))
+	operation.SetLabels(obj.Spec.Operation.Labels)
+	operation.SetAnnotations(obj.Spec.Operation.Annotations)
+	err = controllerutil.SetControllerReference(obj, operation, r.GetScheme())
+	if err != nil {
+		return err
+	}
+	err = r.GetClient().Create(context.Background(), operation)
+	// Already created, when the status couldn't be updated following the creation
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create %s %s: %w", run.Kind, operation.GetName(), err)
+	}
+
+	run.Name = operation.GetName()
+	run.StartTime = &now
+	log.Info(fmt.Sprintf("Run of ScheduledOperation %s started %s %s.", obj.Name, run.Kind, run.Name))
+	r.GetRecorder().Event(obj, "Normal", netconfv1.RunStartedReason, fmt.Sprintf("Started %s %s", run.Kind, run.Name))
+	for i := range obj.History {
+		if obj.History[i].Kind == run.Kind && obj.History[i].Name == run.Name {
+			return nil
+		}
+	}
+	obj.History = append([]netconfv1.ScheduledRun{run}, obj.History...)
+	return nil
+}
+
+// updateHistory reports the outcome of the runs whose operation was still active.
+func (r *ScheduledOperationReconciler) updateHistory(obj *netconfv1.ScheduledOperation) error {
+	for i := range obj.History {
+		run := &obj.History[i]
+		if run.Result != netconfv1.RunningResult {
+			continue
+		}
+		newOperation, ok := dependencyKinds[run.Kind]
+		if !ok {
+			completeRun(run, netconfv1.FailedResult, fmt.Sprintf("Unsupported kind %s", run.Kind), time.Now())
+			continue
+		}
+		operation := newOperation()
+		err := r.GetClient().Get(
+			context.Background(), types.NamespacedName{Namespace: obj.Namespace, Name: run.Name}, operation,
+		)
+		if apierrors.IsNotFound(err) {
+			completeRun(run, netconfv1.FailedResult, "The operation was deleted before completing", time.Now())
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		ready := meta.FindStatusCondition(operation.GetConditions(), netconfv1.ReadyCondition)
+		if ready == nil || ready.ObservedGeneration != operation.GetGeneration() {
+			continue
+		}
+		switch {
+		case ready.Status == metav1.ConditionTrue || ready.Reason == netconfv1.DryRunReason:
+			completeRun(run, netconfv1.SucceededResult, ready.Message, ready.LastTransitionTime.Time)
+		case !containsString(runInProgressReasons, ready.Reason):
+			completeRun(run, netconfv1.FailedResult, ready.Message, ready.LastTransitionTime.Time)
+			r.GetRecorder().Event(
+				obj, "Warning", netconfv1.FailedReason, fmt.Sprintf("%s %s failed: %s", run.Kind, run.Name, ready.Message),
+			)
+		}
+	}
+	return nil
+}
+
+func completeRun(run *netconfv1.ScheduledRun, result netconfv1.ScheduledRunResult, message string, at time.Time) {
+	completion := metav1.NewTime(at)
+	run.Result = result
+	run.Message = message
+	run.CompletionTime = &completion
+	if run.StartTime != nil {
+		run.Duration = at.Sub(run.StartTime.Time).Round(time.Second).String()
+	}
+}
+
+// trimHistory removes the oldest completed runs beyond the history limit, along with their operation. The active
+// runs are always kept.
+func (r *ScheduledOperationReconciler) trimHistory(obj *netconfv1.ScheduledOperation) error {
+	limit := int(obj.Spec.HistoryLimit)
+	if limit == 0 {
+		limit = 10
+	}
+	history := make([]netconfv1.ScheduledRun, 0, len(obj.History))
+	completed := 0
+	for i := range obj.History {
+		run := &obj.History[i]
+		if run.Result != netconfv1.RunningResult {
+			completed++
+			if completed > limit {
+				err := r.deleteOperation(obj, run)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		history = append(history, *run)
+	}
+	obj.History = history
+	return nil
+}
+
+// deleteOperation deletes the operation of the run, if any.
+func (r *ScheduledOperationReconciler) deleteOperation(
+	obj *netconfv1.ScheduledOperation, run *netconfv1.ScheduledRun,
+) error {
+	newOperation, ok := dependencyKinds[run.Kind]
+	if run.Name == "" || !ok {
+		return nil
+	}
+	operation := newOperation()
+	operation.SetNamespace(obj.Namespace)
+	operation.SetName(run.Name)
+	err := r.GetClient().Delete(context.Background(), operation)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete %s %s: %w", run.Kind, run.Name, err)
+	}
+	return nil
+}
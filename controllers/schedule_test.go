@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// testWindows parses the maintenance windows, each opening upon its cron schedule for its duration, in seconds.
+func testWindows(t *testing.T, windows ...netconfv1.MaintenanceWindow) []maintenanceWindow {
+	t.Helper()
+	_, parsed, _, err := parseScheduledOperation(
+		netconfv1.ScheduledOperationSpec{Schedule: "@hourly", MaintenanceWindows: windows},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestParseScheduledOperation(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    netconfv1.ScheduledOperationSpec
+		wantErr string
+	}{
+		{
+			name: "valid",
+			spec: netconfv1.ScheduledOperationSpec{
+				Schedule:           "0 2 * * *",
+				MaintenanceWindows: []netconfv1.MaintenanceWindow{{Start: "0 1 * * sat", Duration: 3600}},
+				TimeZone:           "UTC",
+			},
+		},
+		{name: "invalid schedule", spec: netconfv1.ScheduledOperationSpec{Schedule: "0 2 * *"}, wantErr: "0 2 * *"},
+		{
+			name: "invalid window",
+			spec: netconfv1.ScheduledOperationSpec{
+				Schedule:           "0 2 * * *",
+				MaintenanceWindows: []netconfv1.MaintenanceWindow{{Start: "0 25 * * *", Duration: 3600}},
+			},
+			wantErr: "invalid maintenance window",
+		},
+		{
+			name: "window without duration",
+			spec: netconfv1.ScheduledOperationSpec{
+				Schedule:           "0 2 * * *",
+				MaintenanceWindows: []netconfv1.MaintenanceWindow{{Start: "0 1 * * sat"}},
+			},
+			wantErr: "the duration must be set",
+		},
+		{
+			name: "window opening at an interval",
+			spec: netconfv1.ScheduledOperationSpec{
+				Schedule:           "0 2 * * *",
+				MaintenanceWindows: []netconfv1.MaintenanceWindow{{Start: "@every 24h", Duration: 3600}},
+			},
+			wantErr: "the start must use the cron format",
+		},
+		{
+			name:    "invalid time zone",
+			spec:    netconfv1.ScheduledOperationSpec{Schedule: "0 2 * * *", TimeZone: "Mars/Olympus_Mons"},
+			wantErr: "invalid time zone",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := parseScheduledOperation(tt.spec)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("parseScheduledOperation() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseScheduledOperation() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInMaintenanceWindow(t *testing.T) {
+	// Saturdays from 01:00 to 03:00, and every day from 22:00 to 22:30
+	windows := testWindows(t,
+		netconfv1.MaintenanceWindow{Start: "0 1 * * sat", Duration: 7200},
+		netconfv1.MaintenanceWindow{Start: "0 22 * * *", Duration: 1800},
+	)
+	tests := []struct {
+		at   string
+		want bool
+	}{
+		{at: "2026-10-17T00:59:59Z"},
+		{at: "2026-10-17T01:00:00Z", want: true},
+		{at: "2026-10-17T02:59:59Z", want: true},
+		{at: "2026-10-17T03:00:00Z"},
+		{at: "2026-10-18T01:30:00Z"},
+		{at: "2026-10-19T22:15:00Z", want: true},
+		{at: "2026-10-19T22:30:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.at, func(t *testing.T) {
+			if got := inMaintenanceWindow(windows, mustParseTime(t, tt.at)); got != tt.want {
+				t.Errorf("inMaintenanceWindow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if inMaintenanceWindow(nil, mustParseTime(t, "2026-10-17T01:00:00Z")) {
+		t.Error("inMaintenanceWindow() = true without any window")
+	}
+}
+
+func TestNextMaintenanceWindow(t *testing.T) {
+	windows := testWindows(t,
+		netconfv1.MaintenanceWindow{Start: "0 1 * * sat", Duration: 7200},
+		netconfv1.MaintenanceWindow{Start: "0 22 * * *", Duration: 1800},
+	)
+	never := testWindows(t, netconfv1.MaintenanceWindow{Start: "0 0 30 feb *", Duration: 60})
+	tests := []struct {
+		name    string
+		windows []maintenanceWindow
+		from    string
+		want    string
+	}{
+		{name: "earliest window", windows: windows, from: "2026-10-16T12:00:00Z", want: "2026-10-16T22:00:00Z"},
+		{name: "other window", windows: windows, from: "2026-10-16T23:00:00Z", want: "2026-10-17T01:00:00Z"},
+		{name: "window opening", windows: windows, from: "2026-10-17T01:00:00Z", want: "2026-10-17T22:00:00Z"},
+		{name: "never opening", windows: never, from: "2026-10-16T12:00:00Z", want: "0001-01-01T00:00:00Z"},
+		{name: "no window", from: "2026-10-16T12:00:00Z", want: "0001-01-01T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextMaintenanceWindow(tt.windows, mustParseTime(t, tt.from))
+			if want := mustParseTime(t, tt.want); !got.Equal(want) {
+				t.Errorf("nextMaintenanceWindow() = %s, want %s", got.Format(time.RFC3339), want.Format(time.RFC3339))
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2021. Alexis de Talhouët
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"time"
+
+	"github.com/redhat-cop/operator-utils/pkg/util"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=scheduledoperations,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=scheduledoperations/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=scheduledoperations/finalizers,verbs=update
+
+// ScheduledOperationReconciler reconciles a ScheduledOperation object
+type ScheduledOperationReconciler struct {
+	util.ReconcilerBase
+}
+
+// AddScheduledOperation creates a new ScheduledOperation Controller and adds it to the Manager.
+func AddScheduledOperation(mgr manager.Manager) error {
+	return addScheduledOperation(mgr, newScheduledOperationReconciler(mgr))
+}
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *ScheduledOperationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var log = logf.Log.WithName(scheduledOperationControllerName)
+
+	reqLogger := log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+	reqLogger.Info("Reconciling ScheduledOperation")
+
+	// Fetch the CRD instance
+	instance := &netconfv1.ScheduledOperation{}
+	err := r.GetClient().Get(context.Background(), req.NamespacedName, instance)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("ScheduledOperation resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		log.Error(err, "Failed to get ScheduledOperation")
+		return r.ManageError(ctx, instance, err)
+	}
+
+	// Managing CR Finalization
+	if util.IsBeingDeleted(instance) {
+		return reconcile.Result{}, nil
+	}
+
+	err = r.manageOperatorLogic(instance, log)
+	if err != nil {
+		return r.ManageError(ctx, instance, err)
+	}
+
+	result, err := r.ManageSuccess(ctx, instance)
+	if err == nil && instance.NextScheduleTime != nil {
+		// Wait for the next run, or the next maintenance window
+		result.RequeueAfter = time.Until(instance.NextScheduleTime.Time) + time.Second
+	}
+	return result, err
+}
+
+// manageOperatorLogic reports the outcome of the active runs, then starts a run once the schedule is due, within a
+// maintenance window. A schedule missed, e.g. while the operator wasn't running, or outside of the maintenance
+// windows, is caught up once.
+func (r *ScheduledOperationReconciler) manageOperatorLogic(
+	obj *netconfv1.ScheduledOperation, log logr.Logger,
+) error {
+	schedule, windows, location, err := parseScheduledOperation(obj.Spec)
+	if err != nil {
+		err = &conditionError{reason: netconfv1.InvalidScheduleReason, message: err.Error()}
+		setFailed(obj, err)
+		return err
+	}
+	if !containsString(scheduledKinds, obj.Spec.Operation.Kind) {
+		err = fmt.Errorf("unsupported operation kind %s", obj.Spec.Operation.Kind)
+		setFailed(obj, err)
+		return err
+	}
+
+	err = r.updateHistory(obj)
+	if err != nil {
+		return err
+	}
+
+	if obj.Spec.Suspend {
+		obj.NextScheduleTime = nil
+		setConditions(obj, metav1.ConditionTrue, netconfv1.SucceededReason, "Suspended", netconfv1.ReadyCondition)
+		return nil
+	}
+	last := obj.CreationTimestamp.Time
+	if obj.LastScheduleTime != nil {
+		last = obj.LastScheduleTime.Time
+	}
+	due := schedule.next(last.In(location))
+	if due.IsZero() {
+		err = &conditionError{
+			reason:  netconfv1.InvalidScheduleReason,
+			message: fmt.Sprintf("schedule %q never matches", obj.Spec.Schedule),
+		}
+		setFailed(obj, err)
+		return err
+	}
+	now := time.Now().In(location)
+	if now.Before(due) {
+		scheduled(obj, due, "Next run")
+		return nil
+	}
+	if len(windows) != 0 && !inMaintenanceWindow(windows, now) {
+		opening := nextMaintenanceWindow(windows, now)
+		if opening.IsZero() {
+			err = &conditionError{
+				reason: netconfv1.InvalidScheduleReason, message: "the maintenance windows never open",
+			}
+			setFailed(obj, err)
+			return err
+		}
+		log.Info(fmt.Sprintf("Run of ScheduledOperation %s deferred until %s.", obj.Name, opening))
+		scheduled(obj, opening, "Run deferred until the maintenance window opens")
+		return nil
+	}
+
+	err = r.startRun(obj, due, log)
+	if err != nil {
+		setFailed(obj, err)
+		return err
+	}
+	lastSchedule := metav1.NewTime(now)
+	obj.LastScheduleTime = &lastSchedule
+	scheduled(obj, schedule.next(now), "Next run")
+	return r.trimHistory(obj)
+}
+
+func newScheduledOperationReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ScheduledOperationReconciler{
+		ReconcilerBase: util.NewReconcilerBase(
+			mgr.GetClient(), mgr.GetScheme(), mgr.GetConfig(),
+			mgr.GetEventRecorderFor(scheduledOperationControllerName), mgr.GetAPIReader(),
+		),
+	}
+}
+
+func addScheduledOperation(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(scheduledOperationControllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(
+		&source.Kind{Type: &netconfv1.ScheduledOperation{}}, &handler.EnqueueRequestForObject{},
+		util.ResourceGenerationOrFinalizerChangedPredicate{},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch over the operations created by the runs, to report their outcome
+	for _, kind := range scheduledKinds {
+		err = c.Watch(
+			&source.Kind{Type: dependencyKinds[kind]()},
+			&handler.EnqueueRequestForOwner{OwnerType: &netconfv1.ScheduledOperation{}, IsController: true},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/openshift-telco/go-netconf-client/netconf"
+	"github.com/openshift-telco/go-netconf-client/netconf/message"
+	"sync"
+)
+
+// Session is the subset of a NETCONF session the controllers rely on.
+type Session interface {
+	// SyncRPC executes the RPC and waits for its reply, for at most timeout seconds.
+	SyncRPC(operation message.RPCMethod, timeout int32) (*message.RPCReply, error)
+	// CreateNotificationStream registers the callback, and creates the RFC5277 notification stream.
+	CreateNotificationStream(timeout int32, stopTime string, startTime string, stream string, callback netconf.Callback) error
+	// RegisterListener registers the callback for the messages identified by eventID, e.g. a subscription-id.
+	RegisterListener(eventID string, callback netconf.Callback)
+	// RemoveListener removes the callback registered for eventID.
+	RemoveListener(eventID string)
+}
+
+// SessionProvider gives access to the sessions established for the MountPoints.
+// The MountPoint is identified by its NamespacedName.
+type SessionProvider interface {
+	// Acquire returns the session of the MountPoint. It must be given back using Release once done.
+	Acquire(mountPoint string) (Session, error)
+	// Release gives back a session obtained through Acquire.
+	Release(session Session)
+	// Healthy reports whether the session of the MountPoint is established, and its transport is up.
+	Healthy(mountPoint string) bool
+	// Capabilities returns the capabilities advertised by the NETCONF server of the MountPoint.
+	Capabilities(mountPoint string) []string
+}
+
+// sessions hold the sessions established for the MountPoints, shared by all the controllers.
+var sessions = newSessionManager()
+
+// managedSession wraps a NETCONF session to keep track of its users. As the dispatcher of the
+// NETCONF client isn't safe for concurrent use, RPCs sent over the session are serialized.
+type managedSession struct {
+	session   *netconf.Session
+	transport *supervisedTransport
+	timeout   int32
+
+	rpcLock sync.Mutex
+
+	// Guarded by the session manager lock
+	refs     int
+	retired  bool
+	graceful bool
+}
+
+func (s *managedSession) SyncRPC(operation message.RPCMethod, timeout int32) (*message.RPCReply, error) {
+	s.rpcLock.Lock()
+	defer s.rpcLock.Unlock()
+	return s.session.SyncRPC(operation, timeout)
+}
+
+func (s *managedSession) CreateNotificationStream(
+	timeout int32, stopTime string, startTime string, stream string, callback netconf.Callback,
+) error {
+	s.rpcLock.Lock()
+	defer s.rpcLock.Unlock()
+	return s.session.CreateNotificationStream(timeout, stopTime, startTime, stream, callback)
+}
+
+func (s *managedSession) RegisterListener(eventID string, callback netconf.Callback) {
+	s.rpcLock.Lock()
+	defer s.rpcLock.Unlock()
+	s.session.Listener.Register(eventID, callback)
+}
+
+func (s *managedSession) RemoveListener(eventID string) {
+	s.rpcLock.Lock()
+	defer s.rpcLock.Unlock()
+	s.session.Listener.Remove(eventID)
+}
+
+// close ends the session, gracefully if requested, i.e. using a close-session RPC.
+func (s *managedSession) close() error {
+	s.rpcLock.Lock()
+	defer s.rpcLock.Unlock()
+	if s.graceful {
+		return closeSession(s.session, s.timeout)
+	}
+	return s.session.Close()
+}
+
+// sessionManager implements SessionProvider. It also lets the MountPoint controller put, replace
+// and remove sessions. A replaced or removed session is closed once its last user releases it.
+type sessionManager struct {
+	lock     sync.Mutex
+	sessions map[string]*managedSession
+}
+
+func newSessionManager() *sessionManager {
+	return &sessionManager{sessions: make(map[string]*managedSession)}
+}
+
+func (m *sessionManager) Acquire(mountPoint string) (Session, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	s, ok := m.sessions[mountPoint]
+	if !ok {
+		return nil, fmt.Errorf("MountPoint %s has no session", mountPoint)
+	}
+	s.refs++
+	return s, nil
+}
+
+func (m *sessionManager) Release(session Session) {
+	s, ok := session.(*managedSession)
+	if !ok {
+		return
+	}
+	m.lock.Lock()
+	s.refs--
+	closing := s.retired && s.refs == 0
+	m.lock.Unlock()
+
+	if closing {
+		_ = s.close()
+	}
+}
+
+func (m *sessionManager) Healthy(mountPoint string) bool {
+	m.lock.Lock()
+	s, ok := m.sessions[mountPoint]
+	m.lock.Unlock()
+	if !ok {
+		return false
+	}
+	select {
+	case <-s.transport.lost:
+		return false
+	default:
+		return true
+	}
+}
+
+func (m *sessionManager) Capabilities(mountPoint string) []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	s, ok := m.sessions[mountPoint]
+	if !ok {
+		return nil
+	}
+	return s.session.Capabilities
+}
+
+// Put sets the session of the MountPoint. The previous session, if any, is gracefully closed
+// once released by all its users.
+func (m *sessionManager) Put(
+	mountPoint string, session *netconf.Session, transport *supervisedTransport, timeout int32,
+) error {
+	m.lock.Lock()
+	previous := m.sessions[mountPoint]
+	m.sessions[mountPoint] = &managedSession{session: session, transport: transport, timeout: timeout}
+	m.lock.Unlock()
+
+	return m.retire(previous, true)
+}
+
+// Remove removes the session of the MountPoint. The session is gracefully closed once released
+// by all its users.
+func (m *sessionManager) Remove(mountPoint string) error {
+	m.lock.Lock()
+	s := m.sessions[mountPoint]
+	delete(m.sessions, mountPoint)
+	m.lock.Unlock()
+
+	return m.retire(s, true)
+}
+
+// Drop removes the session of the MountPoint, if carried by the provided transport, e.g. when the
+// transport was lost. The session is closed, without any close-session RPC, once released by all its users.
+func (m *sessionManager) Drop(mountPoint string, transport *supervisedTransport) error {
+	m.lock.Lock()
+	s, ok := m.sessions[mountPoint]
+	if !ok || s.transport != transport {
+		m.lock.Unlock()
+		return nil
+	}
+	delete(m.sessions, mountPoint)
+	m.lock.Unlock()
+
+	return m.retire(s, false)
+}
+
+func (m *sessionManager) retire(s *managedSession, graceful bool) error {
+	if s == nil {
+		return nil
+	}
+	m.lock.Lock()
+	s.retired = true
+	s.graceful = graceful
+	closing := s.refs == 0
+	m.lock.Unlock()
+
+	if closing {
+		return s.close()
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/openshift-telco/go-netconf-client/netconf"
+)
+
+// testHello is the hello-message of the NETCONF server, using the end-of-message framing.
+const testHello = `<hello xmlns="urn:ietf:params:xml:ns:netconf:base:1.0"><capabilities>` +
+	`<capability>urn:ietf:params:netconf:base:1.0</capability>` +
+	`<capability>urn:ietf:params:netconf:capability:candidate:1.0</capability>` +
+	`</capabilities><session-id>1</session-id></hello>`
+
+// testNetconfSession is a NETCONF session whose hello-message was received, recording the messages sent, and
+// whether its transport was closed. The NETCONF server doesn't reply to any RPC.
+type testNetconfSession struct {
+	session   *netconf.Session
+	transport *supervisedTransport
+	out       bytes.Buffer
+	closed    bool
+}
+
+func newTestNetconfSession() *testNetconfSession {
+	s := &testNetconfSession{}
+	framed := newFramedTransport(
+		strings.NewReader(testHello+endOfMessage), &s.out, func() error {
+			s.closed = true
+			return nil
+		},
+	)
+	s.transport = newSupervisedTransport(framed)
+	s.session = netconf.NewSession(s.transport)
+	return s
+}
+
+// graceful reports whether the session was closed using a close-session RPC.
+func (s *testNetconfSession) graceful() bool {
+	return strings.Contains(s.out.String(), "close-session")
+}
+
+func TestSessionManager(t *testing.T) {
+	const mountPoint = "default/device"
+	tests := []struct {
+		name string
+		// Whether the user releases the first session before the change
+		idle bool
+		// The change following the Put of the first session
+		change func(m *sessionManager, first, second *testNetconfSession) error
+		// The session acquired once changed, if any: first or second
+		wantAcquired string
+		// Whether the first session is closed once released, gracefully or not
+		wantClosed, wantGraceful bool
+	}{
+		{
+			name:         "kept",
+			change:       func(*sessionManager, *testNetconfSession, *testNetconfSession) error { return nil },
+			wantAcquired: "first",
+		},
+		{
+			name: "removed",
+			change: func(m *sessionManager, _, _ *testNetconfSession) error {
+				return m.Remove(mountPoint)
+			},
+			wantClosed:   true,
+			wantGraceful: true,
+		},
+		{
+			name: "removed while idle",
+			idle: true,
+			change: func(m *sessionManager, _, _ *testNetconfSession) error {
+				return m.Remove(mountPoint)
+			},
+			wantClosed:   true,
+			wantGraceful: true,
+		},
+		{
+			name: "replaced",
+			change: func(m *sessionManager, _, second *testNetconfSession) error {
+				return m.Put(mountPoint, second.session, second.transport, 0)
+			},
+			wantAcquired: "second",
+			wantClosed:   true,
+			wantGraceful: true,
+		},
+		{
+			name: "dropped",
+			change: func(m *sessionManager, first, _ *testNetconfSession) error {
+				return m.Drop(mountPoint, first.transport)
+			},
+			wantClosed: true,
+		},
+		{
+			name: "dropped once replaced",
+			change: func(m *sessionManager, first, second *testNetconfSession) error {
+				if err := m.Put(mountPoint, second.session, second.transport, 0); err != nil {
+					return err
+				}
+				// The transport of the first session was lost, the second session is kept
+				return m.Drop(mountPoint, first.transport)
+			},
+			wantAcquired: "second",
+			wantClosed:   true,
+			wantGraceful: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newSessionManager()
+			first, second := newTestNetconfSession(), newTestNetconfSession()
+			if err := m.Put(mountPoint, first.session, first.transport, 0); err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+			user, err := m.Acquire(mountPoint)
+			if err != nil {
+				t.Fatalf("Acquire() error = %v", err)
+			}
+			if tt.idle {
+				m.Release(user)
+			}
+
+			if err := tt.change(m, first, second); err != nil {
+				t.Fatalf("change error = %v", err)
+			}
+			if first.closed && !tt.idle {
+				t.Errorf("the first session was closed while acquired")
+			}
+			acquired := ""
+			if s, err := m.Acquire(mountPoint); err == nil {
+				switch s.(*managedSession).session {
+				case first.session:
+					acquired = "first"
+				case second.session:
+					acquired = "second"
+				}
+				m.Release(s)
+			}
+			if acquired != tt.wantAcquired {
+				t.Errorf("Acquire() returned the %q session, want %q", acquired, tt.wantAcquired)
+			}
+
+			if !tt.idle {
+				m.Release(user)
+			}
+			if first.closed != tt.wantClosed || first.graceful() != tt.wantGraceful {
+				t.Errorf(
+					"the first session was closed = %v, gracefully = %v, want %v, %v", first.closed, first.graceful(),
+					tt.wantClosed, tt.wantGraceful,
+				)
+			}
+			if second.closed {
+				t.Errorf("the second session was closed")
+			}
+		})
+	}
+}
+
+func TestSessionManagerHealthy(t *testing.T) {
+	const mountPoint = "default/device"
+	m := newSessionManager()
+	s := newTestNetconfSession()
+	if err := m.Put(mountPoint, s.session, s.transport, 0); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if !m.Healthy(mountPoint) {
+		t.Errorf("Healthy() = false, want true")
+	}
+	if m.Healthy("default/other") {
+		t.Errorf("Healthy() = true for a MountPoint without session, want false")
+	}
+	want := []string{"urn:ietf:params:netconf:base:1.0", "urn:ietf:params:netconf:capability:candidate:1.0"}
+	if got := m.Capabilities(mountPoint); !reflect.DeepEqual(got, want) {
+		t.Errorf("Capabilities() = %q, want %q", got, want)
+	}
+
+	// The NETCONF server closes the connection
+	received := make(chan error)
+	go func() {
+		_, err := s.transport.Receive()
+		received <- err
+	}()
+	<-s.transport.lost
+	if m.Healthy(mountPoint) {
+		t.Errorf("Healthy() = true, once the transport was lost")
+	}
+	if err := m.Drop(mountPoint, s.transport); err != nil {
+		t.Fatalf("Drop() error = %v", err)
+	}
+	if err := <-received; err == nil {
+		t.Errorf("Receive() error = nil, once the transport was lost")
+	}
+	if m.Healthy(mountPoint) || !s.closed {
+		t.Errorf("the session was kept once dropped")
+	}
+}
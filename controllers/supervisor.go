@@ -0,0 +1,410 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	"github.com/openshift-telco/go-netconf-client/netconf"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sync"
+	"time"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// subscriptionEventsBufferSize bounds the subscriptions pending their reconciliation, following the
+// (re)establishment of their MountPoint session.
+const subscriptionEventsBufferSize = 1024
+
+// Channels used to trigger the reconciliation of the subscriptions once their MountPoint session is re-established.
+var (
+	createSubscriptionEvents    = make(chan event.GenericEvent, subscriptionEventsBufferSize)
+	establishSubscriptionEvents = make(chan event.GenericEvent, subscriptionEventsBufferSize)
+)
+
+// supervisors hold the supervisor of each MountPoint session.
+// The key is the NamespacedName of the MountPoint object.
+var (
+	supervisors     = make(map[string]*supervisor)
+	supervisorsLock sync.Mutex
+)
+
+// subscriptions keep track of the session each subscription was registered against, so a subscription
+// reconciled both upon its own change and upon its MountPoint (re)connection is only registered once.
+// The key is the controller name, followed by the NamespacedName of the subscription object.
+var (
+	subscriptions     = make(map[string]subscription)
+	subscriptionsLock sync.Mutex
+)
+
+type subscription struct {
+	session    Session
+	generation int64
+}
+
+// isSubscribed reports whether the subscription, in its current generation, is registered against the session.
+func isSubscribed(key string, session Session, generation int64) bool {
+	subscriptionsLock.Lock()
+	defer subscriptionsLock.Unlock()
+	sub, ok := subscriptions[key]
+	return ok && sub.session == session && sub.generation == generation
+}
+
+// setSubscribed records the subscription as registered against the session.
+func setSubscribed(key string, session Session, generation int64) {
+	subscriptionsLock.Lock()
+	defer subscriptionsLock.Unlock()
+	subscriptions[key] = subscription{session: session, generation: generation}
+}
+
+// removeSubscribed forgets about the subscription.
+func removeSubscribed(key string) {
+	subscriptionsLock.Lock()
+	defer subscriptionsLock.Unlock()
+	delete(subscriptions, key)
+}
+
+// supervisedTransport wraps the NETCONF transport to detect the loss of the underlying connection,
+// either through an EOF, or a failed keepalive. When a timeout is set on the MountPoint, SSH keepalives
+// are sent and a missing answer surfaces as a receive error; otherwise, TCP keepalives are relied upon.
+type supervisedTransport struct {
+	netconf.Transport
+
+	lost      chan struct{}
+	lostOnce  sync.Once
+	lostErr   error
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newSupervisedTransport(t netconf.Transport) *supervisedTransport {
+	return &supervisedTransport{Transport: t, lost: make(chan struct{}), closed: make(chan struct{})}
+}
+
+// Receive reports the first receive error as the loss of the transport. As the session keeps on
+// receiving until it is closed, it then blocks until the transport gets closed.
+func (t *supervisedTransport) Receive() ([]byte, error) {
+	b, err := t.Transport.Receive()
+	if err != nil {
+		t.lostOnce.Do(
+			func() {
+				t.lostErr = err
+				close(t.lost)
+			},
+		)
+		<-t.closed
+	}
+	return b, err
+}
+
+func (t *supervisedTransport) Close() error {
+	t.closeOnce.Do(func() { close(t.closed) })
+	return t.Transport.Close()
+}
+
+// supervisor watches over the session of a MountPoint, and re-establishes it, with an exponential
+// backoff, when the transport is lost.
+type supervisor struct {
+	r         *MountPointReconciler
+	key       types.NamespacedName
+	transport *supervisedTransport
+	log       logr.Logger
+	// The session of a MountPoint in Call Home mode isn't re-established by the supervisor,
+	// but by the NETCONF server calling home again.
+	callHome bool
+
+	lock    sync.Mutex
+	stopped bool
+	stop    chan struct{}
+}
+
+// startSupervisor starts supervising the session of the MountPoint, carried by the provided transport.
+func startSupervisor(r *MountPointReconciler, obj *netconfv1.MountPoint, transport *supervisedTransport) {
+	s := &supervisor{
+		r:         r,
+		key:       types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name},
+		transport: transport,
+		log:       logf.Log.WithName(mountPointControllerName).WithValues("MountPoint", obj.GetNamespacedName()),
+		callHome:  obj.Spec.CallHome != nil,
+		stop:      make(chan struct{}),
+	}
+
+	supervisorsLock.Lock()
+	supervisors[obj.GetNamespacedName()] = s
+	supervisorsLock.Unlock()
+
+	go s.run()
+}
+
+// stopSupervisor stops supervising the session of the MountPoint. It must be called before closing
+// the session on purpose, so it isn't mistaken for a transport loss.
+func stopSupervisor(namespacedName string) {
+	supervisorsLock.Lock()
+	s := supervisors[namespacedName]
+	delete(supervisors, namespacedName)
+	supervisorsLock.Unlock()
+
+	if s != nil {
+		s.lock.Lock()
+		s.stopped = true
+		close(s.stop)
+		s.lock.Unlock()
+	}
+}
+
+func (s *supervisor) run() {
+	for {
+		// The transport is replaced upon reconnection
+		s.lock.Lock()
+		transport := s.transport
+		s.lock.Unlock()
+
+		select {
+		case <-s.stop:
+			return
+		case <-transport.lost:
+		}
+
+		s.log.Info(fmt.Sprintf("%s: NETCONF session lost: %s", s.key.Name, transport.lostErr))
+		s.disconnected(transport, transport.lostErr)
+
+		if s.callHome || !s.reconnect() {
+			return
+		}
+	}
+}
+
+// disconnected closes the lost session, and reports it in the MountPoint status.
+func (s *supervisor) disconnected(transport *supervisedTransport, reason error) {
+	namespacedName := s.key.String()
+	_ = s.r.sessions.Drop(namespacedName, transport)
+
+	now := metav1.Now()
+	obj, err := s.updateStatus(
+		func(obj *netconfv1.MountPoint) {
+			state := netconfv1.Disconnected
+			if s.callHome {
+				state = netconfv1.AwaitingCallHome
+			}
+			setNotConnected(obj, state, reason.Error())
+			obj.LastDisconnectedTime = &now
+			obj.ReconnectAttempts = 0
+			obj.NextReconnectTime = nil
+		},
+	)
+	if err == nil {
+		s.r.GetRecorder().Event(obj, "Warning", string(netconfv1.Disconnected), reason.Error())
+	}
+}
+
+// reconnect attempts to re-establish the session until it succeeds, or the supervisor is stopped.
+func (s *supervisor) reconnect() bool {
+	var attempts int32
+	var delay time.Duration
+	for {
+		obj := &netconfv1.MountPoint{}
+		err := s.r.GetClient().Get(context.Background(), s.key, obj)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return false
+			}
+			s.log.Error(err, "Failed to get MountPoint")
+		}
+
+		delay = nextReconnectDelay(obj, delay)
+		attempts++
+		next := metav1.NewTime(time.Now().Add(delay))
+		_, _ = s.updateStatus(
+			func(obj *netconfv1.MountPoint) {
+				setNotConnected(obj, netconfv1.Reconnecting, obj.DisconnectReason)
+				obj.ReconnectAttempts = attempts
+				obj.NextReconnectTime = &next
+			},
+		)
+
+		select {
+		case <-s.stop:
+			return false
+		case <-time.After(delay):
+		}
+
+		err = s.r.GetClient().Get(context.Background(), s.key, obj)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return false
+			}
+			continue
+		}
+		if obj.Spec.CallHome != nil {
+			// Switched to Call Home mode in the meantime
+			_, _ = s.updateStatus(
+				func(obj *netconfv1.MountPoint) {
+					setNotConnected(obj, netconfv1.AwaitingCallHome, obj.DisconnectReason)
+					obj.NextReconnectTime = nil
+				},
+			)
+			return false
+		}
+
+		session, transport, err := s.r.connect(obj, s.log)
+		if err != nil {
+			s.log.Info(fmt.Sprintf("%s: Reconnection attempt %d failed: %s", s.key.Name, attempts, err))
+			_, _ = s.updateStatus(
+				func(latest *netconfv1.MountPoint) {
+					latest.Conditions = obj.Conditions
+					setNotConnected(latest, netconfv1.Reconnecting, err.Error())
+				},
+			)
+			continue
+		}
+
+		s.lock.Lock()
+		if s.stopped {
+			// A new session was established in the meantime.
+			s.lock.Unlock()
+			_ = closeSession(session, obj.Spec.Timeout)
+			return false
+		}
+		_ = s.r.sessions.Put(s.key.String(), session, transport, obj.Spec.Timeout)
+		s.transport = transport
+		s.lock.Unlock()
+
+		s.log.Info(fmt.Sprintf("%s: Successfully reconnected.", s.key.Name))
+		connected := obj.MountPointStatus.DeepCopy()
+		latest, err := s.updateStatus(
+			func(latest *netconfv1.MountPoint) {
+				latest.MountPointStatus = *connected
+				setConnected(latest)
+			},
+		)
+		if err == nil {
+			s.r.GetRecorder().Event(
+				latest, "Normal", string(netconfv1.Connected),
+				fmt.Sprintf("NETCONF session re-established after %d attempt(s)", attempts),
+			)
+		}
+		resyncSubscriptions(s.r.GetClient(), obj)
+		return true
+	}
+}
+
+// updateStatus applies the mutation to the latest version of the MountPoint, and updates its status.
+func (s *supervisor) updateStatus(mutate func(obj *netconfv1.MountPoint)) (*netconfv1.MountPoint, error) {
+	obj, err := updateMountPointStatus(s.r.GetClient(), s.key, mutate)
+	if err != nil {
+		s.log.Error(err, "unable to update status")
+	}
+	return obj, err
+}
+
+// updateMountPointStatus applies the mutation to the latest version of the MountPoint, and updates its status,
+// retrying upon conflict.
+func updateMountPointStatus(
+	c client.Client, key types.NamespacedName, mutate func(obj *netconfv1.MountPoint),
+) (*netconfv1.MountPoint, error) {
+	var err error
+	for i := 0; i < 5; i++ {
+		obj := &netconfv1.MountPoint{}
+		err = c.Get(context.Background(), key, obj)
+		if err != nil {
+			return nil, err
+		}
+		mutate(obj)
+		err = c.Status().Update(context.Background(), obj)
+		if err == nil {
+			return obj, nil
+		}
+		if !apierrors.IsConflict(err) {
+			break
+		}
+	}
+	return nil, err
+}
+
+// nextReconnectDelay doubles the previous delay, within the bounds defined by the MountPoint.
+func nextReconnectDelay(obj *netconfv1.MountPoint, previous time.Duration) time.Duration {
+	initial := time.Duration(obj.Spec.ReconnectInitialDelay) * time.Second
+	if initial <= 0 {
+		initial = time.Second
+	}
+	max := time.Duration(obj.Spec.ReconnectMaxDelay) * time.Second
+	if max < initial {
+		max = initial
+	}
+
+	delay := previous * 2
+	if delay < initial {
+		delay = initial
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
+
+// setConnected reports the session as established in the MountPoint status.
+func setConnected(obj *netconfv1.MountPoint) {
+	now := metav1.Now()
+	obj.ConnectionState = netconfv1.Connected
+	obj.LastConnectedTime = &now
+	obj.DisconnectReason = ""
+	obj.ReconnectAttempts = 0
+	obj.NextReconnectTime = nil
+	setConditions(
+		obj, metav1.ConditionTrue, string(netconfv1.Connected), "NETCONF session established",
+		netconfv1.ConnectedCondition, netconfv1.ReadyCondition,
+	)
+}
+
+// setNotConnected reports the session as not established in the MountPoint status, along with the reason.
+func setNotConnected(obj *netconfv1.MountPoint, state netconfv1.ConnectionState, reason string) {
+	obj.ConnectionState = state
+	obj.DisconnectReason = reason
+	setConditions(
+		obj, metav1.ConditionFalse, string(state), reason, netconfv1.ConnectedCondition, netconfv1.ReadyCondition,
+	)
+}
+
+// resyncSubscriptions triggers the reconciliation of the CreateSubscription and EstablishSubscription
+// relying on the MountPoint, so they get registered against its new session.
+func resyncSubscriptions(c client.Client, obj *netconfv1.MountPoint) {
+	createSubscriptions := &netconfv1.CreateSubscriptionList{}
+	err := c.List(context.Background(), createSubscriptions, client.InNamespace(obj.Namespace))
+	if err == nil {
+		for i := range createSubscriptions.Items {
+			if createSubscriptions.Items[i].Spec.MountPoint == obj.Name {
+				sendSubscriptionEvent(createSubscriptionEvents, &createSubscriptions.Items[i])
+			}
+		}
+	}
+
+	establishSubscriptions := &netconfv1.EstablishSubscriptionList{}
+	err = c.List(context.Background(), establishSubscriptions, client.InNamespace(obj.Namespace))
+	if err == nil {
+		for i := range establishSubscriptions.Items {
+			if establishSubscriptions.Items[i].Spec.MountPoint == obj.Name {
+				sendSubscriptionEvent(establishSubscriptionEvents, &establishSubscriptions.Items[i])
+			}
+		}
+	}
+}
+
+// sendSubscriptionEvent triggers the reconciliation of the subscription, without blocking the caller when the
+// buffer of the channel is full, e.g. while the controllers aren't started yet.
+func sendSubscriptionEvent(events chan event.GenericEvent, obj client.Object) {
+	select {
+	case events <- event.GenericEvent{Object: obj}:
+	default:
+		logf.Log.WithName(mountPointControllerName).Info(
+			fmt.Sprintf(
+				"%s: Too many pending subscriptions, %s isn't registered against the new session until reconciled.",
+				obj.GetNamespace(), obj.GetName(),
+			),
+		)
+	}
+}
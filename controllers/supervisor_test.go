@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/crypto/ssh"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// testSSHServer is a NETCONF server over SSH, accepting any password. It sends its hello-message, then discards
+// the messages received. The connections accepted are handed over, so they can be dropped.
+type testSSHServer struct {
+	listener net.Listener
+	conns    chan net.Conn
+}
+
+func newTestSSHServer(t *testing.T) *testSSHServer {
+	t.Helper()
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(ssh.ConnMetadata, []byte) (*ssh.Permissions, error) { return nil, nil },
+	}
+	config.AddHostKey(newTestSigner(t))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &testSSHServer{listener: listener, conns: make(chan net.Conn, 10)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			s.conns <- conn
+			go s.serve(conn, config)
+		}
+	}()
+	return s
+}
+
+func (s *testSSHServer) serve(conn net.Conn, config *ssh.ServerConfig) {
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for request := range requests {
+				subsystem := request.Type == "subsystem"
+				_ = request.Reply(subsystem, nil)
+				if subsystem {
+					_, _ = io.WriteString(channel, testHello+endOfMessage)
+					go func() { _, _ = io.Copy(ioutil.Discard, channel) }()
+				}
+			}
+		}()
+	}
+}
+
+func (s *testSSHServer) close() {
+	_ = s.listener.Close()
+}
+
+func TestSupervisorReconnect(t *testing.T) {
+	server := newTestSSHServer(t)
+	defer server.close()
+	obj := &netconfv1.MountPoint{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "device"},
+		Spec: netconfv1.MountPointSpec{
+			Target: server.listener.Addr().String(), Username: "admin", Password: "admin",
+		},
+	}
+	key := obj.GetNamespacedName()
+	r := &MountPointReconciler{
+		ReconcilerBase: newTestReconcilerBase(&testClient{objects: map[string]client.Object{"device": obj}}),
+		sessions:       newSessionManager(),
+	}
+	session, transport, err := r.connect(obj.DeepCopy(), logr.Discard())
+	if err != nil {
+		t.Fatalf("connect() error = %v", err)
+	}
+	r.bind(obj, session, transport)
+	defer func() {
+		stopSupervisor(key)
+		_ = r.sessions.Remove(key)
+	}()
+	if !r.sessions.Healthy(key) {
+		t.Fatalf("Healthy() = false, once connected")
+	}
+
+	// The NETCONF server drops the connection
+	_ = (<-server.conns).Close()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		s, err := r.sessions.Acquire(key)
+		if err == nil {
+			r.sessions.Release(s)
+			if s.(*managedSession).session != session && r.sessions.Healthy(key) {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the session wasn't re-established")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	select {
+	case <-transport.closed:
+	default:
+		t.Errorf("the lost session wasn't closed")
+	}
+	events := r.GetRecorder().(*record.FakeRecorder).Events
+	for _, want := range []string{"Warning Disconnected", "Normal Connected"} {
+		select {
+		case event := <-events:
+			if !strings.HasPrefix(event, want) {
+				t.Errorf("recorded event %q, want %q", event, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Errorf("no event recorded, want %q", want)
+		}
+	}
+}
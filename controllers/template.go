@@ -0,0 +1,319 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"strconv"
+	"strings"
+	"text/template"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+//+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
+
+// objectFieldKinds are the kinds, beyond the ones of the operator, the parameters of the templates can be read from,
+// as the operator is granted the permission to get, list and watch them.
+var objectFieldKinds = []schema.GroupKind{{Kind: "ConfigMap"}, {Kind: "Secret"}, {Kind: "Service"}}
+
+// Indexes of the EditConfigs, by the objects the parameters of their template are read from
+const (
+	templateConfigMapRefsField = ".spec.parameters.configMapRefs"
+	templateSecretRefsField    = ".spec.parameters.secretRefs"
+	templateObjectRefsField    = ".spec.parameters.objectRefs"
+	// templateMountPointField indexes the templated EditConfigs by their MountPoint, as its labels and annotations
+	// are available to the template
+	templateMountPointField = ".spec.templateMountPoint"
+)
+
+// templateFuncs are the functions available to the templates, on top of the text/template builtins. None of them
+// reads the environment, the filesystem or the network.
+var templateFuncs = template.FuncMap{
+	"xml":        escapeXML,
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"trim":       strings.TrimSpace,
+	"trimPrefix": func(prefix string, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix string, s string) string { return strings.TrimSuffix(s, suffix) },
+	"replace":    func(old string, new string, s string) string { return strings.ReplaceAll(s, old, new) },
+	"split":      func(sep string, s string) []string { return strings.Split(s, sep) },
+	"join":       func(sep string, elems []string) string { return strings.Join(elems, sep) },
+	"contains":   func(substr string, s string) bool { return strings.Contains(s, substr) },
+	"hasPrefix":  func(prefix string, s string) bool { return strings.HasPrefix(s, prefix) },
+	"hasSuffix":  func(suffix string, s string) bool { return strings.HasSuffix(s, suffix) },
+	"default": func(def string, value string) string {
+		if value == "" {
+			return def
+		}
+		return value
+	},
+	"atoi": strconv.Atoi,
+	"add":  func(a int, b int) int { return a + b },
+	"sub":  func(a int, b int) int { return a - b },
+	"mul":  func(a int, b int) int { return a * b },
+}
+
+// templateData is the data the template is rendered with.
+type templateData struct {
+	Parameters map[string]string
+	MountPoint templateMountPoint
+}
+
+type templateMountPoint struct {
+	Name        string
+	Namespace   string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// payload returns the XML payload of the EditConfig, rendered from its template, or converted from JSON, if any,
+// along with its hash.
+func (r *EditConfigReconciler) payload(obj *netconfv1.EditConfig) (string, string, error) {
+	set := 0
+	for _, isSet := range []bool{obj.Spec.XML != "", obj.Spec.Template != "", obj.Spec.JSON != nil} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return "", "", fmt.Errorf("exactly one of xml, template and json must be set")
+	}
+
+	payload := obj.Spec.XML
+	var err error
+	switch {
+	case obj.Spec.Template != "":
+		payload, err = r.render(obj)
+	case obj.Spec.JSON != nil:
+		payload, err = jsonToXML(obj.Spec.JSON, r.namespaces(obj))
+	}
+	if err != nil {
+		return "", "", err
+	}
+	return payload, fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(payload))), nil
+}
+
+// namespaces returns the mapping of the YANG modules of the MountPoint of the EditConfig, which also identifies the
+// entries of the lists of its configuration.
+func (r *EditConfigReconciler) namespaces(obj *netconfv1.EditConfig) *yangNamespaces {
+	return namespacesFor(r.sessions, obj.GetMountPointNamespacedName(obj.Spec.MountPoint), obj.Spec.YANGMapping)
+}
+
+// render renders the template of the EditConfig, with the values of its parameters and its MountPoint.
+func (r *EditConfigReconciler) render(obj *netconfv1.EditConfig) (string, error) {
+	mountPoint := &netconfv1.MountPoint{}
+	err := r.GetClient().Get(
+		context.Background(), types.NamespacedName{Namespace: obj.Namespace, Name: obj.Spec.MountPoint}, mountPoint,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to read MountPoint %s: %w", obj.Spec.MountPoint, err)
+	}
+	data := templateData{
+		Parameters: make(map[string]string, len(obj.Spec.Parameters)),
+		MountPoint: templateMountPoint{
+			Name:        mountPoint.Name,
+			Namespace:   mountPoint.Namespace,
+			Labels:      mountPoint.Labels,
+			Annotations: mountPoint.Annotations,
+		},
+	}
+	for _, parameter := range obj.Spec.Parameters {
+		value, err := r.parameterValue(obj.Namespace, parameter)
+		if err != nil {
+			return "", fmt.Errorf("invalid parameter %s: %w", parameter.Name, err)
+		}
+		data.Parameters[parameter.Name] = value
+	}
+	return renderTemplate(obj.Name, obj.Spec.Template, data)
+}
+
+// renderTemplate renders the template with the data, and checks the result is well-formed XML.
+func renderTemplate(name string, text string, data templateData) (string, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to render the template: %w", err)
+	}
+	if _, err := parseXML(buf.String()); err != nil {
+		return "", fmt.Errorf("the rendered template isn't well-formed XML: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// parameterValue returns the value of the parameter, reading it from the ConfigMap, Secret or object it refers to.
+func (r *EditConfigReconciler) parameterValue(namespace string, parameter netconfv1.TemplateParameter) (string, error) {
+	from := parameter.ValueFrom
+	if from == nil {
+		return parameter.Value, nil
+	}
+	set := 0
+	for _, isSet := range []bool{
+		parameter.Value != "", from.ConfigMapKeyRef != nil, from.SecretKeyRef != nil, from.ObjectFieldRef != nil,
+	} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return "", fmt.Errorf("exactly one of value, configMapKeyRef, secretKeyRef and objectFieldRef must be set")
+	}
+
+	switch {
+	case from.ConfigMapKeyRef != nil:
+		ref := from.ConfigMapKeyRef
+		configMap := &corev1.ConfigMap{}
+		err := r.GetClient().Get(
+			context.Background(), types.NamespacedName{Namespace: namespace, Name: ref.Name}, configMap,
+		)
+		if apierrors.IsNotFound(err) && isOptional(ref.Optional) {
+			return "", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read ConfigMap %s: %w", ref.Name, err)
+		}
+		value, ok := configMap.Data[ref.Key]
+		if !ok && !isOptional(ref.Optional) {
+			return "", fmt.Errorf("ConfigMap %s has no %s key", ref.Name, ref.Key)
+		}
+		return value, nil
+	case from.SecretKeyRef != nil:
+		ref := from.SecretKeyRef
+		secret := &corev1.Secret{}
+		err := r.GetClient().Get(
+			context.Background(), types.NamespacedName{Namespace: namespace, Name: ref.Name}, secret,
+		)
+		if apierrors.IsNotFound(err) && isOptional(ref.Optional) {
+			return "", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read Secret %s: %w", ref.Name, err)
+		}
+		value, ok := secret.Data[ref.Key]
+		if !ok && !isOptional(ref.Optional) {
+			return "", fmt.Errorf("Secret %s has no %s key", ref.Name, ref.Key)
+		}
+		return string(value), nil
+	default:
+		return r.objectField(namespace, from.ObjectFieldRef)
+	}
+}
+
+// objectField reads the field of the object, and starts watching its kind, so that the template is re-rendered
+// whenever the object changes. Only the kinds the operator can list and watch are supported, as the informer of
+// another kind would never sync.
+func (r *EditConfigReconciler) objectField(namespace string, ref *netconfv1.ObjectFieldSelector) (string, error) {
+	gvk := schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind)
+	err := checkObjectFieldKind(gvk, r.GetScheme().Recognizes(gvk))
+	if err != nil {
+		return "", err
+	}
+	err = r.watch(gvk)
+	if err != nil {
+		return "", fmt.Errorf("failed to watch %s: %w", gvk, err)
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	err = r.GetClient().Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: ref.Name}, obj)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s %s: %w", ref.Kind, ref.Name, err)
+	}
+
+	fieldPath := ref.FieldPath
+	if !strings.HasPrefix(fieldPath, "{") {
+		fieldPath = "{" + fieldPath + "}"
+	}
+	path := jsonpath.New(ref.Name)
+	err = path.Parse(fieldPath)
+	if err != nil {
+		return "", fmt.Errorf("invalid field path %s: %w", ref.FieldPath, err)
+	}
+	var buf bytes.Buffer
+	err = path.Execute(&buf, obj.Object)
+	if err != nil {
+		return "", fmt.Errorf("failed to read field %s of %s %s: %w", ref.FieldPath, ref.Kind, ref.Name, err)
+	}
+	return buf.String(), nil
+}
+
+// checkObjectFieldKind returns an error unless the parameters can be read from the kind: one of the kinds of the
+// operator, or of objectFieldKinds, in a version known to the operator.
+func checkObjectFieldKind(gvk schema.GroupVersionKind, recognized bool) error {
+	allowed := gvk.Group == netconfv1.GroupVersion.Group
+	for _, kind := range objectFieldKinds {
+		allowed = allowed || gvk.GroupKind() == kind
+	}
+	if !allowed || !recognized {
+		return fmt.Errorf(
+			"objectFieldRef doesn't support %s: only the kinds of %s, ConfigMap, Secret and Service are",
+			gvk, netconfv1.GroupVersion.Group,
+		)
+	}
+	return nil
+}
+
+// watch starts watching the kind, once, enqueuing the EditConfigs whose parameters refer to the changed object.
+func (r *EditConfigReconciler) watch(gvk schema.GroupVersionKind) error {
+	r.watchesLock.Lock()
+	defer r.watchesLock.Unlock()
+	if r.watches[gvk.GroupKind()] {
+		return nil
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	err := r.controller.Watch(
+		&source.Kind{Type: obj},
+		handler.EnqueueRequestsFromMapFunc(editConfigsReferencing(r.GetClient(), templateObjectRefsField, objectRef)),
+	)
+	if err != nil {
+		return err
+	}
+	r.watches[gvk.GroupKind()] = true
+	return nil
+}
+
+// objectRef identifies the object within its namespace, regardless of the version of its kind.
+func objectRef(obj client.Object) string {
+	return obj.GetObjectKind().GroupVersionKind().GroupKind().String() + "/" + obj.GetName()
+}
+
+// parameterRefs returns the ConfigMaps, Secrets and objects, as identified by objectRef, the parameters of the
+// EditConfig are read from.
+func parameterRefs(obj *netconfv1.EditConfig) (configMaps []string, secrets []string, objects []string) {
+	for _, parameter := range obj.Spec.Parameters {
+		from := parameter.ValueFrom
+		switch {
+		case from == nil:
+		case from.ConfigMapKeyRef != nil:
+			configMaps = append(configMaps, from.ConfigMapKeyRef.Name)
+		case from.SecretKeyRef != nil:
+			secrets = append(secrets, from.SecretKeyRef.Name)
+		case from.ObjectFieldRef != nil:
+			ref := from.ObjectFieldRef
+			objects = append(
+				objects, schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind().String()+"/"+ref.Name,
+			)
+		}
+	}
+	return configMaps, secrets, objects
+}
+
+func isOptional(optional *bool) bool {
+	return optional != nil && *optional
+}
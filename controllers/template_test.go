@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"reflect"
+	"strings"
+	"testing"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	data := templateData{
+		Parameters: map[string]string{"vlan": "100", "description": "R&D <lab>", "mtu": "", "ports": "eth0,eth1"},
+		MountPoint: templateMountPoint{Name: "router1", Namespace: "lab", Labels: map[string]string{"site": "PAR"}},
+	}
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "parameters",
+			template: `<vlan><id>{{ .Parameters.vlan }}</id></vlan>`,
+			want:     `<vlan><id>100</id></vlan>`,
+		},
+		{
+			name:     "escaped value",
+			template: `<description>{{ xml .Parameters.description }}</description>`,
+			want:     `<description>R&amp;D &lt;lab&gt;</description>`,
+		},
+		{
+			name:     "MountPoint",
+			template: `<system><hostname>{{ .MountPoint.Name }}.{{ lower .MountPoint.Labels.site }}</hostname></system>`,
+			want:     `<system><hostname>router1.par</hostname></system>`,
+		},
+		{
+			name:     "default and arithmetic",
+			template: `<mtu>{{ default "1500" .Parameters.mtu }}</mtu><id>{{ add (atoi .Parameters.vlan) 1 }}</id>`,
+			want:     `<mtu>1500</mtu><id>101</id>`,
+		},
+		{
+			name:     "range",
+			template: `{{ range split "," .Parameters.ports }}<interface><name>{{ . }}</name></interface>{{ end }}`,
+			want:     `<interface><name>eth0</name></interface><interface><name>eth1</name></interface>`,
+		},
+		{
+			name:     "undefined parameter",
+			template: `<vlan>{{ .Parameters.undefined }}</vlan>`,
+			wantErr:  "failed to render the template",
+		},
+		{
+			name:     "invalid template",
+			template: `<vlan>{{ .Parameters.vlan </vlan>`,
+			wantErr:  "invalid template",
+		},
+		{
+			name:     "unknown function",
+			template: `<vlan>{{ env "HOME" }}</vlan>`,
+			wantErr:  "invalid template",
+		},
+		{
+			name:     "unescaped value",
+			template: `<description>{{ .Parameters.description }}</description>`,
+			wantErr:  "isn't well-formed XML",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderTemplate(tt.name, tt.template, data)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("renderTemplate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("renderTemplate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("renderTemplate() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckObjectFieldKind(t *testing.T) {
+	tests := []struct {
+		apiVersion string
+		kind       string
+		recognized bool
+		ok         bool
+	}{
+		{"netconf.openshift-telco.io/v1", "MountPoint", true, true},
+		{"v1", "ConfigMap", true, true},
+		{"v1", "Service", true, true},
+		{"v1", "Pod", true, false},
+		{"apps/v1", "Deployment", true, false},
+		{"example.com/v1", "Site", false, false},
+		{"netconf.openshift-telco.io/v2", "MountPoint", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.apiVersion+"/"+tt.kind, func(t *testing.T) {
+			gvk := schema.FromAPIVersionAndKind(tt.apiVersion, tt.kind)
+			if err := checkObjectFieldKind(gvk, tt.recognized); (err == nil) != tt.ok {
+				t.Errorf("checkObjectFieldKind(%s) = %v, want ok %v", gvk, err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParameterRefs(t *testing.T) {
+	obj := &netconfv1.EditConfig{
+		Spec: netconfv1.EditConfigSpec{
+			Parameters: []netconfv1.TemplateParameter{
+				{Name: "literal", Value: "1"},
+				{
+					Name: "vlan",
+					ValueFrom: &netconfv1.ParameterSource{
+						ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "vlans"}, Key: "vlan",
+						},
+					},
+				},
+				{
+					Name: "community",
+					ValueFrom: &netconfv1.ParameterSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "snmp"}, Key: "community",
+						},
+					},
+				},
+				{
+					Name: "site",
+					ValueFrom: &netconfv1.ParameterSource{
+						ObjectFieldRef: &netconfv1.ObjectFieldSelector{
+							APIVersion: "netconf.openshift-telco.io/v1", Kind: "MountPoint", Name: "router1",
+							FieldPath: ".metadata.labels.site",
+						},
+					},
+				},
+			},
+		},
+	}
+	configMaps, secrets, objects := parameterRefs(obj)
+	if !reflect.DeepEqual(configMaps, []string{"vlans"}) {
+		t.Errorf("configMaps = %v", configMaps)
+	}
+	if !reflect.DeepEqual(secrets, []string{"snmp"}) {
+		t.Errorf("secrets = %v", secrets)
+	}
+	if want := []string{"MountPoint.netconf.openshift-telco.io/router1"}; !reflect.DeepEqual(objects, want) {
+		t.Errorf("objects = %v, want %v", objects, want)
+	}
+}
@@ -0,0 +1,373 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/go-logr/logr"
+	"github.com/openshift-telco/go-netconf-client/netconf/message"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"strings"
+	"time"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// Capabilities of the :validate capability, either of them is required by a NetworkTransaction
+const (
+	validateCapability   = "urn:ietf:params:netconf:capability:validate:1.1"
+	validate10Capability = "urn:ietf:params:netconf:capability:validate:1.0"
+)
+
+// transactionDevice is a device of a NetworkTransaction, along with the session it is reached through.
+type transactionDevice struct {
+	spec    netconfv1.TransactionDevice
+	status  *netconfv1.TransactionDeviceStatus
+	session Session
+	// The datastores locked by the transaction, in locking order
+	locked []string
+}
+
+func transactionCompleted(phase netconfv1.NetworkTransactionPhase) bool {
+	return phase == netconfv1.CommittedTransactionPhase || phase == netconfv1.AbortedTransactionPhase ||
+		phase == netconfv1.InconsistentTransactionPhase
+}
+
+// acquire acquires the session of every device. The returned function releases them.
+func (r *NetworkTransactionReconciler) acquire(
+	obj *netconfv1.NetworkTransaction,
+) ([]*transactionDevice, func(), error) {
+	devices := make([]*transactionDevice, 0, len(obj.Spec.Devices))
+	release := func() {
+		for _, device := range devices {
+			r.sessions.Release(device.session)
+		}
+	}
+	for i := range obj.Spec.Devices {
+		device, err := r.acquireDevice(obj, i)
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+		devices = append(devices, device)
+	}
+	return devices, release, nil
+}
+
+// acquireReachable acquires the session of the devices still reachable, skipping the others. The returned
+// function releases them.
+func (r *NetworkTransactionReconciler) acquireReachable(
+	obj *netconfv1.NetworkTransaction,
+) ([]*transactionDevice, func()) {
+	var devices []*transactionDevice
+	for i := range obj.Spec.Devices {
+		if device, err := r.acquireDevice(obj, i); err == nil {
+			devices = append(devices, device)
+		}
+	}
+	return devices, func() {
+		for _, device := range devices {
+			r.sessions.Release(device.session)
+		}
+	}
+}
+
+func (r *NetworkTransactionReconciler) acquireDevice(
+	obj *netconfv1.NetworkTransaction, i int,
+) (*transactionDevice, error) {
+	device := obj.Spec.Devices[i]
+	exists := CheckMountPointExists(
+		r.ReconcilerBase, r.sessions, types.NamespacedName{Namespace: obj.Namespace, Name: device.MountPoint},
+	)
+	if !exists {
+		return nil, mountPointUnavailable(device.MountPoint)
+	}
+	s, err := r.sessions.Acquire(obj.GetMountPointNamespacedName(device.MountPoint))
+	if err != nil {
+		return nil, mountPointUnavailable(device.MountPoint)
+	}
+	return &transactionDevice{spec: device, status: &obj.Devices[i], session: s}, nil
+}
+
+// transact prepares the changes on every device, then commits them using a confirmed commit, and confirms the
+// commits. Once a device failed, the changes are discarded, or the commits cancelled, on every device.
+func (r *NetworkTransactionReconciler) transact(
+	obj *netconfv1.NetworkTransaction, devices []*transactionDevice, log logr.Logger,
+) error {
+	for _, device := range devices {
+		err := r.prepare(obj, device)
+		if err != nil {
+			return r.abort(obj, devices, device, err, log)
+		}
+		device.status.Phase = netconfv1.PreparedDevicePhase
+	}
+	log.Info(fmt.Sprintf("NetworkTransaction %s prepared on every device.", obj.Name))
+
+	obj.PersistID = fmt.Sprintf("%s-%d", obj.UID, obj.Generation)
+	deadline := metav1.NewTime(time.Now().Add(time.Duration(obj.Spec.ConfirmTimeout) * time.Second))
+	for _, device := range devices {
+		err := transactionRPC(
+			obj, device, fmt.Sprintf(
+				"<commit><confirmed/><confirm-timeout>%d</confirm-timeout><persist>%s</persist></commit>",
+				obj.Spec.ConfirmTimeout, escapeXML(obj.PersistID),
+			),
+		)
+		if err != nil {
+			return r.abort(obj, devices, device, fmt.Errorf("failed to send the confirmed commit: %w", err), log)
+		}
+		device.status.Phase = netconfv1.ConfirmingDevicePhase
+	}
+	obj.Phase = netconfv1.ConfirmingTransactionPhase
+	obj.ConfirmDeadline = &deadline
+	return r.confirm(obj, devices, log)
+}
+
+// prepare locks the running and candidate datastores of the device, pushes the changes to the candidate datastore
+// and validates it.
+func (r *NetworkTransactionReconciler) prepare(obj *netconfv1.NetworkTransaction, device *transactionDevice) error {
+	capabilities := r.sessions.Capabilities(obj.GetMountPointNamespacedName(device.spec.MountPoint))
+	for _, capability := range []string{candidateCapability, confirmedCommitCapability} {
+		if !hasCapability(capabilities, capability) {
+			return fmt.Errorf("the NETCONF server doesn't support the %s capability", capability)
+		}
+	}
+	if !hasCapability(capabilities, validateCapability) && !hasCapability(capabilities, validate10Capability) {
+		return fmt.Errorf("the NETCONF server doesn't support the %s capability", validateCapability)
+	}
+	if _, err := parseXML(device.spec.XML); err != nil {
+		return fmt.Errorf("invalid XML payload: %w", err)
+	}
+
+	for _, datastore := range []string{message.DatastoreRunning, message.DatastoreCandidate} {
+		err := transactionRPC(obj, device, "<lock><target><"+datastore+"/></target></lock>")
+		if err != nil {
+			return fmt.Errorf("failed to lock datastore %s: %w", datastore, err)
+		}
+		device.locked = append(device.locked, datastore)
+	}
+
+	operation := device.spec.Operation
+	if operation == "" {
+		operation = "merge"
+	}
+	err := transactionRPC(
+		obj, device, fmt.Sprintf(
+			"<edit-config><target><candidate/></target><default-operation>%s</default-operation>"+
+				"<config>%s</config></edit-config>", operation, device.spec.XML,
+		),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to edit datastore candidate: %w", err)
+	}
+	err = transactionRPC(obj, device, "<validate><source><candidate/></source></validate>")
+	if err != nil {
+		return fmt.Errorf("failed to validate datastore candidate: %w", err)
+	}
+	return nil
+}
+
+// confirm sends the confirming commit to the devices whose commit isn't confirmed yet. Failures are retried upon
+// the next reconciliation, until the confirm timeout expires.
+func (r *NetworkTransactionReconciler) confirm(
+	obj *netconfv1.NetworkTransaction, devices []*transactionDevice, log logr.Logger,
+) error {
+	var failed []string
+	var failure error
+	for _, device := range devices {
+		if device.status.Phase != netconfv1.ConfirmingDevicePhase {
+			continue
+		}
+		err := transactionRPC(
+			obj, device, fmt.Sprintf("<commit><persist-id>%s</persist-id></commit>", escapeXML(obj.PersistID)),
+		)
+		if err != nil {
+			device.status.Message = fmt.Sprintf("Failed to confirm the commit: %s", err)
+			failed = append(failed, device.spec.MountPoint)
+			failure = err
+			continue
+		}
+		device.status.Phase = netconfv1.CommittedDevicePhase
+		device.status.Message = ""
+	}
+	if len(failed) != 0 {
+		log.Info(fmt.Sprintf("Failed to confirm NetworkTransaction %s on %s.", obj.Name, strings.Join(failed, ", ")))
+		err := fmt.Errorf(
+			"failed to confirm the commit on %s, retried until %s: %w", strings.Join(failed, ", "),
+			obj.ConfirmDeadline.UTC().Format(time.RFC3339), failure,
+		)
+		setConditions(
+			obj, metav1.ConditionFalse, netconfv1.ConfirmationPendingReason, err.Error(),
+			netconfv1.CommittedCondition, netconfv1.ReadyCondition,
+		)
+		return err
+	}
+
+	for _, device := range devices {
+		unlockDevice(obj, device, log)
+	}
+	log.Info(fmt.Sprintf("Successfully committed NetworkTransaction %s on every device.", obj.Name))
+	completeTransaction(obj, netconfv1.CommittedTransactionPhase)
+	setSucceeded(obj, netconfv1.CommittedCondition)
+	return nil
+}
+
+// manageConfirmation retries the confirming commits, until the confirm timeout expires. The transaction is then
+// either aborted, when no commit was confirmed, or inconsistent.
+func (r *NetworkTransactionReconciler) manageConfirmation(obj *netconfv1.NetworkTransaction, log logr.Logger) error {
+	devices, release, err := r.acquire(obj)
+	expired := time.Now().After(obj.ConfirmDeadline.Time)
+	if err != nil && expired {
+		// The devices still reachable are unlocked nonetheless
+		devices, release = r.acquireReachable(obj)
+		err = nil
+	}
+	if err == nil {
+		defer release()
+		for _, device := range devices {
+			// The datastores are locked from the prepare phase, unless the sessions were re-established since
+			device.locked = []string{message.DatastoreRunning, message.DatastoreCandidate}
+		}
+	}
+
+	if expired {
+		r.expire(obj, devices, log)
+		return nil
+	}
+	if err != nil {
+		setConditions(
+			obj, metav1.ConditionFalse, netconfv1.ConfirmationPendingReason, err.Error(),
+			netconfv1.CommittedCondition, netconfv1.ReadyCondition,
+		)
+		return err
+	}
+	return r.confirm(obj, devices, log)
+}
+
+// expire reports the commits that weren't confirmed in time as rolled back by the NETCONF servers.
+func (r *NetworkTransactionReconciler) expire(
+	obj *netconfv1.NetworkTransaction, devices []*transactionDevice, log logr.Logger,
+) {
+	var rolledBack []string
+	committed := false
+	for i := range obj.Devices {
+		switch obj.Devices[i].Phase {
+		case netconfv1.ConfirmingDevicePhase:
+			obj.Devices[i].Phase = netconfv1.RolledBackDevicePhase
+			rolledBack = append(rolledBack, obj.Devices[i].MountPoint)
+		case netconfv1.CommittedDevicePhase:
+			committed = true
+		}
+	}
+	for _, device := range devices {
+		unlockDevice(obj, device, log)
+	}
+
+	summary := fmt.Sprintf(
+		"The confirm timeout expired, the commit was rolled back on %s", strings.Join(rolledBack, ", "),
+	)
+	phase := netconfv1.AbortedTransactionPhase
+	if committed {
+		phase = netconfv1.InconsistentTransactionPhase
+		summary += ", while it was confirmed on the other devices"
+	}
+	log.Info(fmt.Sprintf("NetworkTransaction %s: %s.", obj.Name, summary))
+	r.GetRecorder().Event(obj, "Warning", netconfv1.RolledBackReason, summary)
+	completeTransaction(obj, phase)
+	setConditions(
+		obj, metav1.ConditionFalse, netconfv1.RolledBackReason, summary, netconfv1.CommittedCondition,
+		netconfv1.ReadyCondition,
+	)
+}
+
+// abort reverts the transaction once it failed on a device: the confirmed commits are cancelled, the changes of
+// the candidate datastores discarded, and the datastores unlocked.
+func (r *NetworkTransactionReconciler) abort(
+	obj *netconfv1.NetworkTransaction, devices []*transactionDevice, failed *transactionDevice, err error,
+	log logr.Logger,
+) error {
+	failed.status.Phase = netconfv1.FailedDevicePhase
+	failed.status.Message = err.Error()
+	err = fmt.Errorf("MountPoint %s: %w", failed.spec.MountPoint, err)
+	log.Info(fmt.Sprintf("Abort NetworkTransaction %s: %s", obj.Name, err))
+
+	for _, device := range devices {
+		switch {
+		case device.status.Phase == netconfv1.ConfirmingDevicePhase:
+			cancelErr := transactionRPC(
+				obj, device,
+				fmt.Sprintf("<cancel-commit><persist-id>%s</persist-id></cancel-commit>", escapeXML(obj.PersistID)),
+			)
+			if cancelErr != nil {
+				device.status.Phase = netconfv1.AwaitingRollbackDevicePhase
+				device.status.Message = fmt.Sprintf(
+					"Failed to cancel the commit, rolled back once the confirm timeout expires: %s", cancelErr,
+				)
+				break
+			}
+			device.status.Phase = netconfv1.CancelledDevicePhase
+		case containsString(device.locked, message.DatastoreCandidate):
+			// Only the changes of a candidate datastore locked by the transaction are its own
+			discardErr := transactionRPC(obj, device, discardChangesRPC)
+			if discardErr != nil {
+				log.Info(
+					fmt.Sprintf(
+						"%s: Failed to discard changes of NetworkTransaction %s: %s", device.spec.MountPoint, obj.Name,
+						discardErr,
+					),
+				)
+				break
+			}
+			if device.status.Phase == netconfv1.PreparedDevicePhase {
+				device.status.Phase = netconfv1.DiscardedDevicePhase
+			}
+		}
+		unlockDevice(obj, device, log)
+	}
+
+	r.GetRecorder().Event(obj, "Warning", conditionReason(err), err.Error())
+	completeTransaction(obj, netconfv1.AbortedTransactionPhase)
+	setFailed(obj, err, netconfv1.CommittedCondition)
+	return err
+}
+
+// unlockDevice unlocks the datastores locked by the transaction, in reverse order.
+func unlockDevice(obj *netconfv1.NetworkTransaction, device *transactionDevice, log logr.Logger) {
+	for i := len(device.locked) - 1; i >= 0; i-- {
+		datastore := device.locked[i]
+		reply, err := device.session.SyncRPC(
+			message.NewRPC("<unlock><target><"+datastore+"/></target></unlock>"), obj.Spec.Timeout,
+		)
+		if err = checkReply(reply, err); err != nil {
+			log.Info(
+				fmt.Sprintf(
+					"%s: Failed to unlock datastore %s for NetworkTransaction %s: %s", device.spec.MountPoint,
+					datastore, obj.Name, err,
+				),
+			)
+		}
+	}
+	device.locked = nil
+}
+
+// transactionRPC sends the RPC to the device. Upon failure, the rpc-errors are reported in the status of the
+// device, and the reply in the status of the transaction.
+func transactionRPC(obj *netconfv1.NetworkTransaction, device *transactionDevice, rpc string) error {
+	reply, err := device.session.SyncRPC(message.NewRPC(rpc), obj.Spec.Timeout)
+	err = checkReply(reply, err)
+	if err == nil {
+		return nil
+	}
+	setReplyStatus(&obj.RPCStatus, reply, err)
+	device.status.RPCErrors = nil
+	if reply != nil {
+		for i := range reply.Errors {
+			device.status.RPCErrors = append(device.status.RPCErrors, rpcError(reply.Errors[i]))
+		}
+	}
+	return err
+}
+
+func completeTransaction(obj *netconfv1.NetworkTransaction, phase netconfv1.NetworkTransactionPhase) {
+	now := metav1.Now()
+	obj.Phase = phase
+	obj.CompletionTime = &now
+}
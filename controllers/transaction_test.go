@@ -0,0 +1,305 @@
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// testSessions is a SessionProvider holding a testSession per MountPoint. A MountPoint without a session is
+// unavailable.
+type testSessions map[string]*testSession
+
+func (p testSessions) Acquire(mountPoint string) (Session, error) {
+	s, ok := p[mountPoint]
+	if !ok {
+		return nil, fmt.Errorf("no session for MountPoint %s", mountPoint)
+	}
+	return s, nil
+}
+
+func (p testSessions) Release(Session) {}
+
+func (p testSessions) Healthy(mountPoint string) bool {
+	_, ok := p[mountPoint]
+	return ok
+}
+
+func (p testSessions) Capabilities(mountPoint string) []string {
+	if s, ok := p[mountPoint]; ok {
+		return s.capabilities
+	}
+	return nil
+}
+
+// newTestTransaction returns a NetworkTransaction over a device per set of error-tags, along with its reconciler.
+func newTestTransaction(
+	errorTags ...map[string][]string,
+) (*NetworkTransactionReconciler, *netconfv1.NetworkTransaction, testSessions) {
+	c := &testClient{objects: map[string]client.Object{}}
+	sessions := testSessions{}
+	obj := &netconfv1.NetworkTransaction{
+		ObjectMeta: metav1.ObjectMeta{Name: "transaction", Generation: 1, UID: "uid"},
+		Spec:       netconfv1.NetworkTransactionSpec{ConfirmTimeout: 120},
+	}
+	for i, tags := range errorTags {
+		name := fmt.Sprintf("device-%d", i)
+		c.objects[name] = &netconfv1.MountPoint{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		s := newTestSession(tags)
+		s.capabilities = []string{candidateCapability, confirmedCommitCapability, validateCapability}
+		sessions[types.NamespacedName{Name: name}.String()] = s
+		obj.Spec.Devices = append(
+			obj.Spec.Devices, netconfv1.TransactionDevice{MountPoint: name, XML: interfaces("<name>eth0</name>")},
+		)
+	}
+	r := &NetworkTransactionReconciler{ReconcilerBase: newTestReconcilerBase(c), sessions: sessions}
+	return r, obj, sessions
+}
+
+// checkTransaction checks the RPCs received, the phase and the locks of every device.
+func checkTransaction(
+	t *testing.T, obj *netconfv1.NetworkTransaction, sessions testSessions, want [][]string,
+	wantPhases []netconfv1.TransactionDevicePhase, wantLocked []bool,
+) {
+	t.Helper()
+	for i, device := range obj.Devices {
+		s := sessions[types.NamespacedName{Name: device.MountPoint}.String()]
+		if !reflect.DeepEqual(s.operations, want[i]) {
+			t.Errorf("%s received %q, want %q", device.MountPoint, s.operations, want[i])
+		}
+		if device.Phase != wantPhases[i] {
+			t.Errorf("%s phase = %q, want %q", device.MountPoint, device.Phase, wantPhases[i])
+		}
+		if locked := len(s.locked) != 0; locked != (i < len(wantLocked) && wantLocked[i]) {
+			t.Errorf("%s left locked %v, want locked %v", device.MountPoint, s.locked, !locked)
+		}
+	}
+}
+
+func TestTransact(t *testing.T) {
+	prepared := []string{"lock", "lock", "edit-config", "validate"}
+	ops := func(operations ...[]string) []string {
+		var all []string
+		for _, o := range operations {
+			all = append(all, o...)
+		}
+		return all
+	}
+	confirmed := []string{"commit", "commit"}
+	unlocked := []string{"unlock", "unlock"}
+	discarded := []string{"discard-changes", "unlock", "unlock"}
+
+	tests := []struct {
+		name       string
+		errorTags  []map[string][]string
+		wantPhase  netconfv1.NetworkTransactionPhase
+		want       [][]string
+		wantPhases []netconfv1.TransactionDevicePhase
+		wantErr    bool
+		// Whether each device is left locked, until the commits are confirmed
+		wantLocked []bool
+	}{
+		{
+			name:      "committed",
+			errorTags: []map[string][]string{nil, nil, nil},
+			wantPhase: netconfv1.CommittedTransactionPhase,
+			want: [][]string{
+				ops(prepared, confirmed, unlocked), ops(prepared, confirmed, unlocked), ops(prepared, confirmed, unlocked),
+			},
+			wantPhases: []netconfv1.TransactionDevicePhase{
+				netconfv1.CommittedDevicePhase, netconfv1.CommittedDevicePhase, netconfv1.CommittedDevicePhase,
+			},
+		},
+		{
+			name:      "edit failed on the last device",
+			errorTags: []map[string][]string{nil, nil, {"edit-config": {"invalid-value"}}},
+			wantPhase: netconfv1.AbortedTransactionPhase,
+			want: [][]string{
+				ops(prepared, discarded), ops(prepared, discarded),
+				ops([]string{"lock", "lock", "edit-config"}, discarded),
+			},
+			wantPhases: []netconfv1.TransactionDevicePhase{
+				netconfv1.DiscardedDevicePhase, netconfv1.DiscardedDevicePhase, netconfv1.FailedDevicePhase,
+			},
+			wantErr: true,
+		},
+		{
+			name:      "lock denied on the second device",
+			errorTags: []map[string][]string{nil, {"lock": {"lock-denied"}}, nil},
+			wantPhase: netconfv1.AbortedTransactionPhase,
+			want:      [][]string{ops(prepared, discarded), {"lock"}, nil},
+			wantPhases: []netconfv1.TransactionDevicePhase{
+				netconfv1.DiscardedDevicePhase, netconfv1.FailedDevicePhase, netconfv1.PendingDevicePhase,
+			},
+			wantErr: true,
+		},
+		{
+			name:      "validation failed on the second device",
+			errorTags: []map[string][]string{nil, {"validate": {"operation-failed"}}, nil},
+			wantPhase: netconfv1.AbortedTransactionPhase,
+			want:      [][]string{ops(prepared, discarded), ops(prepared, discarded), nil},
+			wantPhases: []netconfv1.TransactionDevicePhase{
+				netconfv1.DiscardedDevicePhase, netconfv1.FailedDevicePhase, netconfv1.PendingDevicePhase,
+			},
+			wantErr: true,
+		},
+		{
+			name:      "confirmed commit failed on the second device",
+			errorTags: []map[string][]string{nil, {"commit": {"operation-failed"}}, nil},
+			wantPhase: netconfv1.AbortedTransactionPhase,
+			want: [][]string{
+				ops(prepared, []string{"commit", "cancel-commit"}, unlocked),
+				ops(prepared, []string{"commit"}, discarded), ops(prepared, discarded),
+			},
+			wantPhases: []netconfv1.TransactionDevicePhase{
+				netconfv1.CancelledDevicePhase, netconfv1.FailedDevicePhase, netconfv1.DiscardedDevicePhase,
+			},
+			wantErr: true,
+		},
+		{
+			name: "cancel-commit failed",
+			errorTags: []map[string][]string{
+				{"cancel-commit": {"operation-failed"}}, {"commit": {"operation-failed"}}, nil,
+			},
+			wantPhase: netconfv1.AbortedTransactionPhase,
+			want: [][]string{
+				ops(prepared, []string{"commit", "cancel-commit"}, unlocked),
+				ops(prepared, []string{"commit"}, discarded), ops(prepared, discarded),
+			},
+			wantPhases: []netconfv1.TransactionDevicePhase{
+				netconfv1.AwaitingRollbackDevicePhase, netconfv1.FailedDevicePhase, netconfv1.DiscardedDevicePhase,
+			},
+			wantErr: true,
+		},
+		{
+			name:      "confirming commit failed on the second device",
+			errorTags: []map[string][]string{nil, {"commit": {"", "operation-failed"}}, nil},
+			wantPhase: netconfv1.ConfirmingTransactionPhase,
+			want:      [][]string{ops(prepared, confirmed), ops(prepared, confirmed), ops(prepared, confirmed)},
+			wantPhases: []netconfv1.TransactionDevicePhase{
+				netconfv1.CommittedDevicePhase, netconfv1.ConfirmingDevicePhase, netconfv1.CommittedDevicePhase,
+			},
+			wantErr:    true,
+			wantLocked: []bool{true, true, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, obj, sessions := newTestTransaction(tt.errorTags...)
+
+			err := r.manageOperatorLogic(obj, logr.Discard())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("manageOperatorLogic() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if obj.Phase != tt.wantPhase {
+				t.Errorf("manageOperatorLogic() phase = %q, want %q", obj.Phase, tt.wantPhase)
+			}
+			checkTransaction(t, obj, sessions, tt.want, tt.wantPhases, tt.wantLocked)
+		})
+	}
+}
+
+func TestManageConfirmation(t *testing.T) {
+	tests := []struct {
+		name string
+		// The error-tags of the confirming commits, per device
+		errorTags []map[string][]string
+		// Whether the confirm timeout expired before the next reconciliation
+		expired bool
+		// The device whose session is lost before the next reconciliation, if any
+		lost       string
+		wantPhase  netconfv1.NetworkTransactionPhase
+		want       [][]string
+		wantPhases []netconfv1.TransactionDevicePhase
+		wantErr    bool
+		wantLocked []bool
+	}{
+		{
+			name:       "confirmed upon retry",
+			errorTags:  []map[string][]string{nil, {"commit": {"", "operation-failed"}}},
+			wantPhase:  netconfv1.CommittedTransactionPhase,
+			want:       [][]string{{"unlock", "unlock"}, {"commit", "unlock", "unlock"}},
+			wantPhases: []netconfv1.TransactionDevicePhase{netconfv1.CommittedDevicePhase, netconfv1.CommittedDevicePhase},
+		},
+		{
+			name:      "confirmation failed again",
+			errorTags: []map[string][]string{nil, {"commit": {"", "operation-failed", "operation-failed"}}},
+			wantPhase: netconfv1.ConfirmingTransactionPhase,
+			want:      [][]string{nil, {"commit"}},
+			wantPhases: []netconfv1.TransactionDevicePhase{
+				netconfv1.CommittedDevicePhase, netconfv1.ConfirmingDevicePhase,
+			},
+			wantErr:    true,
+			wantLocked: []bool{true, true},
+		},
+		{
+			name: "expired, not confirmed on any device",
+			errorTags: []map[string][]string{
+				{"commit": {"", "operation-failed"}}, {"commit": {"", "operation-failed"}},
+			},
+			expired:    true,
+			wantPhase:  netconfv1.AbortedTransactionPhase,
+			want:       [][]string{{"unlock", "unlock"}, {"unlock", "unlock"}},
+			wantPhases: []netconfv1.TransactionDevicePhase{netconfv1.RolledBackDevicePhase, netconfv1.RolledBackDevicePhase},
+		},
+		{
+			name:       "expired, confirmed on the other devices",
+			errorTags:  []map[string][]string{nil, {"commit": {"", "operation-failed"}}},
+			expired:    true,
+			wantPhase:  netconfv1.InconsistentTransactionPhase,
+			want:       [][]string{{"unlock", "unlock"}, {"unlock", "unlock"}},
+			wantPhases: []netconfv1.TransactionDevicePhase{netconfv1.CommittedDevicePhase, netconfv1.RolledBackDevicePhase},
+		},
+		{
+			name:       "expired, session lost",
+			errorTags:  []map[string][]string{nil, {"commit": {"", "operation-failed"}}},
+			expired:    true,
+			lost:       "device-1",
+			wantPhase:  netconfv1.InconsistentTransactionPhase,
+			want:       [][]string{{"unlock", "unlock"}, nil},
+			wantPhases: []netconfv1.TransactionDevicePhase{netconfv1.CommittedDevicePhase, netconfv1.RolledBackDevicePhase},
+			// The NETCONF server releases the locks of the lost session
+			wantLocked: []bool{false, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, obj, sessions := newTestTransaction(tt.errorTags...)
+			if err := r.manageOperatorLogic(obj, logr.Discard()); err == nil {
+				t.Fatalf("manageOperatorLogic() confirmed the transaction, want a confirming commit failed")
+			}
+			for _, s := range sessions {
+				s.operations = nil
+			}
+			lost := types.NamespacedName{Name: tt.lost}.String()
+			lostSession := sessions[lost]
+			if tt.lost != "" {
+				delete(sessions, lost)
+			}
+			if tt.expired {
+				deadline := metav1.NewTime(time.Now().Add(-time.Second))
+				obj.ConfirmDeadline = &deadline
+			}
+
+			err := r.manageOperatorLogic(obj, logr.Discard())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("manageOperatorLogic() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if obj.Phase != tt.wantPhase {
+				t.Errorf("manageOperatorLogic() phase = %q, want %q", obj.Phase, tt.wantPhase)
+			}
+			if tt.lost != "" {
+				sessions[lost] = lostSession
+			}
+			checkTransaction(t, obj, sessions, tt.want, tt.wantPhases, tt.wantLocked)
+		})
+	}
+}
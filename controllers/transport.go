@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NETCONF framing, as per RFC 6242
+const (
+	endOfMessage = "]]>]]>"
+	endOfChunks  = "\n##\n"
+	// maxMessageSize bounds the size of a received message, whatever its framing, so that a bogus or
+	// malicious peer can't exhaust the memory of the operator
+	maxMessageSize = 256 << 20
+)
+
+// errMessageTooLarge is returned once a received message exceeds maxMessageSize.
+var errMessageTooLarge = fmt.Errorf("message exceeds %d bytes", maxMessageSize)
+
+// framedTransport implements netconf.Transport over a byte stream, e.g. a TLS connection or an SSH channel,
+// supporting both the end-of-message and the chunked framing.
+type framedTransport struct {
+	reader *bufio.Reader
+	writer io.Writer
+	closer func() error
+
+	lock    sync.Mutex
+	version string
+}
+
+func newFramedTransport(r io.Reader, w io.Writer, closer func() error) *framedTransport {
+	return &framedTransport{reader: bufio.NewReader(r), writer: w, closer: closer}
+}
+
+// newConnTransport returns a transport over the connection. When a timeout is set, it bounds every write.
+func newConnTransport(conn net.Conn, timeout time.Duration) *framedTransport {
+	var w io.Writer = conn
+	if timeout > 0 {
+		w = &deadlineWriter{conn: conn, timeout: timeout}
+	}
+	return newFramedTransport(conn, w, conn.Close)
+}
+
+type deadlineWriter struct {
+	conn    net.Conn
+	timeout time.Duration
+}
+
+func (w *deadlineWriter) Write(b []byte) (int, error) {
+	_ = w.conn.SetWriteDeadline(time.Now().Add(w.timeout))
+	return w.conn.Write(b)
+}
+
+func (t *framedTransport) SetVersion(version string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.version = version
+}
+
+func (t *framedTransport) chunked() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.version == "v1.1"
+}
+
+func (t *framedTransport) Send(data []byte) error {
+	var framed []byte
+	if t.chunked() {
+		framed = append([]byte(fmt.Sprintf("\n#%d\n", len(data))), data...)
+		framed = append(framed, endOfChunks...)
+	} else {
+		framed = append(append(framed, data...), endOfMessage...)
+	}
+	_, err := t.writer.Write(framed)
+	return err
+}
+
+func (t *framedTransport) Receive() ([]byte, error) {
+	if t.chunked() {
+		return t.receiveChunked()
+	}
+
+	var msg []byte
+	for {
+		b, err := t.reader.ReadSlice('>')
+		msg = append(msg, b...)
+		if len(msg) > maxMessageSize+len(endOfMessage) {
+			return nil, errMessageTooLarge
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if bytes.HasSuffix(msg, []byte(endOfMessage)) {
+			return msg[:len(msg)-len(endOfMessage)], nil
+		}
+	}
+}
+
+// receiveChunked reads a message using the chunked framing, i.e. a sequence of `\n#<size>\n<data>`
+// chunks, terminated by `\n##\n`. The chunks are streamed into the message, rather than allocated upon their
+// announced size, and the message is bounded by maxMessageSize.
+func (t *framedTransport) receiveChunked() ([]byte, error) {
+	var msg bytes.Buffer
+	for {
+		header, err := t.readChunkHeader()
+		if err != nil {
+			return nil, err
+		}
+		if header == "\n" {
+			// Start of the chunk header
+			header, err = t.readChunkHeader()
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !strings.HasPrefix(header, "#") {
+			return nil, fmt.Errorf("invalid chunk header %q", header)
+		}
+		size := strings.TrimSuffix(header[1:], "\n")
+		if size == "#" {
+			return msg.Bytes(), nil
+		}
+		n, err := strconv.ParseUint(size, 10, 32)
+		if err != nil || n == 0 {
+			return nil, fmt.Errorf("invalid chunk size %q", size)
+		}
+		if n > uint64(maxMessageSize-msg.Len()) {
+			return nil, errMessageTooLarge
+		}
+		_, err = io.CopyN(&msg, t.reader, int64(n))
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readChunkHeader reads a line of a chunk header, which holds at most a 10 digits size.
+func (t *framedTransport) readChunkHeader() (string, error) {
+	line, err := t.reader.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		return "", fmt.Errorf("invalid chunk header %q", line[:16])
+	}
+	return string(line), err
+}
+
+func (t *framedTransport) Close() error {
+	return t.closer()
+}
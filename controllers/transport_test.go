@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func newTestTransport(input string, version string) (*framedTransport, *bytes.Buffer) {
+	var out bytes.Buffer
+	t := newFramedTransport(strings.NewReader(input), &out, func() error { return nil })
+	t.SetVersion(version)
+	return t, &out
+}
+
+func TestReceiveChunked(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{name: "single chunk", input: "\n#5\nhello\n##\n", want: "hello"},
+		{name: "several chunks", input: "\n#4\n<rpc\n#1\n>\n#6\n</rpc>\n##\n", want: "<rpc></rpc>"},
+		{name: "chunk holding newlines", input: "\n#3\na\nb\n##\n", want: "a\nb"},
+		{name: "several messages", input: "\n#2\nok\n##\n\n#3\nnok\n##\n", want: "ok"},
+		{name: "zero size", input: "\n#0\n\n##\n", wantErr: errors.New(`invalid chunk size "0"`)},
+		{name: "invalid size", input: "\n#abc\nabc\n##\n", wantErr: errors.New(`invalid chunk size "abc"`)},
+		{name: "invalid header", input: "\nhello\n##\n", wantErr: errors.New(`invalid chunk header "hello\n"`)},
+		{name: "size beyond 32 bits", input: "\n#4294967296\n", wantErr: errors.New(`invalid chunk size "4294967296"`)},
+		{name: "chunk beyond the maximum message size", input: "\n#4294967295\n", wantErr: errMessageTooLarge},
+		{name: "truncated chunk", input: "\n#10\nhello", wantErr: io.ErrUnexpectedEOF},
+		{
+			name:    "header without newline",
+			input:   "\n#" + strings.Repeat("1", 8192),
+			wantErr: errors.New(`invalid chunk header "#111111111111111"`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, _ := newTestTransport(tt.input, "v1.1")
+			got, err := transport.Receive()
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Fatalf("Receive() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Receive() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Receive() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReceiveChunkedBoundsTheMessage(t *testing.T) {
+	// Chunks within the bounds, summing up beyond the maximum message size
+	chunk := strings.Repeat("x", 1<<20)
+	input := strings.Repeat("\n#1048576\n"+chunk, maxMessageSize/len(chunk)+1) + "\n##\n"
+	transport, _ := newTestTransport(input, "v1.1")
+	_, err := transport.Receive()
+	if err != errMessageTooLarge {
+		t.Errorf("Receive() error = %v, want %v", err, errMessageTooLarge)
+	}
+}
+
+func TestReceiveEndOfMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "message", input: "<hello/>]]>]]>", want: "<hello/>"},
+		{name: "several messages", input: "<a/>]]>]]><b/>]]>]]>", want: "<a/>"},
+		{name: "delimiter within the message", input: "<a>]]></a>]]>]]>", want: "<a>]]></a>"},
+		{name: "truncated", input: "<hello/>]]>", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, _ := newTestTransport(tt.input, "v1.0")
+			got, err := transport.Receive()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Receive() error = %v, want error %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Receive() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendReceive(t *testing.T) {
+	for _, version := range []string{"v1.0", "v1.1"} {
+		t.Run(version, func(t *testing.T) {
+			sender, out := newTestTransport("", version)
+			message := "<rpc message-id=\"1\"><get/></rpc>"
+			if err := sender.Send([]byte(message)); err != nil {
+				t.Fatal(err)
+			}
+			receiver, _ := newTestTransport(out.String(), version)
+			got, err := receiver.Receive()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != message {
+				t.Errorf("Receive() = %q, want %q", got, message)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package controllers
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+	"github.com/redhat-cop/operator-utils/pkg/util"
+)
+
+// tlsCABundleKey is the key holding the CA bundle within the Secret referenced by TLSConfig.CABundleSecretRef
+const tlsCABundleKey = "ca.crt"
+
+// dialTLS establishes the TLS connection with the NETCONF server. When a timeout is set, it bounds the
+// connection establishment and every write; TCP keepalives detect the loss of an idle connection.
+func dialTLS(target string, config *tls.Config, timeout time.Duration) (*tls.Conn, error) {
+	dialer := &net.Dialer{Timeout: timeout, KeepAlive: 15 * time.Second}
+	return tls.DialWithDialer(dialer, "tcp", target, config)
+}
+
+// newTLSConfig builds the TLS configuration out of the Secrets referenced by the MountPoint. Missing or
+// malformed Secrets are reported as credentialsError, so they surface on the CredentialsAvailable condition.
+func newTLSConfig(r util.ReconcilerBase, obj *netconfv1.MountPoint) (*tls.Config, *tls.Certificate, error) {
+	spec := obj.Spec.TLS
+	if spec == nil {
+		return nil, nil, malformedCredentials("MountPoint %s requires a tls configuration", obj.Name)
+	}
+
+	secret, err := getSecret(r, obj.Namespace, spec.ClientCertificateSecretRef.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, nil, malformedCredentials(
+			"Secret %s holds an invalid %s or %s: %s", spec.ClientCertificateSecretRef.Name, corev1.TLSCertKey,
+			corev1.TLSPrivateKeyKey, err,
+		)
+	}
+
+	config := &tls.Config{
+		Certificates:       []tls.Certificate{cert},
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         spec.ServerName,
+		InsecureSkipVerify: spec.InsecureSkipVerify,
+	}
+	if config.ServerName == "" {
+		config.ServerName, _, _ = net.SplitHostPort(withDefaultTLSPort(obj.Spec.Target))
+	}
+
+	if spec.CABundleSecretRef != nil {
+		secret, err := getSecret(r, obj.Namespace, spec.CABundleSecretRef.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(secret.Data[tlsCABundleKey]) {
+			return nil, nil, malformedCredentials(
+				"Secret %s has no valid certificate in its %s key", spec.CABundleSecretRef.Name, tlsCABundleKey,
+			)
+		}
+	}
+	return config, &cert, nil
+}
+
+// withDefaultTLSPort appends the NETCONF over TLS default port when none is specified.
+func withDefaultTLSPort(target string) string {
+	if _, _, err := net.SplitHostPort(target); err != nil {
+		return net.JoinHostPort(target, "6513")
+	}
+	return target
+}
+
+// tlsStatus describes the established TLS connection, and the username derived from the client certificate.
+func tlsStatus(state tls.ConnectionState, cert *tls.Certificate, mappings []netconfv1.CertToName) *netconfv1.TLSStatus {
+	status := &netconfv1.TLSStatus{
+		Version:     tlsVersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+	}
+	if len(state.PeerCertificates) != 0 {
+		status.PeerCertificate = peerCertificate(state.PeerCertificates[0])
+	}
+	if cert != nil && len(mappings) != 0 {
+		status.Username = certToName(mappings, cert.Certificate)
+	}
+	return status
+}
+
+func peerCertificate(cert *x509.Certificate) *netconfv1.PeerCertificate {
+	peer := &netconfv1.PeerCertificate{
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		SerialNumber: cert.SerialNumber.String(),
+		NotBefore:    metav1.NewTime(cert.NotBefore),
+		NotAfter:     metav1.NewTime(cert.NotAfter),
+		DNSNames:     cert.DNSNames,
+		Fingerprint:  certificateFingerprint(cert.Raw),
+	}
+	for _, ip := range cert.IPAddresses {
+		peer.IPAddresses = append(peer.IPAddresses, ip.String())
+	}
+	return peer
+}
+
+// certificateFingerprint returns the SHA256 fingerprint of the DER encoded certificate, in colon separated hexadecimal.
+func certificateFingerprint(raw []byte) string {
+	fingerprint := sha256.Sum256(raw)
+	return colonHex(fingerprint[:])
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	default:
+		return fmt.Sprintf("0x%04x", version)
+	}
+}
+
+// certToName derives the NETCONF username from the client certificate chain, as a NETCONF server
+// applying the provided cert-to-name mapping would (RFC 7589, section 7). The entries are evaluated
+// by increasing ID: an entry applies when its fingerprint matches any certificate of the chain, and
+// when the username can be derived using its map type. An empty string is returned if none applies.
+func certToName(mappings []netconfv1.CertToName, chain [][]byte) string {
+	if len(chain) == 0 {
+		return ""
+	}
+	leaf, err := x509.ParseCertificate(chain[0])
+	if err != nil {
+		return ""
+	}
+
+	sorted := make([]netconfv1.CertToName, len(mappings))
+	copy(sorted, mappings)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	for _, mapping := range sorted {
+		if !fingerprintMatches(mapping.Fingerprint, chain) {
+			continue
+		}
+		if name := mapName(mapping, leaf); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+func mapName(mapping netconfv1.CertToName, cert *x509.Certificate) string {
+	switch mapping.MapType {
+	case netconfv1.SpecifiedCertToName:
+		return mapping.Name
+	case netconfv1.SANRFC822NameCertToName:
+		return rfc822Name(cert)
+	case netconfv1.SANDNSNameCertToName:
+		return dnsName(cert)
+	case netconfv1.SANIPAddressCertToName:
+		return ipAddress(cert)
+	case netconfv1.SANAnyCertToName:
+		for _, name := range []string{rfc822Name(cert), dnsName(cert), ipAddress(cert)} {
+			if name != "" {
+				return name
+			}
+		}
+	case netconfv1.CommonNameCertToName:
+		return cert.Subject.CommonName
+	}
+	return ""
+}
+
+// rfc822Name returns the first rfc822Name subjectAltName, with its host part converted to lowercase.
+func rfc822Name(cert *x509.Certificate) string {
+	if len(cert.EmailAddresses) == 0 {
+		return ""
+	}
+	address := cert.EmailAddresses[0]
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		return address[:i] + strings.ToLower(address[i:])
+	}
+	return address
+}
+
+// dnsName returns the first dNSName subjectAltName, converted to lowercase.
+func dnsName(cert *x509.Certificate) string {
+	if len(cert.DNSNames) == 0 {
+		return ""
+	}
+	return strings.ToLower(cert.DNSNames[0])
+}
+
+// ipAddress returns the first iPAddress subjectAltName; IPv6 addresses are fully expanded, as required by RFC 7407.
+func ipAddress(cert *x509.Certificate) string {
+	if len(cert.IPAddresses) == 0 {
+		return ""
+	}
+	ip := cert.IPAddresses[0]
+	if v4 := ip.To4(); v4 != nil {
+		return v4.String()
+	}
+	groups := make([]string, 8)
+	for i := range groups {
+		groups[i] = hex.EncodeToString(ip[2*i : 2*i+2])
+	}
+	return strings.ToUpper(strings.Join(groups, ":"))
+}
+
+// fingerprintMatches reports whether the tls-fingerprint, i.e. a hash algorithm identifier (RFC 5246)
+// followed by the hash, matches any certificate of the chain.
+func fingerprintMatches(fingerprint string, chain [][]byte) bool {
+	raw, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(fingerprint), ":", ""))
+	if err != nil || len(raw) < 2 {
+		return false
+	}
+
+	var h func() hash.Hash
+	switch raw[0] {
+	case 2:
+		h = sha1.New
+	case 3:
+		h = sha256.New224
+	case 4:
+		h = sha256.New
+	case 5:
+		h = sha512.New384
+	case 6:
+		h = sha512.New
+	default:
+		return false
+	}
+
+	for _, cert := range chain {
+		digest := h()
+		digest.Write(cert)
+		if bytes.Equal(digest.Sum(nil), raw[1:]) {
+			return true
+		}
+	}
+	return false
+}
+
+func colonHex(b []byte) string {
+	parts := make([]string, len(b))
+	for i := range b {
+		parts[i] = hex.EncodeToString(b[i : i+1])
+	}
+	return strings.ToUpper(strings.Join(parts, ":"))
+}
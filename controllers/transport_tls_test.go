@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// newTestChain returns a client certificate, issued by a CA, along with the CA certificate, both DER-encoded.
+func newTestChain(t *testing.T, leaf *x509.Certificate) [][]byte {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "netconf-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, ca, ca, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err = x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf.SerialNumber = big.NewInt(2)
+	leaf.NotBefore = time.Now().Add(-time.Hour)
+	leaf.NotAfter = time.Now().Add(time.Hour)
+	leafDER, err := x509.CreateCertificate(rand.Reader, leaf, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return [][]byte{leafDER, caDER}
+}
+
+// tlsFingerprint returns the tls-fingerprint of the certificate, using SHA256, or SHA1.
+func tlsFingerprint(der []byte, useSHA1 bool) string {
+	if useSHA1 {
+		sum := sha1.Sum(der)
+		return colonHex(append([]byte{2}, sum[:]...))
+	}
+	sum := sha256.Sum256(der)
+	return colonHex(append([]byte{4}, sum[:]...))
+}
+
+func TestCertToName(t *testing.T) {
+	chain := newTestChain(t, &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "netconf-admin"},
+		EmailAddresses: []string{"Admin@Example.COM"},
+		DNSNames:       []string{"Client.Example.com"},
+		IPAddresses:    []net.IP{net.ParseIP("2001:db8::1")},
+	})
+	bare := newTestChain(t, &x509.Certificate{Subject: pkix.Name{CommonName: "bare"}})
+	leaf, ca := tlsFingerprint(chain[0], false), tlsFingerprint(chain[1], false)
+
+	tests := []struct {
+		name     string
+		mappings []netconfv1.CertToName
+		chain    [][]byte
+		want     string
+	}{
+		{
+			name:     "specified, by the fingerprint of the CA",
+			mappings: []netconfv1.CertToName{{ID: 1, Fingerprint: ca, MapType: netconfv1.SpecifiedCertToName, Name: "ops"}},
+			chain:    chain,
+			want:     "ops",
+		},
+		{
+			name: "specified, by the SHA1 fingerprint of the client certificate",
+			mappings: []netconfv1.CertToName{
+				{ID: 1, Fingerprint: tlsFingerprint(chain[0], true), MapType: netconfv1.SpecifiedCertToName, Name: "ops"},
+			},
+			chain: chain,
+			want:  "ops",
+		},
+		{
+			name:     "lowercase fingerprint",
+			mappings: []netconfv1.CertToName{{ID: 1, Fingerprint: "04:" + leaf[3:], MapType: netconfv1.CommonNameCertToName}},
+			chain:    chain,
+			want:     "netconf-admin",
+		},
+		{
+			name:     "rfc822Name, with a lowercase host",
+			mappings: []netconfv1.CertToName{{ID: 1, Fingerprint: leaf, MapType: netconfv1.SANRFC822NameCertToName}},
+			chain:    chain,
+			want:     "Admin@example.com",
+		},
+		{
+			name:     "dNSName, lowercase",
+			mappings: []netconfv1.CertToName{{ID: 1, Fingerprint: leaf, MapType: netconfv1.SANDNSNameCertToName}},
+			chain:    chain,
+			want:     "client.example.com",
+		},
+		{
+			name:     "iPAddress, fully expanded",
+			mappings: []netconfv1.CertToName{{ID: 1, Fingerprint: leaf, MapType: netconfv1.SANIPAddressCertToName}},
+			chain:    chain,
+			want:     "2001:0DB8:0000:0000:0000:0000:0000:0001",
+		},
+		{
+			name:     "any subjectAltName",
+			mappings: []netconfv1.CertToName{{ID: 1, Fingerprint: leaf, MapType: netconfv1.SANAnyCertToName}},
+			chain:    chain,
+			want:     "Admin@example.com",
+		},
+		{
+			name: "the lowest ID wins",
+			mappings: []netconfv1.CertToName{
+				{ID: 2, Fingerprint: ca, MapType: netconfv1.SpecifiedCertToName, Name: "second"},
+				{ID: 1, Fingerprint: ca, MapType: netconfv1.SpecifiedCertToName, Name: "first"},
+			},
+			chain: chain,
+			want:  "first",
+		},
+		{
+			name: "an entry not deriving a name is skipped",
+			mappings: []netconfv1.CertToName{
+				{ID: 1, Fingerprint: tlsFingerprint(bare[1], false), MapType: netconfv1.SANDNSNameCertToName},
+				{ID: 2, Fingerprint: tlsFingerprint(bare[1], false), MapType: netconfv1.CommonNameCertToName},
+			},
+			chain: bare,
+			want:  "bare",
+		},
+		{
+			name:     "other fingerprint",
+			mappings: []netconfv1.CertToName{{ID: 1, Fingerprint: ca, MapType: netconfv1.SpecifiedCertToName, Name: "ops"}},
+			chain:    bare,
+		},
+		{
+			name:     "unknown hash algorithm",
+			mappings: []netconfv1.CertToName{{ID: 1, Fingerprint: "09" + ca[2:], MapType: netconfv1.CommonNameCertToName}},
+			chain:    chain,
+		},
+		{
+			name:     "no certificate",
+			mappings: []netconfv1.CertToName{{ID: 1, Fingerprint: ca, MapType: netconfv1.SpecifiedCertToName, Name: "ops"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := certToName(tt.mappings, tt.chain); got != tt.want {
+				t.Errorf("certToName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
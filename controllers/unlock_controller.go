@@ -25,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -45,6 +46,7 @@ import (
 // UnlockReconciler reconciles a Unlock object
 type UnlockReconciler struct {
 	util.ReconcilerBase
+	sessions SessionProvider
 }
 
 // AddUnlock Add creates a new MountPoint Controller and adds it to the Manager.
@@ -73,8 +75,17 @@ func (r *UnlockReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return r.ManageError(ctx, instance, err)
 	}
 
+	// The operation is executed once per spec, unless executed again through its run-id
+	execute, result, err := manageExecution(
+		ctx, r.ReconcilerBase, instance, instance.Spec, instance.Spec.ExecutionPolicy, &instance.RPCStatus, nil, log,
+	)
+	if !execute {
+		return result, err
+	}
+
 	// Managing CR validation
 	if ok, err := r.isValid(instance); !ok {
+		setFailed(instance, err, netconfv1.AppliedCondition)
 		return r.ManageErrorWithRequeue(ctx, instance, err, 2*time.Second)
 	}
 
@@ -93,12 +104,17 @@ func (r *UnlockReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return reconcile.Result{}, nil
 	}
 
+	// Wait for the retry scheduled following a transient failure, if any
+	if wait := startAttempt(instance.Spec.RetryPolicy, &instance.RPCStatus, instance.Generation); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
 	err = r.manageOperatorLogic(instance, log)
 	if err != nil {
-		return r.ManageError(ctx, instance, err)
+		return manageRetry(ctx, r.ReconcilerBase, instance, instance.Spec.RetryPolicy, &instance.RPCStatus, err)
 	}
 
-	return r.ManageSuccess(ctx, instance)
+	return manageCompletion(ctx, r.ReconcilerBase, instance, instance.Spec.ExecutionPolicy, &instance.RPCStatus)
 }
 
 func (r *UnlockReconciler) isInitialized(obj metav1.Object) bool {
@@ -117,11 +133,11 @@ func (r *UnlockReconciler) isValid(obj metav1.Object) (bool, error) {
 	}
 
 	exists := CheckMountPointExists(
-		r.ReconcilerBase,
+		r.ReconcilerBase, r.sessions,
 		types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.MountPoint},
 	)
 	if !exists {
-		return false, fmt.Errorf("MountPoint %s doesn't exists", instance.Spec.MountPoint)
+		return false, mountPointUnavailable(instance.Spec.MountPoint)
 	}
 
 	return true, nil
@@ -134,26 +150,30 @@ func (r *UnlockReconciler) manageOperatorLogic(unlock *netconfv1.Unlock, log log
 		),
 	)
 
-	if !unlock.Spec.DependsOn.IsNil() {
-		err := validateDependency(r.ReconcilerBase, unlock.Namespace, unlock.Spec.DependsOn)
-		if err != nil {
-			log.Error(err, "Failed to validate dependency.")
-			return err
-		}
+	ok, err := checkDependencies(r.ReconcilerBase, unlock, unlock.Spec.DependsOn, log, netconfv1.AppliedCondition)
+	if !ok {
+		return err
 	}
-	s := Sessions[unlock.GetMountPointNamespacedName(unlock.Spec.MountPoint)]
+	s, err := r.sessions.Acquire(unlock.GetMountPointNamespacedName(unlock.Spec.MountPoint))
+	if err != nil {
+		err = mountPointUnavailable(unlock.Spec.MountPoint)
+		setFailed(unlock, err, netconfv1.AppliedCondition)
+		return err
+	}
+	defer r.sessions.Release(s)
 	reply, err := s.SyncRPC(message.NewUnlock(unlock.Spec.Target), unlock.Spec.Timeout)
 
-	if err != nil || reply.Errors != nil {
+	err = checkReply(reply, err)
+	if err != nil {
 		log.Info(fmt.Sprintf("%s: Failed to Unlock %s.", unlock.Spec.MountPoint, unlock.Name))
-		unlock.Status = "failed"
-		unlock.RpcReply = reply.RawReply
+		setReplyStatus(&unlock.RPCStatus, reply, err)
+		setFailed(unlock, err, netconfv1.AppliedCondition)
 		return err
 	}
 
 	log.Info(fmt.Sprintf("%s: Successfully executed Unlock %s operation.", unlock.Spec.MountPoint, unlock.Name))
-	unlock.Status = "success"
-	unlock.RpcReply = reply.Data
+	setReplyStatus(&unlock.RPCStatus, reply, nil)
+	setSucceeded(unlock, netconfv1.AppliedCondition)
 
 	return nil
 }
@@ -164,6 +184,7 @@ func newUnlockReconciler(mgr manager.Manager) reconcile.Reconciler {
 			mgr.GetClient(), mgr.GetScheme(), mgr.GetConfig(), mgr.GetEventRecorderFor(unlockControllerName),
 			mgr.GetAPIReader(),
 		),
+		sessions: sessions,
 	}
 }
 
@@ -176,11 +197,19 @@ func addUnlock(mgr manager.Manager, r reconcile.Reconciler) error {
 
 	err = c.Watch(
 		&source.Kind{Type: &netconfv1.Unlock{}}, &handler.EnqueueRequestForObject{},
-		util.ResourceGenerationOrFinalizerChangedPredicate{},
+		executionPredicate,
 	)
 	if err != nil {
 		return err
 	}
 
+	// Watch over the dependencies, to start the Unlock once they are Ready
+	err = watchDependencies(
+		mgr, c, &netconfv1.Unlock{}, &netconfv1.UnlockList{},
+		func(obj client.Object) netconfv1.Dependencies { return obj.(*netconfv1.Unlock).Spec.DependsOn },
+	)
+	if err != nil {
+		return err
+	}
 	return nil
 }
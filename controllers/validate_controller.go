@@ -0,0 +1,215 @@
+/*
+Copyright 2021. Alexis de Talhouët
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	"github.com/openshift-telco/go-netconf-client/netconf/message"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"time"
+
+	"github.com/redhat-cop/operator-utils/pkg/util"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=validates,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=validates/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=validates/finalizers,verbs=update
+
+// ValidateReconciler reconciles a Validate object
+type ValidateReconciler struct {
+	util.ReconcilerBase
+	sessions SessionProvider
+}
+
+// AddValidate Add creates a new MountPoint Controller and adds it to the Manager.
+func AddValidate(mgr manager.Manager) error {
+	return addValidate(mgr, newValidateReconciler(mgr))
+}
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *ValidateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var log = logf.Log.WithName(validateControllerName)
+
+	reqLogger := log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+	reqLogger.Info("Reconciling Validate")
+
+	// Fetch the CRD instance
+	instance := &netconfv1.Validate{}
+	err := r.GetClient().Get(context.Background(), req.NamespacedName, instance)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("Validate resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		log.Error(err, "Failed to get Validate")
+		return r.ManageError(ctx, instance, err)
+	}
+
+	// The operation is executed once per spec, unless executed again through its run-id
+	execute, result, err := manageExecution(
+		ctx, r.ReconcilerBase, instance, instance.Spec, instance.Spec.ExecutionPolicy, &instance.RPCStatus, nil, log,
+	)
+	if !execute {
+		return result, err
+	}
+
+	// Managing CR validation
+	if ok, err := r.isValid(instance); !ok {
+		setFailed(instance, err, netconfv1.AppliedCondition)
+		return r.ManageErrorWithRequeue(ctx, instance, err, 2*time.Second)
+	}
+
+	// Managing CR Initialization
+	if ok := r.isInitialized(instance); !ok {
+		err := r.GetClient().Update(context.Background(), instance)
+		if err != nil {
+			log.Error(err, "unable to update instance", "instance", instance)
+			return r.ManageError(ctx, instance, err)
+		}
+		return reconcile.Result{}, nil
+	}
+
+	// Managing CR Finalization
+	if util.IsBeingDeleted(instance) {
+		return reconcile.Result{}, nil
+	}
+
+	// Wait for the retry scheduled following a transient failure, if any
+	if wait := startAttempt(instance.Spec.RetryPolicy, &instance.RPCStatus, instance.Generation); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
+	err = r.manageOperatorLogic(instance, log)
+	if err != nil {
+		return manageRetry(ctx, r.ReconcilerBase, instance, instance.Spec.RetryPolicy, &instance.RPCStatus, err)
+	}
+
+	return manageCompletion(ctx, r.ReconcilerBase, instance, instance.Spec.ExecutionPolicy, &instance.RPCStatus)
+}
+
+func (r *ValidateReconciler) isInitialized(obj metav1.Object) bool {
+	_, ok := obj.(*netconfv1.Validate)
+	if !ok {
+		return false
+	}
+	return true
+
+}
+
+func (r *ValidateReconciler) isValid(obj metav1.Object) (bool, error) {
+	instance, ok := obj.(*netconfv1.Validate)
+	if !ok {
+		return false, fmt.Errorf("%s is not a Validate object", obj.GetName())
+	}
+
+	exists := CheckMountPointExists(
+		r.ReconcilerBase, r.sessions,
+		types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.MountPoint},
+	)
+	if !exists {
+		return false, mountPointUnavailable(instance.Spec.MountPoint)
+	}
+
+	return true, nil
+}
+
+func (r *ValidateReconciler) manageOperatorLogic(validate *netconfv1.Validate, log logr.Logger) error {
+	log.Info(
+		fmt.Sprintf(
+			"%s: Send Validate %s on %s datastore.", validate.Spec.MountPoint, validate.Name, validate.Spec.Source,
+		),
+	)
+
+	ok, err := checkDependencies(r.ReconcilerBase, validate, validate.Spec.DependsOn, log, netconfv1.AppliedCondition)
+	if !ok {
+		return err
+	}
+	s, err := r.sessions.Acquire(validate.GetMountPointNamespacedName(validate.Spec.MountPoint))
+	if err != nil {
+		err = mountPointUnavailable(validate.Spec.MountPoint)
+		setFailed(validate, err, netconfv1.AppliedCondition)
+		return err
+	}
+	defer r.sessions.Release(s)
+	reply, err := s.SyncRPC(message.NewValidate(validate.Spec.Source), validate.Spec.Timeout)
+
+	err = checkReply(reply, err)
+	if err != nil {
+		log.Info(fmt.Sprintf("%s: Failed to Validate %s.", validate.Spec.MountPoint, validate.Name))
+		setReplyStatus(&validate.RPCStatus, reply, err)
+		setFailed(validate, err, netconfv1.AppliedCondition)
+		return err
+	}
+
+	log.Info(fmt.Sprintf("%s: Successfully executed Validate %s operation.", validate.Spec.MountPoint, validate.Name))
+	setReplyStatus(&validate.RPCStatus, reply, nil)
+	setSucceeded(validate, netconfv1.AppliedCondition)
+
+	return nil
+}
+
+func newValidateReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ValidateReconciler{
+		ReconcilerBase: util.NewReconcilerBase(
+			mgr.GetClient(), mgr.GetScheme(), mgr.GetConfig(), mgr.GetEventRecorderFor(validateControllerName),
+			mgr.GetAPIReader(),
+		),
+		sessions: sessions,
+	}
+}
+
+func addValidate(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(validateControllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(
+		&source.Kind{Type: &netconfv1.Validate{}}, &handler.EnqueueRequestForObject{},
+		executionPredicate,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch over the dependencies, to start the Validate once they are Ready
+	err = watchDependencies(
+		mgr, c, &netconfv1.Validate{}, &netconfv1.ValidateList{},
+		func(obj client.Object) netconfv1.Dependencies { return obj.(*netconfv1.Validate).Spec.DependsOn },
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
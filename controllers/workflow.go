@@ -0,0 +1,263 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/go-logr/logr"
+	"github.com/openshift-telco/go-netconf-client/netconf/message"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// workflowState tracks what the steps of the workflow left behind, so that it can be compensated by default.
+type workflowState struct {
+	// The datastores locked by the workflow, in locking order
+	locked []string
+	// Whether the candidate datastore was edited since its last commit, or discard
+	candidateEdited bool
+}
+
+func completeWorkflow(obj *netconfv1.Workflow, phase netconfv1.WorkflowPhase) {
+	now := metav1.Now()
+	obj.Phase = phase
+	obj.CompletionTime = &now
+}
+
+// workflowOrder returns the indexes of the steps in execution order: each step is executed after the steps listed
+// in its runAfter, and otherwise in the order the steps are listed.
+func workflowOrder(steps []netconfv1.WorkflowStep) ([]int, error) {
+	indexes := make(map[string]int, len(steps))
+	for i, step := range steps {
+		if step.Name == "" {
+			return nil, fmt.Errorf("step %d has no name", i)
+		}
+		if _, ok := indexes[step.Name]; ok {
+			return nil, fmt.Errorf("step %s is defined more than once", step.Name)
+		}
+		indexes[step.Name] = i
+	}
+	for _, step := range steps {
+		for _, after := range step.RunAfter {
+			if _, ok := indexes[after]; !ok {
+				return nil, fmt.Errorf("step %s runs after the unknown step %s", step.Name, after)
+			}
+		}
+	}
+
+	order := make([]int, 0, len(steps))
+	done := make([]bool, len(steps))
+	for len(order) < len(steps) {
+		next := -1
+		for i, step := range steps {
+			if done[i] {
+				continue
+			}
+			ready := true
+			for _, after := range step.RunAfter {
+				ready = ready && done[indexes[after]]
+			}
+			if ready {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			return nil, fmt.Errorf("the runAfter of the steps form a cycle")
+		}
+		done[next] = true
+		order = append(order, next)
+	}
+	return order, nil
+}
+
+// run executes the steps in order. Once a step failed, the following steps are skipped, and the compensating
+// steps are executed.
+func (r *WorkflowReconciler) run(
+	obj *netconfv1.Workflow, s Session, order []int, compensations []int, log logr.Logger,
+) error {
+	capabilities := r.sessions.Capabilities(obj.GetMountPointNamespacedName(obj.Spec.MountPoint))
+	obj.Steps = make([]netconfv1.WorkflowStepStatus, len(order))
+	for i, index := range order {
+		step := obj.Spec.Steps[index]
+		obj.Steps[i] = netconfv1.WorkflowStepStatus{
+			Name: step.Name, Type: step.Type, Phase: netconfv1.PendingWorkflowStepPhase,
+		}
+	}
+
+	state := &workflowState{}
+	for i, index := range order {
+		step := obj.Spec.Steps[index]
+		reply, err := runWorkflowStep(s, step, &obj.Steps[i], state, capabilities, obj.Spec.Timeout)
+		setReplyStatus(&obj.RPCStatus, reply, err)
+		if err == nil {
+			continue
+		}
+
+		for j := i + 1; j < len(order); j++ {
+			obj.Steps[j].Phase = netconfv1.SkippedWorkflowStepPhase
+			obj.Steps[j].Message = fmt.Sprintf("Step %s failed", step.Name)
+		}
+		r.compensate(obj, s, compensations, state, capabilities, log)
+		return fmt.Errorf("step %s failed: %w", step.Name, err)
+	}
+	return nil
+}
+
+// compensate executes the compensating steps, or by default discards the changes of the candidate datastore and
+// unlocks the locked datastores, in reverse order. Every compensating step is attempted, whatever the outcome of
+// the previous ones.
+func (r *WorkflowReconciler) compensate(
+	obj *netconfv1.Workflow, s Session, order []int, state *workflowState, capabilities []string, log logr.Logger,
+) {
+	steps := make([]netconfv1.WorkflowStep, 0, len(order))
+	for _, index := range order {
+		steps = append(steps, obj.Spec.OnFailure[index])
+	}
+	if len(obj.Spec.OnFailure) == 0 {
+		steps = defaultCompensations(state)
+	}
+
+	obj.Compensations = make([]netconfv1.WorkflowStepStatus, len(steps))
+	for i, step := range steps {
+		obj.Compensations[i] = netconfv1.WorkflowStepStatus{
+			Name: step.Name, Type: step.Type, Phase: netconfv1.PendingWorkflowStepPhase,
+		}
+		_, err := runWorkflowStep(s, step, &obj.Compensations[i], state, capabilities, obj.Spec.Timeout)
+		if err != nil {
+			log.Info(
+				fmt.Sprintf(
+					"%s: Failed to compensate Workflow %s, step %s: %s", obj.Spec.MountPoint, obj.Name, step.Name, err,
+				),
+			)
+		}
+	}
+}
+
+func defaultCompensations(state *workflowState) []netconfv1.WorkflowStep {
+	var steps []netconfv1.WorkflowStep
+	if state.candidateEdited {
+		steps = append(
+			steps, netconfv1.WorkflowStep{Name: "discard-changes", Type: netconfv1.DiscardChangesWorkflowStep},
+		)
+	}
+	for i := len(state.locked) - 1; i >= 0; i-- {
+		steps = append(
+			steps, netconfv1.WorkflowStep{
+				Name: "unlock-" + state.locked[i], Type: netconfv1.UnlockWorkflowStep, Datastore: state.locked[i],
+			},
+		)
+	}
+	return steps
+}
+
+// runWorkflowStep executes the step, reports its outcome in its status, and tracks the datastores it locked,
+// unlocked or edited.
+func runWorkflowStep(
+	s Session, step netconfv1.WorkflowStep, status *netconfv1.WorkflowStepStatus, state *workflowState,
+	capabilities []string, timeout int32,
+) (*message.RPCReply, error) {
+	start := metav1.Now()
+	status.StartTime = &start
+	datastore := stepDatastore(step)
+	rpc, err := workflowStepRPC(step, datastore, capabilities)
+	var reply *message.RPCReply
+	if err == nil {
+		reply, err = s.SyncRPC(message.NewRPC(rpc), timeout)
+		err = checkReply(reply, err)
+	}
+	completion := metav1.Now()
+	status.CompletionTime = &completion
+	status.RPCErrors = nil
+	if reply != nil {
+		for i := range reply.Errors {
+			status.RPCErrors = append(status.RPCErrors, rpcError(reply.Errors[i]))
+		}
+	}
+	if err != nil {
+		status.Phase = netconfv1.FailedWorkflowStepPhase
+		status.Message = err.Error()
+		return reply, err
+	}
+
+	status.Phase = netconfv1.SucceededWorkflowStepPhase
+	switch step.Type {
+	case netconfv1.GetWorkflowStep, netconfv1.GetConfigWorkflowStep, netconfv1.RPCWorkflowStep:
+		status.Reply = reply.Data
+	case netconfv1.LockWorkflowStep:
+		state.locked = append(state.locked, datastore)
+	case netconfv1.UnlockWorkflowStep:
+		for i, locked := range state.locked {
+			if locked == datastore {
+				state.locked = append(state.locked[:i], state.locked[i+1:]...)
+				break
+			}
+		}
+	case netconfv1.EditConfigWorkflowStep:
+		state.candidateEdited = state.candidateEdited || datastore == message.DatastoreCandidate
+	case netconfv1.CommitWorkflowStep, netconfv1.DiscardChangesWorkflowStep:
+		state.candidateEdited = false
+	}
+	return reply, nil
+}
+
+// stepDatastore returns the datastore the step operates on: `running` by default for getConfig, `candidate`
+// otherwise.
+func stepDatastore(step netconfv1.WorkflowStep) string {
+	switch {
+	case step.Datastore != "":
+		return step.Datastore
+	case step.Type == netconfv1.GetConfigWorkflowStep:
+		return message.DatastoreRunning
+	}
+	return message.DatastoreCandidate
+}
+
+// workflowStepRPC renders the RPC of the step.
+func workflowStepRPC(step netconfv1.WorkflowStep, datastore string, capabilities []string) (string, error) {
+	target, err := configTarget(netconfv1.ConfigTarget{Datastore: datastore}, capabilities)
+	if err != nil {
+		return "", err
+	}
+	filter := ""
+	if step.XML != "" {
+		if _, err := parseXML(step.XML); err != nil {
+			return "", fmt.Errorf("invalid XML payload: %w", err)
+		}
+		filter = `<filter type="subtree">` + step.XML + `</filter>`
+	}
+
+	switch step.Type {
+	case netconfv1.LockWorkflowStep:
+		return "<lock><target>" + target + "</target></lock>", nil
+	case netconfv1.UnlockWorkflowStep:
+		return "<unlock><target>" + target + "</target></unlock>", nil
+	case netconfv1.EditConfigWorkflowStep:
+		if step.XML == "" {
+			return "", fmt.Errorf("the xml payload of an editConfig step must be set")
+		}
+		operation := step.Operation
+		if operation == "" {
+			operation = "merge"
+		}
+		return fmt.Sprintf(
+			"<edit-config><target>%s</target><default-operation>%s</default-operation><config>%s</config>"+
+				"</edit-config>", target, operation, step.XML,
+		), nil
+	case netconfv1.ValidateWorkflowStep:
+		return "<validate><source>" + target + "</source></validate>", nil
+	case netconfv1.CommitWorkflowStep:
+		return "<commit/>", nil
+	case netconfv1.DiscardChangesWorkflowStep:
+		return discardChangesRPC, nil
+	case netconfv1.GetWorkflowStep:
+		return "<get>" + filter + "</get>", nil
+	case netconfv1.GetConfigWorkflowStep:
+		return "<get-config><source>" + target + "</source>" + filter + "</get-config>", nil
+	case netconfv1.RPCWorkflowStep:
+		if step.XML == "" {
+			return "", fmt.Errorf("the xml payload of an rpc step must be set")
+		}
+		return step.XML, nil
+	}
+	return "", fmt.Errorf("unsupported step type %s", step.Type)
+}
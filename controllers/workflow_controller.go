@@ -0,0 +1,232 @@
+/*
+Copyright 2021. Alexis de Talhouët
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"time"
+
+	"github.com/redhat-cop/operator-utils/pkg/util"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=workflows,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=workflows/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=netconf.openshift-telco.io,resources=workflows/finalizers,verbs=update
+
+// WorkflowReconciler reconciles a Workflow object
+type WorkflowReconciler struct {
+	util.ReconcilerBase
+	sessions SessionProvider
+}
+
+// AddWorkflow creates a new Workflow Controller and adds it to the Manager.
+func AddWorkflow(mgr manager.Manager) error {
+	return addWorkflow(mgr, newWorkflowReconciler(mgr))
+}
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *WorkflowReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var log = logf.Log.WithName(workflowControllerName)
+
+	reqLogger := log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+	reqLogger.Info("Reconciling Workflow")
+
+	// Fetch the CRD instance
+	instance := &netconfv1.Workflow{}
+	err := r.GetClient().Get(context.Background(), req.NamespacedName, instance)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("Workflow resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		log.Error(err, "Failed to get Workflow")
+		return r.ManageError(ctx, instance, err)
+	}
+
+	// The operation is executed once per spec, unless executed again through its run-id
+	execute, result, err := manageExecution(
+		ctx, r.ReconcilerBase, instance, instance.Spec, instance.Spec.ExecutionPolicy, &instance.RPCStatus,
+		func() { instance.ObservedGeneration = 0 }, log,
+	)
+	if !execute {
+		return result, err
+	}
+
+	// The workflow is executed once per generation, whatever its outcome, unless retried. Failed workflows which
+	// completed before their executions were recorded aren't executed again either.
+	if instance.ObservedGeneration == instance.Generation &&
+		(instance.Phase == netconfv1.SucceededWorkflowPhase || instance.Phase == netconfv1.FailedWorkflowPhase) &&
+		!retryScheduled(&instance.RPCStatus, instance.Generation) {
+		return reconcile.Result{}, nil
+	}
+
+	// Managing CR validation
+	if ok, err := r.isValid(instance); !ok {
+		setFailed(instance, err, netconfv1.AppliedCondition)
+		return r.ManageErrorWithRequeue(ctx, instance, err, 2*time.Second)
+	}
+
+	// Managing CR Finalization
+	if util.IsBeingDeleted(instance) {
+		return reconcile.Result{}, nil
+	}
+
+	// Wait for the retry scheduled following a transient failure, if any
+	if wait := startAttempt(instance.Spec.RetryPolicy, &instance.RPCStatus, instance.Generation); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
+	err = r.manageOperatorLogic(instance, log)
+	if instance.Phase == netconfv1.SucceededWorkflowPhase || instance.Phase == netconfv1.FailedWorkflowPhase {
+		// Completed, whatever its outcome, unless retried
+		completeExecution(&instance.RPCStatus)
+	}
+	if err != nil {
+		return manageRetry(ctx, r.ReconcilerBase, instance, instance.Spec.RetryPolicy, &instance.RPCStatus, err)
+	}
+
+	return manageCompletion(ctx, r.ReconcilerBase, instance, instance.Spec.ExecutionPolicy, &instance.RPCStatus)
+}
+
+func (r *WorkflowReconciler) isValid(obj metav1.Object) (bool, error) {
+	instance, ok := obj.(*netconfv1.Workflow)
+	if !ok {
+		return false, fmt.Errorf("%s is not a Workflow object", obj.GetName())
+	}
+
+	exists := CheckMountPointExists(
+		r.ReconcilerBase, r.sessions,
+		types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.MountPoint},
+	)
+	if !exists {
+		return false, mountPointUnavailable(instance.Spec.MountPoint)
+	}
+
+	return true, nil
+}
+
+// manageOperatorLogic executes the steps of the workflow on the session of the MountPoint, and the compensating
+// steps once a step failed. Until the steps are started, e.g. while the dependency isn't Ready, the workflow is
+// Pending. A failed workflow is only executed again when retried according to its retry policy.
+func (r *WorkflowReconciler) manageOperatorLogic(obj *netconfv1.Workflow, log logr.Logger) error {
+	if obj.ObservedGeneration != obj.Generation || obj.Phase == netconfv1.FailedWorkflowPhase {
+		obj.ObservedGeneration = obj.Generation
+		obj.Phase = netconfv1.PendingWorkflowPhase
+		obj.StartTime = nil
+		obj.CompletionTime = nil
+		obj.Steps = nil
+		obj.Compensations = nil
+	}
+
+	order, err := workflowOrder(obj.Spec.Steps)
+	if err != nil {
+		err = fmt.Errorf("invalid steps: %w", err)
+		completeWorkflow(obj, netconfv1.FailedWorkflowPhase)
+		setFailed(obj, err, netconfv1.AppliedCondition)
+		return err
+	}
+	compensations, err := workflowOrder(obj.Spec.OnFailure)
+	if err != nil {
+		err = fmt.Errorf("invalid onFailure steps: %w", err)
+		completeWorkflow(obj, netconfv1.FailedWorkflowPhase)
+		setFailed(obj, err, netconfv1.AppliedCondition)
+		return err
+	}
+
+	ok, err := checkDependencies(r.ReconcilerBase, obj, obj.Spec.DependsOn, log, netconfv1.AppliedCondition)
+	if !ok {
+		return err
+	}
+
+	s, err := r.sessions.Acquire(obj.GetMountPointNamespacedName(obj.Spec.MountPoint))
+	if err != nil {
+		err = mountPointUnavailable(obj.Spec.MountPoint)
+		setFailed(obj, err, netconfv1.AppliedCondition)
+		return err
+	}
+	defer r.sessions.Release(s)
+
+	log.Info(fmt.Sprintf("%s: Run Workflow %s.", obj.Spec.MountPoint, obj.Name))
+	now := metav1.Now()
+	obj.StartTime = &now
+	err = r.run(obj, s, order, compensations, log)
+	if err != nil {
+		log.Info(fmt.Sprintf("%s: Workflow %s failed: %s", obj.Spec.MountPoint, obj.Name, err))
+		r.GetRecorder().Event(obj, "Warning", conditionReason(err), err.Error())
+		completeWorkflow(obj, netconfv1.FailedWorkflowPhase)
+		setFailed(obj, err, netconfv1.AppliedCondition)
+		return err
+	}
+
+	log.Info(fmt.Sprintf("%s: Successfully executed Workflow %s.", obj.Spec.MountPoint, obj.Name))
+	completeWorkflow(obj, netconfv1.SucceededWorkflowPhase)
+	setSucceeded(obj, netconfv1.AppliedCondition)
+	return nil
+}
+
+func newWorkflowReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &WorkflowReconciler{
+		ReconcilerBase: util.NewReconcilerBase(
+			mgr.GetClient(), mgr.GetScheme(), mgr.GetConfig(), mgr.GetEventRecorderFor(workflowControllerName),
+			mgr.GetAPIReader(),
+		),
+		sessions: sessions,
+	}
+}
+
+func addWorkflow(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(workflowControllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(
+		&source.Kind{Type: &netconfv1.Workflow{}}, &handler.EnqueueRequestForObject{},
+		executionPredicate,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch over the dependencies, to start the Workflow once they are Ready
+	err = watchDependencies(
+		mgr, c, &netconfv1.Workflow{}, &netconfv1.WorkflowList{},
+		func(obj client.Object) netconfv1.Dependencies { return obj.(*netconfv1.Workflow).Spec.DependsOn },
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// testSteps returns the steps, each defined as `name` or `name:after1,after2`.
+func testSteps(definitions ...string) []netconfv1.WorkflowStep {
+	steps := make([]netconfv1.WorkflowStep, len(definitions))
+	for i, definition := range definitions {
+		parts := strings.SplitN(definition, ":", 2)
+		steps[i].Name = parts[0]
+		if len(parts) == 2 {
+			steps[i].RunAfter = strings.Split(parts[1], ",")
+		}
+	}
+	return steps
+}
+
+func TestWorkflowOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   []netconfv1.WorkflowStep
+		want    []int
+		wantErr string
+	}{
+		{name: "no step", want: []int{}},
+		{name: "listed order", steps: testSteps("lock", "edit", "commit"), want: []int{0, 1, 2}},
+		{
+			name:  "runAfter",
+			steps: testSteps("commit:edit", "edit:lock", "lock", "unlock:commit"),
+			want:  []int{2, 1, 0, 3},
+		},
+		{
+			name:  "several runAfter",
+			steps: testSteps("commit:edit1,edit2", "edit1", "edit2:edit1"),
+			want:  []int{1, 2, 0},
+		},
+		{
+			name:  "listed order among the ready steps",
+			steps: testSteps("b:a", "a", "c"),
+			want:  []int{1, 0, 2},
+		},
+		{name: "unnamed step", steps: testSteps("lock", ""), wantErr: "step 1 has no name"},
+		{name: "duplicated name", steps: testSteps("lock", "lock"), wantErr: "step lock is defined more than once"},
+		{
+			name:    "unknown step",
+			steps:   testSteps("commit:edit"),
+			wantErr: "step commit runs after the unknown step edit",
+		},
+		{name: "cycle", steps: testSteps("lock", "a:b", "b:a"), wantErr: "form a cycle"},
+		{name: "self", steps: testSteps("a:a"), wantErr: "form a cycle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := workflowOrder(tt.steps)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("workflowOrder() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("workflowOrder() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("workflowOrder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,506 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// netconfBaseNamespace is the namespace of the NETCONF base protocol, holding the edit-config operation attribute.
+const netconfBaseNamespace = "urn:ietf:params:xml:ns:netconf:base:1.0"
+
+// xmlNode is an XML element, as relevant to compare configurations: its qualified name, its children,
+// or its trimmed text when it has none, and the edit-config operation it carries, if any.
+type xmlNode struct {
+	name      xml.Name
+	operation string
+	text      string
+	children  []*xmlNode
+}
+
+// parseXML parses an XML fragment, possibly made of several root elements. Comments and processing
+// instructions are ignored.
+func parseXML(data string) ([]*xmlNode, error) {
+	decoder := xml.NewDecoder(strings.NewReader(data))
+	var roots []*xmlNode
+	var stack []*xmlNode
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: t.Name}
+			for _, attr := range t.Attr {
+				if attr.Name.Space == netconfBaseNamespace && attr.Name.Local == "operation" {
+					node.operation = attr.Value
+				}
+			}
+			if len(stack) == 0 {
+				roots = append(roots, node)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			}
+			stack = append(stack, node)
+			text.Reset()
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			node := stack[len(stack)-1]
+			if len(node.children) == 0 {
+				node.text = strings.TrimSpace(text.String())
+			}
+			stack = stack[:len(stack)-1]
+			text.Reset()
+		}
+	}
+	if len(stack) != 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return roots, nil
+}
+
+func (n *xmlNode) isLeaf() bool {
+	return len(n.children) == 0
+}
+
+// isRemoval reports whether the node requests its removal, through the `delete` or `remove` operation.
+func (n *xmlNode) isRemoval() bool {
+	return n.operation == "delete" || n.operation == "remove"
+}
+
+// contains reports whether the actual node holds the desired one: the same name and, for a leaf, the same
+// text; otherwise, every desired child is held by one of the actual children, regardless of their order.
+// Children requesting their removal must not be held by any actual child. An empty desired leaf only
+// requires the presence of the actual node.
+func (n *xmlNode) contains(desired *xmlNode) bool {
+	if n.name != desired.name {
+		return false
+	}
+	if desired.isLeaf() {
+		return desired.text == "" || n.text == desired.text
+	}
+	for _, child := range desired.children {
+		if child.isRemoval() == n.holds(child) {
+			return false
+		}
+	}
+	return true
+}
+
+// holds reports whether any child of the node contains the desired one.
+func (n *xmlNode) holds(desired *xmlNode) bool {
+	for _, child := range n.children {
+		if child.contains(desired) {
+			return true
+		}
+	}
+	return false
+}
+
+// filter renders the node as a subtree filter selecting the data it defines: leaves are turned into selection
+// nodes, so that their actual value is returned whatever it is.
+func (n *xmlNode) filter(buf *bytes.Buffer, parentSpace string) {
+	buf.WriteString("<" + n.name.Local)
+	if n.name.Space != parentSpace {
+		buf.WriteString(` xmlns="`)
+		_ = xml.EscapeText(buf, []byte(n.name.Space))
+		buf.WriteString(`"`)
+	}
+	if n.isLeaf() {
+		buf.WriteString("/>")
+		return
+	}
+	buf.WriteString(">")
+	for _, child := range n.children {
+		child.filter(buf, n.name.Space)
+	}
+	buf.WriteString("</" + n.name.Local + ">")
+}
+
+// subtreeFilter renders the nodes as a subtree filter selecting the data they define.
+func subtreeFilter(nodes []*xmlNode) string {
+	var buf bytes.Buffer
+	for _, node := range nodes {
+		node.filter(&buf, "")
+	}
+	return buf.String()
+}
+
+// diffXML compares the desired nodes with the actual ones, and returns the differences, one per line:
+// the desired elements missing or holding another value, and the elements present though their removal is
+// requested. Each line starts with the path of the element, list entries being identified by their keys.
+func diffXML(path string, desired []*xmlNode, actual []*xmlNode, ns *yangNamespaces) []string {
+	var diff []string
+	for _, d := range desired {
+		var candidates []*xmlNode
+		for _, a := range actual {
+			if a.name == d.name {
+				candidates = append(candidates, a)
+			}
+		}
+		match := matchingNode(d, candidates, ns)
+		nodePath := path + "/" + d.pathElement(len(candidates) > 1 || match == nil, ns)
+
+		held := match != nil && match.contains(d)
+		if d.isRemoval() {
+			if held {
+				diff = append(diff, fmt.Sprintf("%s: present, expected to be removed", nodePath))
+			}
+			continue
+		}
+		if held {
+			continue
+		}
+
+		switch {
+		case match == nil:
+			diff = append(diff, fmt.Sprintf("%s: missing", nodePath))
+		case d.isLeaf():
+			diff = append(diff, fmt.Sprintf("%s: expected %q, found %q", nodePath, d.text, match.text))
+		default:
+			diff = append(diff, diffXML(nodePath, d.children, match.children, ns)...)
+		}
+	}
+	return diff
+}
+
+// matchingNode returns the candidate the desired node stands for, if any. A list entry stands for the candidate
+// holding the same keys, so that a new entry is never mistaken for another one. A leaf among several candidates,
+// i.e. a leaf-list entry, stands for the candidate holding the same value. Any other node stands for its only
+// candidate, or for the candidate containing it.
+func matchingNode(desired *xmlNode, candidates []*xmlNode, ns *yangNamespaces) *xmlNode {
+	if keys := desired.keys(ns); keys != nil {
+		for _, candidate := range candidates {
+			if candidate.hasKeys(keys) {
+				return candidate
+			}
+		}
+		return nil
+	}
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	for _, candidate := range candidates {
+		if candidate.contains(desired) {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// keys returns the leaves identifying the node as a list entry: the keys of its list, by qualified list name, when
+// the YANG mapping defines them, or its first leaf, as YANG list keys come first. A list defined without keys,
+// e.g. `example:system: []`, declares a container. Nil is returned when the node isn't a list entry, or misses one
+// of its keys.
+func (n *xmlNode) keys(ns *yangNamespaces) []*xmlNode {
+	if n.isLeaf() {
+		return nil
+	}
+	if ns != nil {
+		if names, ok := ns.listKeys[ns.modules[n.name.Space]+":"+n.name.Local]; ok {
+			var keys []*xmlNode
+			for _, name := range names {
+				key := n.child(name)
+				if key == nil || !key.isLeaf() {
+					return nil
+				}
+				keys = append(keys, &xmlNode{name: key.name, text: key.text})
+			}
+			return keys
+		}
+	}
+	if !n.children[0].isLeaf() || n.children[0].text == "" {
+		return nil
+	}
+	return []*xmlNode{{name: n.children[0].name, text: n.children[0].text}}
+}
+
+// hasKeys reports whether the node holds every key leaf, with the same value.
+func (n *xmlNode) hasKeys(keys []*xmlNode) bool {
+	for _, key := range keys {
+		child := n.child(key.name.Local)
+		if child == nil || child.name != key.name || !child.isLeaf() || child.text != key.text {
+			return false
+		}
+	}
+	return !n.isLeaf()
+}
+
+// child returns the first child of the node with the local name, if any.
+func (n *xmlNode) child(local string) *xmlNode {
+	for _, child := range n.children {
+		if child.name.Local == local {
+			return child
+		}
+	}
+	return nil
+}
+
+// pathElement returns the local name of the node, along with its keys as predicates when it is a list entry, e.g.
+// `interface[name='eth0']`.
+func (n *xmlNode) pathElement(listEntry bool, ns *yangNamespaces) string {
+	element := n.name.Local
+	if listEntry {
+		for _, key := range n.keys(ns) {
+			element += fmt.Sprintf("[%s='%s']", key.name.Local, key.text)
+		}
+	}
+	return element
+}
+
+// dataNodes returns the content of the data element of a get-config, or get, rpc-reply.
+func dataNodes(reply string) ([]*xmlNode, error) {
+	nodes, err := parseXML(reply)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes {
+		if node.name.Local == "data" {
+			return node.children, nil
+		}
+		for _, child := range node.children {
+			if child.name.Local == "data" {
+				return child.children, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// dataXML returns the content of the data element of a get-config, or get, rpc-reply, as sent by the NETCONF server.
+func dataXML(reply string) (string, error) {
+	var rpcReply struct {
+		Data struct {
+			Content string `xml:",innerxml"`
+		} `xml:"data"`
+	}
+	if err := xml.Unmarshal([]byte(reply), &rpcReply); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(rpcReply.Data.Content), nil
+}
+
+// introducedNodes returns the desired nodes missing from the actual ones, as requests for their removal,
+// within their ancestors. List entries are identified by their keys: an entry whose keys no actual entry holds is
+// introduced.
+func introducedNodes(desired []*xmlNode, actual []*xmlNode, ns *yangNamespaces) []*xmlNode {
+	var introduced []*xmlNode
+	for _, d := range desired {
+		if d.isRemoval() {
+			continue
+		}
+		var candidates []*xmlNode
+		for _, a := range actual {
+			if a.name == d.name {
+				candidates = append(candidates, a)
+			}
+		}
+
+		match := matchingNode(d, candidates, ns)
+		if match == nil {
+			introduced = append(introduced, &xmlNode{name: d.name, operation: "remove", children: d.keys(ns)})
+			continue
+		}
+		if d.isLeaf() {
+			continue
+		}
+
+		children := introducedNodes(d.children, match.children, ns)
+		if len(children) == 0 {
+			continue
+		}
+		introduced = append(introduced, &xmlNode{name: d.name, children: append(d.keys(ns), children...)})
+	}
+	return introduced
+}
+
+// mergeNodes adds the extra nodes missing from the base ones. The base nodes are kept as they are.
+func mergeNodes(base []*xmlNode, extra []*xmlNode, ns *yangNamespaces) []*xmlNode {
+	merged := append([]*xmlNode{}, base...)
+	for _, e := range extra {
+		var candidates []*xmlNode
+		for _, b := range base {
+			if b.name == e.name {
+				candidates = append(candidates, b)
+			}
+		}
+
+		match := matchingNode(e, candidates, ns)
+		switch {
+		case match == nil:
+			merged = append(merged, e)
+		case !match.isLeaf() && !e.isLeaf():
+			match.children = mergeNodes(match.children, e.children, ns)
+		}
+	}
+	return merged
+}
+
+// render writes the node as XML, along with its edit-config operation, if any.
+func (n *xmlNode) render(buf *bytes.Buffer, parentSpace string) {
+	buf.WriteString("<" + n.name.Local)
+	if n.name.Space != parentSpace {
+		buf.WriteString(` xmlns="`)
+		_ = xml.EscapeText(buf, []byte(n.name.Space))
+		buf.WriteString(`"`)
+	}
+	if n.operation != "" {
+		buf.WriteString(` xmlns:nc="` + netconfBaseNamespace + `" nc:operation="`)
+		_ = xml.EscapeText(buf, []byte(n.operation))
+		buf.WriteString(`"`)
+	}
+	buf.WriteString(">")
+	if n.isLeaf() {
+		_ = xml.EscapeText(buf, []byte(n.text))
+	}
+	for _, child := range n.children {
+		child.render(buf, n.name.Space)
+	}
+	buf.WriteString("</" + n.name.Local + ">")
+}
+
+// renderXML writes the nodes as an XML fragment.
+func renderXML(nodes []*xmlNode) string {
+	var buf bytes.Buffer
+	for _, node := range nodes {
+		node.render(&buf, "")
+	}
+	return buf.String()
+}
+
+// clone returns a deep copy of the node, without its edit-config operation. Children requesting their removal
+// are left out.
+func (n *xmlNode) clone() *xmlNode {
+	c := &xmlNode{name: n.name, text: n.text}
+	for _, child := range n.children {
+		if !child.isRemoval() {
+			c.children = append(c.children, child.clone())
+		}
+	}
+	return c
+}
+
+// editNodes returns the configuration resulting from the edit of the base nodes, the base nodes being left
+// untouched. Each edited element is merged, replaced or removed, according to its operation, or the default
+// one. As the NETCONF server would, a missing element is created, unless its removal is requested.
+func editNodes(base []*xmlNode, edit []*xmlNode, operation string, ns *yangNamespaces) []*xmlNode {
+	var edited []*xmlNode
+	for _, b := range base {
+		edited = append(edited, b.clone())
+	}
+	for _, e := range edit {
+		op := operation
+		if e.operation != "" {
+			op = e.operation
+		}
+		var candidates []*xmlNode
+		for _, n := range edited {
+			if n.name == e.name {
+				candidates = append(candidates, n)
+			}
+		}
+
+		match := matchingNode(e, candidates, ns)
+		switch {
+		case e.isRemoval():
+			if match != nil {
+				edited = removeNode(edited, match)
+			}
+		case match == nil:
+			edited = append(edited, e.clone())
+		case op == "replace" || e.isLeaf():
+			*match = *e.clone()
+		default:
+			match.children = editNodes(match.children, e.children, op, ns)
+		}
+	}
+	return edited
+}
+
+func removeNode(nodes []*xmlNode, node *xmlNode) []*xmlNode {
+	var kept []*xmlNode
+	for _, n := range nodes {
+		if n != node {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
+
+// changesXML compares the configuration before and after an edit, and returns the changes, one per line: `+`
+// for an added element, `-` for a removed one, and `~` for a leaf holding another value. The leaves of an added,
+// or removed, element are listed along with it. Each line then holds the path of the element, list entries being
+// identified by their keys.
+func changesXML(path string, before []*xmlNode, after []*xmlNode, ns *yangNamespaces) []string {
+	var changes []string
+	matched := make(map[*xmlNode]bool)
+	for _, a := range after {
+		var candidates []*xmlNode
+		for _, b := range before {
+			if b.name == a.name && !matched[b] {
+				candidates = append(candidates, b)
+			}
+		}
+		nodePath := path + "/" + a.pathElement(len(candidates) > 1 || countNodes(after, a.name) > 1, ns)
+
+		b := matchingNode(a, candidates, ns)
+		switch {
+		case b == nil:
+			changes = append(changes, leafChanges("+", nodePath, a, ns)...)
+			continue
+		case a.isLeaf() && b.isLeaf():
+			if a.text != b.text {
+				changes = append(changes, fmt.Sprintf("~ %s: %q -> %q", nodePath, b.text, a.text))
+			}
+		case a.isLeaf() || b.isLeaf():
+			changes = append(changes, leafChanges("-", nodePath, b, ns)...)
+			changes = append(changes, leafChanges("+", nodePath, a, ns)...)
+		default:
+			changes = append(changes, changesXML(nodePath, b.children, a.children, ns)...)
+		}
+		matched[b] = true
+	}
+	for _, b := range before {
+		if !matched[b] {
+			nodePath := path + "/" + b.pathElement(countNodes(before, b.name) > 1, ns)
+			changes = append(changes, leafChanges("-", nodePath, b, ns)...)
+		}
+	}
+	return changes
+}
+
+// leafChanges lists the leaves of the added, or removed, node, along with their value.
+func leafChanges(change string, path string, n *xmlNode, ns *yangNamespaces) []string {
+	if n.isLeaf() {
+		if n.text == "" {
+			return []string{fmt.Sprintf("%s %s", change, path)}
+		}
+		return []string{fmt.Sprintf("%s %s: %q", change, path, n.text)}
+	}
+	var changes []string
+	for _, child := range n.children {
+		childPath := path + "/" + child.pathElement(countNodes(n.children, child.name) > 1, ns)
+		changes = append(changes, leafChanges(change, childPath, child, ns)...)
+	}
+	return changes
+}
+
+func countNodes(nodes []*xmlNode, name xml.Name) int {
+	count := 0
+	for _, n := range nodes {
+		if n.name == name {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,273 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+const (
+	testInterfacesNamespace = "urn:ietf:params:xml:ns:yang:ietf-interfaces"
+	testSystemNamespace     = "urn:ietf:params:xml:ns:yang:ietf-system"
+)
+
+func mustParseXML(t *testing.T, data string) []*xmlNode {
+	t.Helper()
+	nodes, err := parseXML(data)
+	if err != nil {
+		t.Fatalf("parseXML(%q): %s", data, err)
+	}
+	return nodes
+}
+
+// interfaces renders the interfaces, each being the inner XML of an interface entry.
+func interfaces(entries ...string) string {
+	data := `<interfaces xmlns="` + testInterfacesNamespace + `">`
+	for _, entry := range entries {
+		data += "<interface>" + entry + "</interface>"
+	}
+	return data + "</interfaces>"
+}
+
+// testNamespaces maps the ietf-interfaces and ietf-system modules, defining the keys of their lists.
+func testNamespaces(listKeys map[string][]string) *yangNamespaces {
+	return newYANGNamespaces(
+		[]string{
+			testInterfacesNamespace + "?module=ietf-interfaces&revision=2018-02-20",
+			testSystemNamespace + "?module=ietf-system&revision=2014-08-06",
+		},
+		&netconfv1.YANGMapping{ListKeys: listKeys},
+	)
+}
+
+func TestDiffXML(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired string
+		actual  string
+		ns      *yangNamespaces
+		want    []string
+	}{
+		{
+			name:    "in sync",
+			desired: interfaces("<name>eth0</name><mtu>1500</mtu>"),
+			actual:  interfaces("<name>eth0</name><mtu>1500</mtu><enabled>true</enabled>"),
+		},
+		{
+			name:    "changed entry",
+			desired: interfaces("<name>eth0</name><mtu>9000</mtu>"),
+			actual:  interfaces("<name>eth0</name><mtu>1500</mtu>"),
+			want:    []string{`/interfaces/interface/mtu: expected "9000", found "1500"`},
+		},
+		{
+			name:    "missing entry, beside another one",
+			desired: interfaces("<name>eth1</name><mtu>1500</mtu>"),
+			actual:  interfaces("<name>eth0</name><mtu>1500</mtu>"),
+			want:    []string{"/interfaces/interface[name='eth1']: missing"},
+		},
+		{
+			name:    "missing entry, among others",
+			desired: interfaces("<name>eth0</name>", "<name>eth2</name><mtu>1500</mtu>"),
+			actual:  interfaces("<name>eth0</name>", "<name>eth1</name><mtu>1500</mtu>"),
+			want:    []string{"/interfaces/interface[name='eth2']: missing"},
+		},
+		{
+			name:    "changed entry, among others",
+			desired: interfaces("<name>eth1</name><mtu>9000</mtu>"),
+			actual:  interfaces("<name>eth0</name><mtu>9000</mtu>", "<name>eth1</name><mtu>1500</mtu>"),
+			want:    []string{`/interfaces/interface[name='eth1']/mtu: expected "9000", found "1500"`},
+		},
+		{
+			name:    "extra entries",
+			desired: interfaces("<name>eth1</name><mtu>1500</mtu>"),
+			actual: interfaces(
+				"<name>eth0</name><mtu>1500</mtu>", "<name>eth1</name><mtu>1500</mtu>", "<name>eth2</name>",
+			),
+		},
+		{
+			name: "entry present, expected to be removed",
+			desired: `<interfaces xmlns="` + testInterfacesNamespace + `" xmlns:nc="` + netconfBaseNamespace + `">` +
+				`<interface nc:operation="delete"><name>eth1</name></interface></interfaces>`,
+			actual: interfaces("<name>eth0</name>", "<name>eth1</name>"),
+			want:   []string{"/interfaces/interface[name='eth1']: present, expected to be removed"},
+		},
+		{
+			name: "entry removed",
+			desired: `<interfaces xmlns="` + testInterfacesNamespace + `" xmlns:nc="` + netconfBaseNamespace + `">` +
+				`<interface nc:operation="delete"><name>eth1</name></interface></interfaces>`,
+			actual: interfaces("<name>eth0</name>"),
+		},
+		{
+			name:    "composite keys",
+			desired: interfaces("<type>ethernet</type><name>eth0</name><mtu>9000</mtu>"),
+			actual: interfaces(
+				"<type>ethernet</type><name>eth1</name><mtu>1500</mtu>",
+				"<type>ethernet</type><name>eth0</name><mtu>1500</mtu>",
+			),
+			ns:   testNamespaces(map[string][]string{"ietf-interfaces:interface": {"name", "type"}}),
+			want: []string{`/interfaces/interface[name='eth0'][type='ethernet']/mtu: expected "9000", found "1500"`},
+		},
+		{
+			name:    "container, declared without keys",
+			desired: `<system xmlns="` + testSystemNamespace + `"><hostname>router2</hostname></system>`,
+			actual:  `<system xmlns="` + testSystemNamespace + `"><hostname>router1</hostname></system>`,
+			ns:      testNamespaces(map[string][]string{"ietf-system:system": {}}),
+			want:    []string{`/system/hostname: expected "router2", found "router1"`},
+		},
+		{
+			name:    "leaf-list",
+			desired: `<dns xmlns="` + testSystemNamespace + `"><server>10.0.0.2</server></dns>`,
+			actual:  `<dns xmlns="` + testSystemNamespace + `"><server>10.0.0.1</server><server>10.0.0.3</server></dns>`,
+			ns:      testNamespaces(map[string][]string{"ietf-system:dns": {}}),
+			want:    []string{"/dns/server: missing"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffXML("", mustParseXML(t, tt.desired), mustParseXML(t, tt.actual), tt.ns)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffXML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntroducedNodes(t *testing.T) {
+	// removal renders the removal of the interface entry, holding its keys
+	removal := func(keys string) string {
+		return `<interfaces xmlns="` + testInterfacesNamespace + `"><interface xmlns:nc="` + netconfBaseNamespace +
+			`" nc:operation="remove">` + keys + `</interface></interfaces>`
+	}
+	tests := []struct {
+		name    string
+		desired string
+		actual  string
+		ns      *yangNamespaces
+		want    string
+	}{
+		{
+			name:    "nothing introduced",
+			desired: interfaces("<name>eth0</name><mtu>9000</mtu>"),
+			actual:  interfaces("<name>eth0</name><mtu>1500</mtu>"),
+		},
+		{
+			name:    "entry introduced, beside another one",
+			desired: interfaces("<name>eth1</name><mtu>1500</mtu>"),
+			actual:  interfaces("<name>eth0</name><mtu>1500</mtu>"),
+			want:    removal("<name>eth1</name>"),
+		},
+		{
+			name:    "entry introduced, among others",
+			desired: interfaces("<name>eth1</name>", "<name>eth2</name><mtu>1500</mtu>"),
+			actual:  interfaces("<name>eth0</name><mtu>1500</mtu>", "<name>eth1</name><mtu>1500</mtu>"),
+			want:    removal("<name>eth2</name>"),
+		},
+		{
+			name:    "leaf introduced within an entry",
+			desired: interfaces("<name>eth1</name><description>uplink</description>"),
+			actual:  interfaces("<name>eth0</name><description>core</description>", "<name>eth1</name>"),
+			want: `<interfaces xmlns="` + testInterfacesNamespace + `"><interface><name>eth1</name>` +
+				`<description xmlns:nc="` + netconfBaseNamespace + `" nc:operation="remove"></description>` +
+				`</interface></interfaces>`,
+		},
+		{
+			name:    "entry introduced, identified by composite keys",
+			desired: interfaces("<type>ethernet</type><name>eth1</name>"),
+			actual:  interfaces("<type>ethernet</type><name>eth0</name>"),
+			ns:      testNamespaces(map[string][]string{"ietf-interfaces:interface": {"name", "type"}}),
+			want:    removal("<name>eth1</name><type>ethernet</type>"),
+		},
+		{
+			name:    "whole tree introduced",
+			desired: interfaces("<name>eth0</name>"),
+			want: `<interfaces xmlns="` + testInterfacesNamespace + `" xmlns:nc="` + netconfBaseNamespace +
+				`" nc:operation="remove"></interfaces>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderXML(introducedNodes(mustParseXML(t, tt.desired), mustParseXML(t, tt.actual), tt.ns))
+			if got != tt.want {
+				t.Errorf("introducedNodes() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeNodes(t *testing.T) {
+	tests := []struct {
+		name  string
+		base  string
+		extra string
+		want  string
+	}{
+		{
+			name:  "entry kept as it is",
+			base:  interfaces("<name>eth0</name><mtu>1500</mtu>"),
+			extra: interfaces("<name>eth0</name><mtu>9000</mtu>"),
+			want:  interfaces("<name>eth0</name><mtu>1500</mtu>"),
+		},
+		{
+			name:  "entry added, beside another one",
+			base:  interfaces("<name>eth0</name><mtu>1500</mtu>"),
+			extra: interfaces("<name>eth1</name><mtu>9000</mtu>"),
+			want:  interfaces("<name>eth0</name><mtu>1500</mtu>", "<name>eth1</name><mtu>9000</mtu>"),
+		},
+		{
+			name:  "leaf added to the entry with the same key",
+			base:  interfaces("<name>eth0</name><mtu>1500</mtu>", "<name>eth1</name>"),
+			extra: interfaces("<name>eth1</name><mtu>1500</mtu>"),
+			want:  interfaces("<name>eth0</name><mtu>1500</mtu>", "<name>eth1</name><mtu>1500</mtu>"),
+		},
+		{
+			name:  "tree added",
+			extra: interfaces("<name>eth0</name>"),
+			want:  interfaces("<name>eth0</name>"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderXML(mergeNodes(mustParseXML(t, tt.base), mustParseXML(t, tt.extra), nil))
+			if got != tt.want {
+				t.Errorf("mergeNodes() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangesXML(t *testing.T) {
+	before := interfaces("<name>eth0</name><mtu>1500</mtu>")
+	tests := []struct {
+		name  string
+		after string
+		want  []string
+	}{
+		{
+			name:  "leaf changed",
+			after: interfaces("<name>eth0</name><mtu>9000</mtu>"),
+			want:  []string{`~ /interfaces/interface/mtu: "1500" -> "9000"`},
+		},
+		{
+			name:  "entry added",
+			after: interfaces("<name>eth0</name><mtu>1500</mtu>", "<name>eth1</name>"),
+			want:  []string{`+ /interfaces/interface[name='eth1']/name: "eth1"`},
+		},
+		{
+			name:  "entry replaced",
+			after: interfaces("<name>eth1</name><mtu>1500</mtu>"),
+			want: []string{
+				`+ /interfaces/interface/name: "eth1"`, `+ /interfaces/interface/mtu: "1500"`,
+				`- /interfaces/interface/name: "eth0"`, `- /interfaces/interface/mtu: "1500"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := changesXML("", mustParseXML(t, before), mustParseXML(t, tt.after), nil)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changesXML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
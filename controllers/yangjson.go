@@ -0,0 +1,478 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/openshift-telco/go-netconf-client/netconf/message"
+	"io"
+	"k8s.io/apimachinery/pkg/runtime"
+	"net/url"
+	"regexp"
+	"strings"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+// ietfNetconfModule is the module of the NETCONF base namespace, holding the operation metadata annotation. Servers
+// don't always advertise it.
+const ietfNetconfModule = "ietf-netconf"
+
+// identityPattern matches a value qualified by a module name, e.g. the identityref `iana-if-type:ethernetCsmacd`.
+var identityPattern = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_.-]*):([A-Za-z_][A-Za-z0-9_.-]*)$`)
+
+// yangNamespaces maps the YANG modules to their XML namespace, and back, to convert JSON-encoded YANG data (RFC 7951)
+// into XML, and back.
+type yangNamespaces struct {
+	namespaces map[string]string
+	modules    map[string]string
+	listKeys   map[string][]string
+}
+
+// newYANGNamespaces builds the mapping from the capabilities advertised by the NETCONF server, e.g.
+// `urn:ietf:params:xml:ns:yang:ietf-interfaces?module=ietf-interfaces&revision=2018-02-20`, complemented, or
+// overridden, by the user-supplied mapping.
+func newYANGNamespaces(capabilities []string, mapping *netconfv1.YANGMapping) *yangNamespaces {
+	ns := &yangNamespaces{
+		namespaces: map[string]string{ietfNetconfModule: netconfBaseNamespace},
+		modules:    map[string]string{netconfBaseNamespace: ietfNetconfModule},
+		listKeys:   map[string][]string{},
+	}
+	for _, capability := range capabilities {
+		parts := strings.SplitN(strings.TrimSpace(capability), "?", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		// The parameters parsed before an invalid one, if any, are returned
+		query, _ := url.ParseQuery(parts[1])
+		if query.Get("module") == "" {
+			continue
+		}
+		ns.add(query.Get("module"), parts[0])
+	}
+	if mapping != nil {
+		for module, namespace := range mapping.Namespaces {
+			ns.add(module, namespace)
+		}
+		for list, keys := range mapping.ListKeys {
+			ns.listKeys[list] = keys
+		}
+	}
+	return ns
+}
+
+func (ns *yangNamespaces) add(module string, namespace string) {
+	ns.namespaces[module] = namespace
+	ns.modules[namespace] = module
+}
+
+// namespacesFor returns the mapping of the YANG modules of the MountPoint.
+func namespacesFor(sessions SessionProvider, mountPoint string, mapping *netconfv1.YANGMapping) *yangNamespaces {
+	return newYANGNamespaces(sessions.Capabilities(mountPoint), mapping)
+}
+
+// jsonMember is a member of a JSON object, which order is kept.
+type jsonMember struct {
+	name  string
+	value interface{}
+}
+
+// decodeJSON decodes a JSON value, keeping the order of the members of the objects, as []jsonMember.
+func decodeJSON(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		members := []jsonMember{}
+		for decoder.More() {
+			name, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			members = append(members, jsonMember{name: name.(string), value: value})
+		}
+		_, err = decoder.Token()
+		return members, err
+	case json.Delim('['):
+		values := []interface{}{}
+		for decoder.More() {
+			value, err := decodeJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		_, err = decoder.Token()
+		return values, err
+	}
+	return token, nil
+}
+
+// jsonToXML converts JSON-encoded YANG data (RFC 7951) into XML. The top-level members are qualified by their module
+// name, e.g. `ietf-interfaces:interfaces`, as are the members from another module than their parent. An array holds
+// the entries of a list, or a leaf-list; `[null]`, or `null`, an empty leaf. The `ietf-netconf:operation` metadata
+// annotation (RFC 7952) sets the edit-config operation, e.g. `"@": {"ietf-netconf:operation": "delete"}`.
+func jsonToXML(raw *runtime.RawExtension, ns *yangNamespaces) (string, error) {
+	if raw == nil || len(raw.Raw) == 0 {
+		return "", fmt.Errorf("empty JSON payload")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw.Raw))
+	decoder.UseNumber()
+	value, err := decodeJSON(decoder)
+	if err != nil {
+		return "", fmt.Errorf("invalid JSON payload: %w", err)
+	}
+	members, ok := value.([]jsonMember)
+	if !ok {
+		return "", fmt.Errorf("invalid JSON payload: expecting an object")
+	}
+
+	var buf bytes.Buffer
+	err = ns.writeMembers(&buf, members, "", "", "")
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// writeMembers writes the members of the object as XML elements. The keys of the list, when the object is a list
+// entry, are written first.
+func (ns *yangNamespaces) writeMembers(
+	buf *bytes.Buffer, members []jsonMember, list string, parentModule string, parentSpace string,
+) error {
+	keys := ns.listKeys[list]
+	if keys == nil {
+		keys = []string{"name"}
+	}
+	var ordered []jsonMember
+	for _, key := range keys {
+		for _, member := range members {
+			if member.name == key {
+				ordered = append(ordered, member)
+			}
+		}
+	}
+	for _, member := range members {
+		if !containsString(keys, member.name) {
+			ordered = append(ordered, member)
+		}
+	}
+
+	annotations := make(map[string]interface{})
+	for _, member := range ordered {
+		if strings.HasPrefix(member.name, "@") {
+			annotations[member.name[1:]] = member.value
+		}
+	}
+	for _, member := range ordered {
+		if strings.HasPrefix(member.name, "@") {
+			continue
+		}
+		operation, err := editOperation(annotations[member.name])
+		if err != nil {
+			return fmt.Errorf("invalid annotation of %s: %w", member.name, err)
+		}
+		module, local := parentModule, member.name
+		if i := strings.Index(member.name, ":"); i >= 0 {
+			module, local = member.name[:i], member.name[i+1:]
+		}
+		if module == "" {
+			return fmt.Errorf("%s must be qualified by its module name, e.g. `module:%s`", member.name, member.name)
+		}
+		space, ok := ns.namespaces[module]
+		if !ok {
+			return fmt.Errorf(
+				"unknown YANG module %s, neither advertised by the NETCONF server nor in the YANG mapping", module,
+			)
+		}
+
+		values, isArray := member.value.([]interface{})
+		if !isArray {
+			values = []interface{}{member.value}
+		}
+		for _, value := range values {
+			err := ns.writeElement(buf, module, local, space, parentSpace, value, operation)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (ns *yangNamespaces) writeElement(
+	buf *bytes.Buffer, module string, local string, space string, parentSpace string, value interface{},
+	operation string,
+) error {
+	buf.WriteString("<" + local)
+	if space != parentSpace {
+		buf.WriteString(` xmlns="` + escapeXML(space) + `"`)
+	}
+	if operation != "" {
+		buf.WriteString(` xmlns:nc="` + netconfBaseNamespace + `" nc:operation="` + escapeXML(operation) + `"`)
+	}
+
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("/>")
+		return nil
+	case []jsonMember:
+		nested, err := editOperation(annotationOf(v))
+		if err != nil {
+			return fmt.Errorf("invalid annotation of %s: %w", local, err)
+		}
+		if nested != "" && operation == "" {
+			buf.WriteString(` xmlns:nc="` + netconfBaseNamespace + `" nc:operation="` + escapeXML(nested) + `"`)
+		}
+		buf.WriteString(">")
+		err = ns.writeMembers(buf, v, module+":"+local, module, space)
+		if err != nil {
+			return err
+		}
+	case []interface{}:
+		return fmt.Errorf("%s: nested arrays aren't supported", local)
+	case string:
+		// An identityref, or instance-identifier, value refers to its module by name, used as prefix.
+		if match := identityPattern.FindStringSubmatch(v); match != nil {
+			if prefixSpace, ok := ns.namespaces[match[1]]; ok {
+				buf.WriteString(` xmlns:` + match[1] + `="` + escapeXML(prefixSpace) + `"`)
+			}
+		}
+		buf.WriteString(">" + escapeXML(v))
+	default:
+		buf.WriteString(">" + escapeXML(fmt.Sprint(v)))
+	}
+	buf.WriteString("</" + local + ">")
+	return nil
+}
+
+// annotationOf returns the metadata annotation of the object itself, i.e. its `@` member.
+func annotationOf(members []jsonMember) interface{} {
+	for _, member := range members {
+		if member.name == "@" {
+			return member.value
+		}
+	}
+	return nil
+}
+
+// editOperation returns the edit-config operation set by the metadata annotation, the only one supported.
+func editOperation(annotation interface{}) (string, error) {
+	if annotation == nil {
+		return "", nil
+	}
+	members, ok := annotation.([]jsonMember)
+	if !ok {
+		return "", fmt.Errorf("expecting an object")
+	}
+	operation := ""
+	for _, member := range members {
+		if member.name != ietfNetconfModule+":operation" {
+			return "", fmt.Errorf("unsupported annotation %s", member.name)
+		}
+		value, ok := member.value.(string)
+		if !ok {
+			return "", fmt.Errorf("%s must be a string", member.name)
+		}
+		operation = value
+	}
+	return operation, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonNode is an XML element, as relevant to encode it in JSON: its namespace and local name, its children, or its
+// text when it has none. The prefix of an identityref value is resolved into its namespace, held by textSpace.
+type jsonNode struct {
+	space     string
+	local     string
+	text      string
+	textSpace string
+	children  []*jsonNode
+}
+
+// xmlToJSON converts the content of an rpc-reply into JSON-encoded YANG data (RFC 7951). The content of the data
+// element, if any, is converted. Without the YANG schema, a list holding a single entry is encoded as an object,
+// leaves are encoded as strings, and empty elements as `[null]`. An element from an unknown namespace is qualified
+// by its namespace.
+func xmlToJSON(data string, ns *yangNamespaces) (string, error) {
+	nodes, err := parseJSONNodes(data)
+	if err != nil {
+		return "", err
+	}
+	if len(nodes) == 1 && nodes[0].space == netconfBaseNamespace && nodes[0].local == "data" {
+		nodes = nodes[0].children
+	}
+
+	var buf bytes.Buffer
+	ns.writeObject(&buf, nodes, "")
+	var indented bytes.Buffer
+	err = json.Indent(&indented, buf.Bytes(), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return indented.String(), nil
+}
+
+func parseJSONNodes(data string) ([]*jsonNode, error) {
+	decoder := xml.NewDecoder(strings.NewReader(data))
+	var roots []*jsonNode
+	var stack []*jsonNode
+	// The namespaces of the prefixes declared by each open element
+	var prefixes []map[string]string
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &jsonNode{space: t.Name.Space, local: t.Name.Local}
+			declared := make(map[string]string)
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" {
+					declared[attr.Name.Local] = attr.Value
+				}
+			}
+			prefixes = append(prefixes, declared)
+			if len(stack) == 0 {
+				roots = append(roots, node)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			}
+			stack = append(stack, node)
+			text.Reset()
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			node := stack[len(stack)-1]
+			if len(node.children) == 0 {
+				node.text = strings.TrimSpace(text.String())
+				if match := identityPattern.FindStringSubmatch(node.text); match != nil {
+					for i := len(prefixes) - 1; i >= 0; i-- {
+						if space, ok := prefixes[i][match[1]]; ok {
+							node.text, node.textSpace = match[2], space
+							break
+						}
+					}
+				}
+			}
+			stack = stack[:len(stack)-1]
+			prefixes = prefixes[:len(prefixes)-1]
+			text.Reset()
+		}
+	}
+	if len(stack) != 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return roots, nil
+}
+
+// writeObject writes the nodes as the members of a JSON object, the siblings sharing their name as an array.
+func (ns *yangNamespaces) writeObject(buf *bytes.Buffer, nodes []*jsonNode, parentSpace string) {
+	buf.WriteString("{")
+	written := make(map[[2]string]bool)
+	first := true
+	for _, node := range nodes {
+		name := [2]string{node.space, node.local}
+		if written[name] {
+			continue
+		}
+		written[name] = true
+		var siblings []*jsonNode
+		for _, sibling := range nodes {
+			if sibling.space == node.space && sibling.local == node.local {
+				siblings = append(siblings, sibling)
+			}
+		}
+
+		if !first {
+			buf.WriteString(",")
+		}
+		first = false
+		member := node.local
+		if node.space != parentSpace {
+			member = ns.module(node.space) + ":" + node.local
+		}
+		writeJSONString(buf, member)
+		buf.WriteString(":")
+		if len(siblings) == 1 {
+			ns.writeValue(buf, node)
+			continue
+		}
+		buf.WriteString("[")
+		for i, sibling := range siblings {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			ns.writeValue(buf, sibling)
+		}
+		buf.WriteString("]")
+	}
+	buf.WriteString("}")
+}
+
+func (ns *yangNamespaces) writeValue(buf *bytes.Buffer, node *jsonNode) {
+	switch {
+	case len(node.children) > 0:
+		ns.writeObject(buf, node.children, node.space)
+	case node.text == "":
+		buf.WriteString("[null]")
+	case node.textSpace != "":
+		writeJSONString(buf, ns.module(node.textSpace)+":"+node.text)
+	default:
+		writeJSONString(buf, node.text)
+	}
+}
+
+// module returns the name of the module of the namespace, or the namespace itself when unknown.
+func (ns *yangNamespaces) module(space string) string {
+	if module, ok := ns.modules[space]; ok {
+		return module
+	}
+	return space
+}
+
+// setReplyJSON reports the data of the reply in JSON, when requested.
+func setReplyJSON(
+	status *netconfv1.RPCStatus, format netconfv1.ReplyFormat, reply *message.RPCReply, ns *yangNamespaces,
+) error {
+	status.RpcReplyJSON = ""
+	if format != netconfv1.JSONReplyFormat || reply == nil {
+		return nil
+	}
+	data, err := xmlToJSON(reply.Data, ns)
+	if err != nil {
+		return fmt.Errorf("failed to translate the reply into JSON: %w", err)
+	}
+	status.RpcReplyJSON = data
+	return nil
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(s)
+	buf.Write(bytes.TrimSpace(encoded.Bytes()))
+}
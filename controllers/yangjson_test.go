@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"strings"
+	"testing"
+
+	netconfv1 "github.com/openshift-telco/netconf-operator/api/v1"
+)
+
+const testIanaIfTypeNamespace = "urn:ietf:params:xml:ns:yang:iana-if-type"
+
+func TestNewYANGNamespaces(t *testing.T) {
+	ns := newYANGNamespaces(
+		[]string{
+			"urn:ietf:params:netconf:base:1.1",
+			testInterfacesNamespace + "?module=ietf-interfaces&revision=2018-02-20",
+			"urn:example:acl?revision=2020-01-01",
+			"urn:example:bad?module=bad&revision=%zz",
+			" urn:example:system?module=example-system ",
+		},
+		&netconfv1.YANGMapping{
+			Namespaces: map[string]string{"example-system": "urn:example:system:v2", "acme": "urn:acme"},
+		},
+	)
+	want := map[string]string{
+		ietfNetconfModule: netconfBaseNamespace,
+		"ietf-interfaces": testInterfacesNamespace,
+		"bad":             "urn:example:bad",
+		"example-system":  "urn:example:system:v2",
+		"acme":            "urn:acme",
+	}
+	for module, namespace := range want {
+		if got := ns.namespaces[module]; got != namespace {
+			t.Errorf("namespace of %s = %q, want %q", module, got, namespace)
+		}
+	}
+	if len(ns.namespaces) != len(want) {
+		t.Errorf("namespaces = %v, want %v", ns.namespaces, want)
+	}
+	if got := ns.module("urn:example:system:v2"); got != "example-system" {
+		t.Errorf("module() = %q, want example-system", got)
+	}
+	if got := ns.module("urn:unknown"); got != "urn:unknown" {
+		t.Errorf("module() = %q, want the namespace itself", got)
+	}
+}
+
+func TestJSONToXML(t *testing.T) {
+	ns := newYANGNamespaces(
+		[]string{
+			testInterfacesNamespace + "?module=ietf-interfaces&revision=2018-02-20",
+			testIanaIfTypeNamespace + "?module=iana-if-type&revision=2014-05-08",
+			"urn:example:ext?module=example-ext",
+		},
+		&netconfv1.YANGMapping{ListKeys: map[string][]string{"example-ext:entry": {"id", "type"}}},
+	)
+	interfacesXMLNS := ` xmlns="` + testInterfacesNamespace + `"`
+	operation := ` xmlns:nc="` + netconfBaseNamespace + `" nc:operation="delete"`
+	tests := []struct {
+		name    string
+		json    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "list",
+			json: `{"ietf-interfaces:interfaces": {"interface": [{"mtu": 1500, "name": "eth0"}, {"name": "eth1"}]}}`,
+			want: `<interfaces` + interfacesXMLNS + `><interface><name>eth0</name><mtu>1500</mtu></interface>` +
+				`<interface><name>eth1</name></interface></interfaces>`,
+		},
+		{
+			name: "keys defined by the YANG mapping",
+			json: `{"example-ext:entry": {"value": "v", "type": "t", "id": 1}}`,
+			want: `<entry xmlns="urn:example:ext"><id>1</id><type>t</type><value>v</value></entry>`,
+		},
+		{
+			name: "identityref",
+			json: `{"ietf-interfaces:interfaces": {"interface": {"name": "eth0", "type": "iana-if-type:ethernetCsmacd"}}}`,
+			want: `<interfaces` + interfacesXMLNS + `><interface><name>eth0</name><type xmlns:iana-if-type="` +
+				testIanaIfTypeNamespace + `">iana-if-type:ethernetCsmacd</type></interface></interfaces>`,
+		},
+		{
+			name: "member of another module",
+			json: `{"ietf-interfaces:interfaces": {"interface": {"name": "eth0", "example-ext:speed": "10G"}}}`,
+			want: `<interfaces` + interfacesXMLNS + `><interface><name>eth0</name>` +
+				`<speed xmlns="urn:example:ext">10G</speed></interface></interfaces>`,
+		},
+		{
+			name: "empty leaves",
+			json: `{"ietf-interfaces:interfaces": {"interface": {"name": "eth0", "enabled": [null], "x": null}}}`,
+			want: `<interfaces` + interfacesXMLNS + `><interface><name>eth0</name><enabled/><x/></interface></interfaces>`,
+		},
+		{
+			name: "operation of a member",
+			json: `{"ietf-interfaces:interfaces": {"interface": {"name": "eth0", "description": "d",` +
+				` "@description": {"ietf-netconf:operation": "delete"}}}}`,
+			want: `<interfaces` + interfacesXMLNS + `><interface><name>eth0</name><description` + operation +
+				`>d</description></interface></interfaces>`,
+		},
+		{
+			name: "operation of a list entry",
+			json: `{"ietf-interfaces:interfaces": {"interface": [{"name": "eth1",` +
+				` "@": {"ietf-netconf:operation": "delete"}}]}}`,
+			want: `<interfaces` + interfacesXMLNS + `><interface` + operation + `><name>eth1</name></interface>` +
+				`</interfaces>`,
+		},
+		{
+			name: "escaped value",
+			json: `{"ietf-interfaces:interfaces": {"interface": {"name": "a<b&c"}}}`,
+			want: `<interfaces` + interfacesXMLNS + `><interface><name>a&lt;b&amp;c</name></interface></interfaces>`,
+		},
+		{name: "unqualified top-level member", json: `{"interfaces": {}}`, wantErr: "must be qualified"},
+		{name: "unknown module", json: `{"unknown:interfaces": {}}`, wantErr: "unknown YANG module unknown"},
+		{name: "not an object", json: `["ietf-interfaces:interfaces"]`, wantErr: "expecting an object"},
+		{name: "malformed", json: `{"ietf-interfaces:interfaces": `, wantErr: "invalid JSON payload"},
+		{
+			name:    "nested arrays",
+			json:    `{"ietf-interfaces:interfaces": {"interface": [[{"name": "eth0"}]]}}`,
+			wantErr: "nested arrays",
+		},
+		{
+			name:    "unsupported annotation",
+			json:    `{"ietf-interfaces:interfaces": {"@": {"example-ext:origin": "intended"}}}`,
+			wantErr: "unsupported annotation",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonToXML(&runtime.RawExtension{Raw: []byte(tt.json)}, ns)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("jsonToXML() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("jsonToXML() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("jsonToXML() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestXMLToJSON(t *testing.T) {
+	ns := newYANGNamespaces(
+		[]string{
+			testInterfacesNamespace + "?module=ietf-interfaces&revision=2018-02-20",
+			testIanaIfTypeNamespace + "?module=iana-if-type&revision=2014-05-08",
+		},
+		nil,
+	)
+	tests := []struct {
+		name string
+		xml  string
+		want string
+	}{
+		{
+			name: "data of the reply",
+			xml: `<data xmlns="` + netconfBaseNamespace + `"><interfaces xmlns="` + testInterfacesNamespace + `">` +
+				`<interface><name>eth0</name></interface><interface><name>eth1</name></interface></interfaces></data>`,
+			want: `{"ietf-interfaces:interfaces":{"interface":[{"name":"eth0"},{"name":"eth1"}]}}`,
+		},
+		{
+			name: "single list entry",
+			xml:  `<interfaces xmlns="` + testInterfacesNamespace + `"><interface><name>eth0</name></interface></interfaces>`,
+			want: `{"ietf-interfaces:interfaces":{"interface":{"name":"eth0"}}}`,
+		},
+		{
+			name: "identityref and empty leaf",
+			xml: `<interfaces xmlns="` + testInterfacesNamespace + `" xmlns:ianaift="` + testIanaIfTypeNamespace +
+				`"><interface><type>ianaift:ethernetCsmacd</type><enabled/></interface></interfaces>`,
+			want: `{"ietf-interfaces:interfaces":{"interface":{"type":"iana-if-type:ethernetCsmacd","enabled":[null]}}}`,
+		},
+		{
+			name: "unknown namespace",
+			xml:  `<config xmlns="urn:unknown"><value>&lt;1&gt;</value></config>`,
+			want: `{"urn:unknown:config":{"value":"<1>"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := xmlToJSON(tt.xml, ns)
+			if err != nil {
+				t.Fatalf("xmlToJSON() error = %v", err)
+			}
+			if compact := strings.Join(strings.Fields(got), ""); compact != tt.want {
+				t.Errorf("xmlToJSON() = %s, want %s", compact, tt.want)
+			}
+		})
+	}
+}
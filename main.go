@@ -19,6 +19,8 @@ package main
 import (
 	"flag"
 	"os"
+	// Embed the time zone database, read by the ScheduledOperations
+	_ "time/tzdata"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -52,6 +54,7 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var callHome controllers.CallHomeOptions
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(
@@ -59,6 +62,27 @@ func main() {
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.",
 	)
+	flag.StringVar(
+		&callHome.SSHAddress, "call-home-ssh-bind-address", "",
+		"The address the NETCONF over SSH Call Home listener binds to, e.g. :4334. Disabled by default.",
+	)
+	flag.StringVar(
+		&callHome.TLSAddress, "call-home-tls-bind-address", "",
+		"The address the NETCONF over TLS Call Home listener binds to, e.g. :4335. Disabled by default.",
+	)
+	flag.StringVar(
+		&callHome.Namespace, "call-home-namespace", "netconf-operator-system",
+		"The namespace in which the devices calling home, pending approval, are recorded.",
+	)
+	flag.IntVar(
+		&callHome.MaxPendingDevices, "call-home-max-pending-devices", 100,
+		"The maximum number of devices calling home pending approval. Beyond, unknown devices are refused. "+
+			"0 for no limit.",
+	)
+	flag.StringVar(
+		&callHome.SSHUsername, "call-home-ssh-username", "netconf",
+		"The username initially used to authenticate against a device calling home over SSH.",
+	)
 	opts := zap.Options{
 		Development: true,
 	}
@@ -98,6 +122,51 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Unlock")
 	}
 
+	err = controllers.AddValidate(mgr)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Validate")
+	}
+
+	err = controllers.AddDiscardChanges(mgr)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DiscardChanges")
+	}
+
+	err = controllers.AddCopyConfig(mgr)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CopyConfig")
+	}
+
+	err = controllers.AddDeleteConfig(mgr)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DeleteConfig")
+	}
+
+	err = controllers.AddConfigBackup(mgr)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ConfigBackup")
+	}
+
+	err = controllers.AddConfigRestore(mgr)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ConfigRestore")
+	}
+
+	err = controllers.AddWorkflow(mgr)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Workflow")
+	}
+
+	err = controllers.AddNetworkTransaction(mgr)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NetworkTransaction")
+	}
+
+	err = controllers.AddScheduledOperation(mgr)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ScheduledOperation")
+	}
+
 	err = controllers.AddEditConfig(mgr)
 	if err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "EditConfig")
@@ -133,6 +202,11 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "CreateSubscription")
 	}
 
+	err = controllers.AddCallHome(mgr, callHome)
+	if err != nil {
+		setupLog.Error(err, "unable to create listener", "listener", "CallHome")
+	}
+
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {